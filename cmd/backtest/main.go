@@ -0,0 +1,207 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+
+	"github.com/imbonda/vmm-bot/internal/trader"
+	"github.com/imbonda/vmm-bot/pkg/exchanges/paper"
+	"github.com/imbonda/vmm-bot/pkg/models"
+	"github.com/imbonda/vmm-bot/pkg/utils"
+)
+
+const (
+	backtestDepth  = "1000000000"
+	defaultBalance = 1e12
+)
+
+var backtestPair = models.Pair{Base: "BACKTEST", Quote: "USDT"}
+
+type Configuration struct {
+	Inputs            []string
+	CandleHeight      float64
+	SpreadMarginLower float64
+	SpreadMarginUpper float64
+	TradeAmountMin    float64
+	TradeAmountMax    float64
+	PriceDecimals     int
+	AmountDecimals    int
+	MakerFee          float64
+	TakerFee          float64
+	BaseBalance       float64
+	QuoteBalance      float64
+	Inventory         *trader.InventoryConfig
+	Step              time.Duration
+	JSON              bool
+	Verbose           bool
+}
+
+func parseFlags() (*Configuration, error) {
+	cfg := &Configuration{}
+	var input string
+	flag.StringVar(&input, "input", "", "recorded tickers file (.csv or .jsonl), more files may be passed as arguments")
+	flag.Float64Var(&cfg.CandleHeight, "candle-height", 0.01, "same as CANDLE_HEIGHT")
+	flag.Float64Var(&cfg.SpreadMarginLower, "spread-margin-lower", 0, "same as SPREAD_MARGIN_LOWER")
+	flag.Float64Var(&cfg.SpreadMarginUpper, "spread-margin-upper", 1, "same as SPREAD_MARGIN_UPPER")
+	flag.Float64Var(&cfg.TradeAmountMin, "trade-amount-min", 1, "same as TRADE_AMOUNT_MIN")
+	flag.Float64Var(&cfg.TradeAmountMax, "trade-amount-max", 1, "same as TRADE_AMOUNT_MAX")
+	flag.IntVar(&cfg.PriceDecimals, "price-decimals", 3, "same as PRICE_DECIMALS_PRECISION")
+	flag.IntVar(&cfg.AmountDecimals, "amount-decimals", 2, "same as AMOUNT_DECIMALS_PRECISION")
+	flag.Float64Var(&cfg.MakerFee, "maker-fee", 0.001, "simulated maker fee rate")
+	flag.Float64Var(&cfg.TakerFee, "taker-fee", 0.001, "simulated taker fee rate")
+	flag.Float64Var(&cfg.BaseBalance, "base-balance", defaultBalance, "simulated initial base balance")
+	flag.Float64Var(&cfg.QuoteBalance, "quote-balance", defaultBalance, "simulated initial quote balance")
+	inventory := &trader.InventoryConfig{}
+	inventorySkew := flag.Bool("inventory-skew", false, "same as INVENTORY_SKEW_ENABLED")
+	flag.Float64Var(&inventory.TargetRatio, "inventory-target-ratio", 0.5, "same as INVENTORY_TARGET_RATIO")
+	flag.Float64Var(&inventory.MinRatio, "inventory-min-ratio", 0.2, "same as INVENTORY_MIN_RATIO")
+	flag.Float64Var(&inventory.MaxRatio, "inventory-max-ratio", 0.8, "same as INVENTORY_MAX_RATIO")
+	flag.Float64Var(&inventory.PriceSkew, "inventory-price-skew", 0.5, "same as INVENTORY_PRICE_SKEW")
+	flag.Float64Var(&inventory.QtySkew, "inventory-qty-skew", 0.5, "same as INVENTORY_QTY_SKEW")
+	flag.DurationVar(&cfg.Step, "step", 0, "minimal recorded time between two iterations, 0 runs an iteration on every venue ticker")
+	flag.BoolVar(&cfg.JSON, "json", false, "print the report as JSON")
+	flag.BoolVar(&cfg.Verbose, "verbose", false, "log every iteration")
+	flag.Parse()
+
+	if *inventorySkew {
+		cfg.Inventory = inventory
+	}
+
+	if input != "" {
+		cfg.Inputs = append(cfg.Inputs, input)
+	}
+	cfg.Inputs = append(cfg.Inputs, flag.Args()...)
+	if len(cfg.Inputs) == 0 {
+		return nil, fmt.Errorf("no input files, pass -input or file arguments")
+	}
+	return cfg, nil
+}
+
+func main() {
+	cfg, err := parseFlags()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	logger := log.NewNopLogger()
+	if cfg.Verbose {
+		logger = log.With(
+			log.NewLogfmtLogger(log.NewSyncWriter(os.Stderr)),
+			"name", "backtest",
+		)
+	}
+
+	result, err := run(context.Background(), cfg, logger)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if cfg.JSON {
+		err = result.writeJSON(os.Stdout)
+	} else {
+		err = result.writeText(os.Stdout)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// run replays the recorded tickers and drives a trader iteration on every venue ticker,
+// once an oracle ticker was replayed as well.
+func run(ctx context.Context, cfg *Configuration, logger log.Logger) (*report, error) {
+	snapshots, err := readSnapshots(cfg.Inputs)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &models.SymbolInfo{
+		Status:   models.SymbolStatusTrading,
+		TickSize: utils.FormatFloatToString(utils.DecimalsStep(cfg.PriceDecimals), -1),
+		LotStep:  utils.FormatFloatToString(utils.DecimalsStep(cfg.AmountDecimals), -1),
+	}
+	venueFeed := &replayFeed{depth: backtestDepth, info: info}
+	oracleFeed := &replayFeed{depth: backtestDepth, info: info}
+	backtestSymbol := backtestPair.String()
+	exchangeClient, err := paper.NewClient(ctx, &paper.NewClientInput{
+		Markets:      map[string]paper.Market{backtestSymbol: {Client: venueFeed, Symbol: backtestSymbol}},
+		BaseBalance:  cfg.BaseBalance,
+		QuoteBalance: cfg.QuoteBalance,
+		MakerFee:     cfg.MakerFee,
+		TakerFee:     cfg.TakerFee,
+		Logger:       logger,
+	})
+	if err != nil {
+		return nil, err
+	}
+	vmmTrader, err := trader.NewTrader(ctx, &trader.NewTraderInput{
+		ExchangeClient:    exchangeClient,
+		PriceOracleClient: oracleFeed,
+		Pair:              backtestPair,
+		Symbol:            backtestSymbol,
+		OracleSymbol:      backtestSymbol,
+		CandleHeight:      cfg.CandleHeight,
+		SpreadMarginLower: cfg.SpreadMarginLower,
+		SpreadMarginUpper: cfg.SpreadMarginUpper,
+		TradeAmountMin:    cfg.TradeAmountMin,
+		TradeAmountMax:    cfg.TradeAmountMax,
+		PriceDecimals:     cfg.PriceDecimals,
+		AmountDecimals:    cfg.AmountDecimals,
+		Inventory:         cfg.Inventory,
+		Logger:            logger,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := newReport()
+	var lastIteration time.Time
+	for _, snapshot := range snapshots {
+		switch snapshot.Source {
+		case models.OracleSource:
+			oracleFeed.ticker = snapshot.Ticker
+			continue
+		case models.VenueSource:
+			venueFeed.ticker = snapshot.Ticker
+		default:
+			return nil, fmt.Errorf("unknown ticker source: %s", snapshot.Source)
+		}
+		if oracleFeed.ticker == nil {
+			continue
+		}
+		if cfg.Step > 0 && !lastIteration.IsZero() && snapshot.Time.Sub(lastIteration) < cfg.Step {
+			continue
+		}
+		lastIteration = snapshot.Time
+
+		result.Iterations++
+		output, err := vmmTrader.TradeOnce(ctx)
+		rangeErr := errors.Is(err, trader.ErrUnexpectedPriceRange)
+		if err != nil {
+			level.Warn(logger).Log("msg", "iteration failed", "ts", snapshot.Time, "err", err)
+			if rangeErr {
+				result.PriceRangeErrors++
+			} else {
+				result.Errors++
+			}
+		}
+		if output != nil {
+			result.addPricing(output.Pricing, rangeErr)
+		}
+	}
+
+	state, err := exchangeClient.GetState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result.addPaperState(state)
+	result.finalize()
+	return result, nil
+}
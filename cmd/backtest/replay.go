@@ -0,0 +1,185 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/imbonda/vmm-bot/pkg/models"
+)
+
+// replayFeed serves the latest replayed ticker of a single source as read-only market data.
+type replayFeed struct {
+	ticker *models.Ticker
+	depth  string
+	// info holds the trading rules, recordings don't include them.
+	info *models.SymbolInfo
+}
+
+func (f *replayFeed) GetOrderBook(_ context.Context, symbol string) (*models.OrderBook, error) {
+	if f.ticker == nil {
+		return nil, fmt.Errorf("no ticker replayed yet")
+	}
+	return &models.OrderBook{
+		Symbol: symbol,
+		Asks:   [][]string{{f.ticker.BestAsk, f.depth}},
+		Bids:   [][]string{{f.ticker.BestBid, f.depth}},
+	}, nil
+}
+
+func (f *replayFeed) GetLastTicker(_ context.Context, symbol string) (*models.Ticker, error) {
+	if f.ticker == nil {
+		return nil, fmt.Errorf("no ticker replayed yet")
+	}
+	ticker := *f.ticker
+	ticker.Symbol = symbol
+	return &ticker, nil
+}
+
+func (f *replayFeed) PlaceOrder(_ context.Context, _ *models.Order) (*models.OrderRecord, error) {
+	return nil, fmt.Errorf("replay feed is read only")
+}
+
+func (f *replayFeed) GetOrder(_ context.Context, _ string, _ string) (*models.OrderRecord, error) {
+	return nil, fmt.Errorf("replay feed is read only")
+}
+
+func (f *replayFeed) GetOrderByClientID(_ context.Context, _ string, _ string) (*models.OrderRecord, error) {
+	return nil, fmt.Errorf("replay feed is read only")
+}
+
+func (f *replayFeed) ListOpenOrders(_ context.Context, _ string) ([]*models.OrderRecord, error) {
+	return nil, nil
+}
+
+func (f *replayFeed) CancelOrder(_ context.Context, _ string, _ string) error {
+	return fmt.Errorf("replay feed is read only")
+}
+
+func (f *replayFeed) CancelAllOrders(_ context.Context, _ string) error {
+	return nil
+}
+
+func (f *replayFeed) GetFills(_ context.Context, _ string, _ time.Time) ([]*models.Fill, error) {
+	return nil, nil
+}
+
+func (f *replayFeed) GetSymbolInfo(_ context.Context, symbol string) (*models.SymbolInfo, error) {
+	info := *f.info
+	info.Symbol = symbol
+	return &info, nil
+}
+
+func (f *replayFeed) GetBalances(_ context.Context) ([]*models.Balance, error) {
+	return nil, nil
+}
+
+// readSnapshots loads the recorded tickers of all files, ordered by time.
+func readSnapshots(paths []string) ([]models.TickerSnapshot, error) {
+	var snapshots []models.TickerSnapshot
+	for _, path := range paths {
+		fileSnapshots, err := readSnapshotsFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed reading %s: %w", path, err)
+		}
+		snapshots = append(snapshots, fileSnapshots...)
+	}
+	slices.SortStableFunc(snapshots, func(a, b models.TickerSnapshot) int {
+		return a.Time.Compare(b.Time)
+	})
+	return snapshots, nil
+}
+
+func readSnapshotsFile(path string) ([]models.TickerSnapshot, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return readCSV(file)
+	case ".jsonl", ".json", ".ndjson":
+		return readJSONL(file)
+	default:
+		return nil, fmt.Errorf("unsupported file format, expected .csv or .jsonl")
+	}
+}
+
+// readJSONL reads one models.TickerSnapshot per line, the format written by the market data recorder.
+func readJSONL(reader io.Reader) ([]models.TickerSnapshot, error) {
+	var snapshots []models.TickerSnapshot
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		raw := strings.TrimSpace(scanner.Text())
+		if raw == "" {
+			continue
+		}
+		var snapshot models.TickerSnapshot
+		if err := json.Unmarshal([]byte(raw), &snapshot); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		// Order book snapshots may be recorded into the same files, they carry no ticker.
+		if snapshot.Ticker == nil {
+			continue
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots, scanner.Err()
+}
+
+// readCSV reads rows of "ts,source,symbol,lastPrice,ask,bid" with a header line.
+// The timestamp is either RFC3339 or unix epoch milliseconds.
+func readCSV(reader io.Reader) ([]models.TickerSnapshot, error) {
+	rows, err := csv.NewReader(reader).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) < 1 {
+		return nil, nil
+	}
+	columns := make(map[string]int)
+	for i, name := range rows[0] {
+		columns[strings.TrimSpace(name)] = i
+	}
+	for _, name := range []string{"ts", "source", "symbol", "lastPrice", "ask", "bid"} {
+		if _, found := columns[name]; !found {
+			return nil, fmt.Errorf("missing csv column: %s", name)
+		}
+	}
+	snapshots := make([]models.TickerSnapshot, 0, len(rows)-1)
+	for i, row := range rows[1:] {
+		ts, err := parseTime(row[columns["ts"]])
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", i+2, err)
+		}
+		snapshots = append(snapshots, models.TickerSnapshot{
+			Time:   ts,
+			Source: models.MarketDataSource(row[columns["source"]]),
+			Ticker: &models.Ticker{
+				Symbol:    row[columns["symbol"]],
+				LastPrice: row[columns["lastPrice"]],
+				BestAsk:   row[columns["ask"]],
+				BestBid:   row[columns["bid"]],
+			},
+		})
+	}
+	return snapshots, nil
+}
+
+func parseTime(value string) (time.Time, error) {
+	if millis, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.UnixMilli(millis), nil
+	}
+	return time.Parse(time.RFC3339Nano, value)
+}
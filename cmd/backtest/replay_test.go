@@ -0,0 +1,69 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/imbonda/vmm-bot/pkg/models"
+)
+
+func TestReadCSV(t *testing.T) {
+	snapshots, err := readCSV(strings.NewReader(strings.Join([]string{
+		"ts,source,symbol,lastPrice,ask,bid",
+		"1700000000000,venue,STOPUSDT,0.5,0.51,0.49",
+		"2023-11-14T22:13:21.5Z,oracle,STOPUSDT,0.6,0.61,0.59",
+	}, "\n")))
+	require.NoError(t, err)
+	require.Len(t, snapshots, 2)
+	assert.Equal(t, time.UnixMilli(1700000000000), snapshots[0].Time)
+	assert.Equal(t, models.VenueSource, snapshots[0].Source)
+	assert.Equal(t, &models.Ticker{Symbol: "STOPUSDT", LastPrice: "0.5", BestAsk: "0.51", BestBid: "0.49"}, snapshots[0].Ticker)
+	assert.Equal(t, time.Date(2023, 11, 14, 22, 13, 21, 5e8, time.UTC), snapshots[1].Time)
+	assert.Equal(t, models.OracleSource, snapshots[1].Source)
+
+	_, err = readCSV(strings.NewReader("ts,source,symbol,lastPrice,ask\n1,venue,STOPUSDT,0.5,0.51"))
+	assert.ErrorContains(t, err, "missing csv column: bid")
+	_, err = readCSV(strings.NewReader("ts,source,symbol,lastPrice,ask,bid\nyesterday,venue,STOPUSDT,0.5,0.51,0.49"))
+	assert.ErrorContains(t, err, "row 2")
+}
+
+func TestReadJSONL(t *testing.T) {
+	snapshots, err := readJSONL(strings.NewReader(strings.Join([]string{
+		`{"ts":"2023-11-14T22:13:20Z","source":"venue","ticker":{"symbol":"STOPUSDT","lastPrice":"0.5","ask":"0.51","bid":"0.49"}}`,
+		``,
+		`{"ts":"2023-11-14T22:13:21Z","source":"venue","orderBook":{"symbol":"STOPUSDT"}}`,
+		`{"ts":"2023-11-14T22:13:22Z","source":"oracle","ticker":{"symbol":"STOPUSDT","lastPrice":"0.6","ask":"0.61","bid":"0.59"}}`,
+	}, "\n")))
+	require.NoError(t, err)
+	require.Len(t, snapshots, 2)
+	assert.Equal(t, "0.5", snapshots[0].Ticker.LastPrice)
+	assert.Equal(t, models.OracleSource, snapshots[1].Source)
+
+	_, err = readJSONL(strings.NewReader("{}\nnot json"))
+	assert.ErrorContains(t, err, "line 2")
+}
+
+func TestReadSnapshotsOrdersFilesByTime(t *testing.T) {
+	dir := t.TempDir()
+	csvPath := filepath.Join(dir, "venue.csv")
+	jsonlPath := filepath.Join(dir, "oracle.jsonl")
+	require.NoError(t, os.WriteFile(csvPath, []byte("ts,source,symbol,lastPrice,ask,bid\n3000,venue,X,1,1,1\n1000,venue,X,1,1,1\n"), 0o600))
+	require.NoError(t, os.WriteFile(jsonlPath, []byte(`{"ts":"1970-01-01T00:00:02Z","source":"oracle","ticker":{"lastPrice":"1"}}`+"\n"), 0o600))
+
+	snapshots, err := readSnapshots([]string{csvPath, jsonlPath})
+	require.NoError(t, err)
+	require.Len(t, snapshots, 3)
+	for i, source := range []models.MarketDataSource{models.VenueSource, models.OracleSource, models.VenueSource} {
+		assert.Equal(t, source, snapshots[i].Source)
+		assert.Equal(t, int64(i+1)*1000, snapshots[i].Time.UnixMilli())
+	}
+
+	_, err = readSnapshots([]string{filepath.Join(dir, "tickers.txt")})
+	assert.Error(t, err)
+}
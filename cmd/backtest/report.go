@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"text/tabwriter"
+
+	"github.com/imbonda/vmm-bot/pkg/models"
+)
+
+type report struct {
+	Iterations        int                        `json:"iterations"`
+	Errors            int                        `json:"errors"`
+	PriceRangeErrors  int                        `json:"priceRangeErrors"`
+	PriceRangeErrRate float64                    `json:"priceRangeErrorRate"`
+	Branches          map[models.PriceBranch]int `json:"branches"`
+	RangeErrBranches  map[models.PriceBranch]int `json:"priceRangeErrorBranches"`
+	CrossedBooks      int                        `json:"crossedBooks"`
+	OutsideSpread     int                        `json:"outsideSpread"`
+	OutsideSpreadRate float64                    `json:"outsideSpreadRate"`
+	MeanOracleDrift   float64                    `json:"meanOracleDrift"`
+	MeanAbsDrift      float64                    `json:"meanAbsOracleDrift"`
+	MaxAbsDrift       float64                    `json:"maxAbsOracleDrift"`
+	Fills             int                        `json:"fills"`
+	FilledVolume      float64                    `json:"filledVolume"`
+	FeesPaid          float64                    `json:"feesPaid"`
+	priced            int
+	driftSum          float64
+	absDriftSum       float64
+}
+
+func newReport() *report {
+	return &report{
+		Branches:         make(map[models.PriceBranch]int),
+		RangeErrBranches: make(map[models.PriceBranch]int),
+	}
+}
+
+func (r *report) addPricing(pricing *models.PriceDecision, rangeErr bool) {
+	if pricing == nil {
+		return
+	}
+	if pricing.Crossed {
+		r.CrossedBooks++
+	}
+	if rangeErr {
+		r.RangeErrBranches[pricing.Branch]++
+		return
+	}
+	r.Branches[pricing.Branch]++
+	r.priced++
+	if pricing.Crossed || pricing.Price < pricing.Bid || pricing.Price > pricing.Ask {
+		r.OutsideSpread++
+	}
+	if pricing.OraclePrice != 0 {
+		drift := (pricing.Price - pricing.OraclePrice) / pricing.OraclePrice
+		r.driftSum += drift
+		r.absDriftSum += math.Abs(drift)
+		r.MaxAbsDrift = math.Max(r.MaxAbsDrift, math.Abs(drift))
+	}
+}
+
+// addPaperState counts the fills of the accounts, the fills of the state are only the latest ones.
+func (r *report) addPaperState(state *models.PaperState) {
+	for _, account := range state.Accounts {
+		r.Fills += account.Fills
+		r.FilledVolume += account.FilledVolume
+		r.FeesPaid += account.FeesPaid
+	}
+}
+
+func (r *report) finalize() {
+	if r.Iterations > 0 {
+		r.PriceRangeErrRate = float64(r.PriceRangeErrors) / float64(r.Iterations)
+	}
+	if r.priced > 0 {
+		r.OutsideSpreadRate = float64(r.OutsideSpread) / float64(r.priced)
+		r.MeanOracleDrift = r.driftSum / float64(r.priced)
+		r.MeanAbsDrift = r.absDriftSum / float64(r.priced)
+	}
+}
+
+func (r *report) writeJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+func (r *report) writeText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "iterations\t%d\n", r.Iterations)
+	fmt.Fprintf(tw, "errors\t%d\n", r.Errors)
+	fmt.Fprintf(tw, "min > max errors\t%d (%.2f%%)\n", r.PriceRangeErrors, r.PriceRangeErrRate*100)
+	fmt.Fprintf(tw, "crossed books\t%d\n", r.CrossedBooks)
+	fmt.Fprintf(tw, "outside spread\t%d (%.2f%%)\n", r.OutsideSpread, r.OutsideSpreadRate*100)
+	fmt.Fprintf(tw, "mean oracle drift\t%.4f%%\n", r.MeanOracleDrift*100)
+	fmt.Fprintf(tw, "mean abs oracle drift\t%.4f%%\n", r.MeanAbsDrift*100)
+	fmt.Fprintf(tw, "max abs oracle drift\t%.4f%%\n", r.MaxAbsDrift*100)
+	fmt.Fprintf(tw, "fills\t%d\n", r.Fills)
+	fmt.Fprintf(tw, "filled volume\t%f\n", r.FilledVolume)
+	fmt.Fprintf(tw, "fees paid\t%f\n", r.FeesPaid)
+	fmt.Fprintf(tw, "\nbranch\tcount\tmin > max\n")
+	branches := make([]models.PriceBranch, 0, len(r.Branches)+len(r.RangeErrBranches))
+	for branch := range r.Branches {
+		branches = append(branches, branch)
+	}
+	for branch := range r.RangeErrBranches {
+		if _, found := r.Branches[branch]; !found {
+			branches = append(branches, branch)
+		}
+	}
+	slices.Sort(branches)
+	for _, branch := range branches {
+		fmt.Fprintf(tw, "%s\t%d\t%d\n", branch, r.Branches[branch], r.RangeErrBranches[branch])
+	}
+	return tw.Flush()
+}
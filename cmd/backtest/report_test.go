@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/imbonda/vmm-bot/pkg/models"
+)
+
+func TestReportPricing(t *testing.T) {
+	result := newReport()
+	result.Iterations = 4
+	result.PriceRangeErrors = 1
+	result.addPricing(&models.PriceDecision{Branch: models.BranchSpread, Price: 101, OraclePrice: 100, Ask: 102, Bid: 98}, false)
+	result.addPricing(&models.PriceDecision{Branch: models.BranchSpread, Price: 97, OraclePrice: 100, Ask: 102, Bid: 98}, false)
+	result.addPricing(&models.PriceDecision{Branch: models.BranchOracleRangeInSpread, Price: 100, Crossed: true}, false)
+	result.addPricing(&models.PriceDecision{Branch: models.BranchCandleRangeInSpread, Crossed: true}, true)
+	result.addPricing(nil, false)
+	result.finalize()
+
+	assert.Equal(t, map[models.PriceBranch]int{models.BranchSpread: 2, models.BranchOracleRangeInSpread: 1}, result.Branches)
+	assert.Equal(t, map[models.PriceBranch]int{models.BranchCandleRangeInSpread: 1}, result.RangeErrBranches)
+	assert.Equal(t, 2, result.CrossedBooks)
+	assert.Equal(t, 0.25, result.PriceRangeErrRate)
+	// The price below the bid and the crossed book are outside the spread.
+	assert.Equal(t, 2, result.OutsideSpread)
+	assert.InDelta(t, 2.0/3, result.OutsideSpreadRate, 1e-9)
+	// The drift of the decision without an oracle price isn't counted, but it's still averaged over the priced ones.
+	assert.InDelta(t, (0.01-0.03)/3, result.MeanOracleDrift, 1e-9)
+	assert.InDelta(t, (0.01+0.03)/3, result.MeanAbsDrift, 1e-9)
+	assert.InDelta(t, 0.03, result.MaxAbsDrift, 1e-9)
+}
+
+func TestReportCountsEveryFill(t *testing.T) {
+	var lines []string
+	for i := range 700 {
+		for _, source := range []string{"oracle", "venue"} {
+			lines = append(lines, fmt.Sprintf(
+				`{"ts":"%s","source":"%s","ticker":{"lastPrice":"100","ask":"100.1","bid":"99.9"}}`,
+				time.Unix(int64(i), 0).UTC().Format("2006-01-02T15:04:05Z"), source,
+			))
+		}
+	}
+	path := filepath.Join(t.TempDir(), "tickers.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0o600))
+
+	result, err := run(context.Background(), &Configuration{
+		Inputs:            []string{path},
+		CandleHeight:      0.01,
+		SpreadMarginUpper: 1,
+		TradeAmountMin:    1,
+		TradeAmountMax:    1,
+		PriceDecimals:     3,
+		AmountDecimals:    2,
+		BaseBalance:       defaultBalance,
+		QuoteBalance:      defaultBalance,
+		MakerFee:          0.001,
+		TakerFee:          0.001,
+	}, log.NewNopLogger())
+	require.NoError(t, err)
+	assert.Equal(t, 700, result.Iterations)
+	assert.Zero(t, result.Errors)
+	// The iterations fill about both of their sides, past the 1000 fills kept by the paper exchange.
+	assert.Greater(t, result.Fills, 1300)
+	assert.InDelta(t, float64(result.Fills), result.FilledVolume, 1e-6)
+	assert.InDelta(t, float64(result.Fills)*100*0.001, result.FeesPaid, 1)
+}
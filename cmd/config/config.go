@@ -4,82 +4,267 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
 	"github.com/kelseyhightower/envconfig"
+	"github.com/samber/lo"
 
 	"github.com/imbonda/vmm-bot/cmd/interfaces"
+	"github.com/imbonda/vmm-bot/cmd/service/metrics"
+	"github.com/imbonda/vmm-bot/internal/marketdata"
 	"github.com/imbonda/vmm-bot/pkg/exchanges"
 	"github.com/imbonda/vmm-bot/pkg/exchanges/biconomy"
 	"github.com/imbonda/vmm-bot/pkg/exchanges/bingx"
 	"github.com/imbonda/vmm-bot/pkg/exchanges/bybit"
+	"github.com/imbonda/vmm-bot/pkg/exchanges/paper"
+	"github.com/imbonda/vmm-bot/pkg/exchanges/ratelimit"
+	"github.com/imbonda/vmm-bot/pkg/exchanges/resilience"
+	"github.com/imbonda/vmm-bot/pkg/exchanges/stream"
+	"github.com/imbonda/vmm-bot/pkg/models"
 	"github.com/imbonda/vmm-bot/pkg/utils"
 )
 
 type ServiceConfig struct {
-	Name              string              `default:"trader" envconfig:"SERVICE_NAME"`
-	Orchestration     utils.Orchestration `default:"executor" envconfig:"SERVICE_ORCHESTRATION"`
-	GracefulShutdown time.Duration       `default:"5s" envconfig:"GRACEFUL_SHUTDOWN"`
+	Name             string              `file:"name" default:"trader" envconfig:"SERVICE_NAME"`
+	Orchestration    utils.Orchestration `file:"orchestration" default:"executor" envconfig:"SERVICE_ORCHESTRATION"`
+	GracefulShutdown time.Duration       `file:"gracefulShutdown" default:"5s" envconfig:"GRACEFUL_SHUTDOWN"`
+	// ConfigWatchInterval is how often the config file is checked for changes, zero disables the hot reload.
+	ConfigWatchInterval time.Duration `file:"configWatchInterval" default:"5s" envconfig:"CONFIG_WATCH_INTERVAL"`
+	// RiskStateFile keeps the halted pairs and their daily volume and fees across restarts, see GetRiskStateFile.
+	RiskStateFile string `file:"riskStateFile" envconfig:"RISK_STATE_FILE"`
+	// Pairs names the traded pairs, see PairConfig.
+	Pairs []string `envconfig:"PAIRS"`
 }
 
 type ExecutorConfig struct {
-	IntervalExecutionDuration      time.Duration `default:"60s" envconfig:"INTERVAL_EXECUTION_DURATION"`
-	NumOfTradeIterationsInInterval int           `default:"2" envconfig:"NUM_OF_TRADE_ITERATIONS_IN_INTERVAL"`
-	ListenAddress                  string        `default:":8080" envconfig:"LISTEN_ADDRESS"`
+	IntervalExecutionDuration      time.Duration `file:"intervalExecutionDuration" default:"60s" envconfig:"INTERVAL_EXECUTION_DURATION"`
+	NumOfTradeIterationsInInterval int           `file:"numOfTradeIterationsInInterval" default:"2" envconfig:"NUM_OF_TRADE_ITERATIONS_IN_INTERVAL"`
+	ListenAddress                  string        `file:"listenAddress" default:":8080" envconfig:"LISTEN_ADDRESS"`
+	// ControlEnabled serves the OpenAPI server next to the scheduler.
+	ControlEnabled bool `file:"controlEnabled" default:"false" envconfig:"CONTROL_ENABLED"`
 }
 
+// HealthConfig sets when the health and readiness probes fail, see the executor and OpenAPI services.
+type HealthConfig struct {
+	// MaxMissedIntervals is how many execution intervals a running pair may go without completing an iteration, zero disables the check.
+	MaxMissedIntervals int `file:"maxMissedIntervals" default:"3" envconfig:"HEALTH_MAX_MISSED_INTERVALS"`
+	// MaxConsecutiveFailures is how many requests in a row an exchange client may fail, zero disables the check.
+	MaxConsecutiveFailures int `file:"maxConsecutiveFailures" default:"5" envconfig:"HEALTH_MAX_CONSECUTIVE_FAILURES"`
+}
+
+// RateLimitConfig sets the client side rate limits of the exchange clients, see the RateLimitRules of the adapters.
+type RateLimitConfig struct {
+	Enabled bool `file:"enabled" default:"true" envconfig:"RATE_LIMIT_ENABLED"`
+	// Ratio is the share of the exchange limits the bot may use, leaving the rest to other clients of the same key or IP.
+	Ratio float64 `file:"ratio" default:"0.8" envconfig:"RATE_LIMIT_RATIO"`
+	// MaxWait is how long a request may wait for its budget before failing, zero fails it right away.
+	MaxWait time.Duration `file:"maxWait" default:"2s" envconfig:"RATE_LIMIT_MAX_WAIT"`
+}
+
+// ResilienceConfig sets the retries and the circuit breakers of the exchange clients, see the RetryRules of the adapters.
+type ResilienceConfig struct {
+	// MaxRetries is how many times a failed idempotent request is sent again, zero disables the retries.
+	MaxRetries int           `file:"maxRetries" default:"2" envconfig:"RETRY_MAX_RETRIES"`
+	BackoffMin time.Duration `file:"backoffMin" default:"200ms" envconfig:"RETRY_BACKOFF_MIN"`
+	BackoffMax time.Duration `file:"backoffMax" default:"2s" envconfig:"RETRY_BACKOFF_MAX"`
+	// BreakerThreshold is how many failed requests in a row open the circuit of an exchange, zero disables the breaker.
+	BreakerThreshold int           `file:"breakerThreshold" default:"5" envconfig:"CIRCUIT_BREAKER_THRESHOLD"`
+	BreakerCooldown  time.Duration `file:"breakerCooldown" default:"30s" envconfig:"CIRCUIT_BREAKER_COOLDOWN"`
+}
+
+type RecorderConfig struct {
+	Interval         time.Duration `file:"interval" default:"1s" envconfig:"RECORDER_INTERVAL"`
+	OutputDir        string        `file:"outputDir" default:"recordings" envconfig:"RECORDER_OUTPUT_DIR"`
+	FilePrefix       string        `file:"filePrefix" default:"market-data" envconfig:"RECORDER_FILE_PREFIX"`
+	RotationInterval time.Duration `file:"rotationInterval" default:"1h" envconfig:"RECORDER_ROTATION_INTERVAL"`
+	MaxFileSize      int64         `file:"maxFileSize" default:"104857600" envconfig:"RECORDER_MAX_FILE_SIZE"`
+	OrderBooks       bool          `file:"orderBooks" default:"true" envconfig:"RECORDER_ORDER_BOOKS"`
+}
+
+type StreamConfig struct {
+	Enabled        bool          `file:"enabled" default:"false" envconfig:"STREAM_ENABLED"`
+	PingInterval   time.Duration `file:"pingInterval" default:"5s" envconfig:"STREAM_PING_INTERVAL"`
+	StaleAfter     time.Duration `file:"staleAfter" default:"15s" envconfig:"STREAM_STALE_AFTER"`
+	ReconnectDelay time.Duration `file:"reconnectDelay" default:"1s" envconfig:"STREAM_RECONNECT_DELAY"`
+}
+
+// ExchangeConfig holds the settings of every exchange, the credentials are only required for the exchanges the pairs refer to.
 type ExchangeConfig struct {
-	Name   exchanges.Exchange `required:"1" envconfig:"EXCHANGE_NAME"`
-	Oracle exchanges.Exchange `required:"1" envconfig:"ORACLE_EXCHANGE_NAME"`
+	Name   exchanges.Exchange `file:"name" required:"1" envconfig:"EXCHANGE_NAME"`
+	Oracle exchanges.Exchange `file:"oracle" required:"1" envconfig:"ORACLE_EXCHANGE_NAME"`
 	Bybit  struct {
-		ExchangeAPIKey     string        `required:"1" envconfig:"BYBIT_API_KEY"`
-		ExchangeAPISecret  string        `required:"1" envconfig:"BYBIT_API_SECRET"`
-		ExchangeAPITimeout time.Duration `required:"1" envconfig:"BYBIT_API_TIMEOUT"`
+		ExchangeAPIURL     string        `file:"apiUrl" envconfig:"BYBIT_API_URL"`
+		ExchangeWSURL      string        `file:"wsUrl" envconfig:"BYBIT_WS_URL"`
+		ExchangeAPIKey     string        `file:"apiKey" envconfig:"BYBIT_API_KEY"`
+		ExchangeAPISecret  string        `file:"apiSecret" envconfig:"BYBIT_API_SECRET"`
+		ExchangeAPITimeout time.Duration `file:"apiTimeout" envconfig:"BYBIT_API_TIMEOUT"`
 		client             interfaces.ExchangeClient
-	}
+		failures           interfaces.FailureCounter
+		breaker            *resilience.Breaker
+	} `file:"bybit"`
 	Biconomy struct {
-		ExchangeAPIKey     string        `required:"1" envconfig:"BICONOMY_API_KEY"`
-		ExchangeAPISecret  string        `required:"1" envconfig:"BICONOMY_API_SECRET"`
-		ExchangeAPITimeout time.Duration `required:"1" envconfig:"BICONOMY_API_TIMEOUT"`
+		ExchangeAPIURL     string        `file:"apiUrl" envconfig:"BICONOMY_API_URL"`
+		ExchangeWSURL      string        `file:"wsUrl" envconfig:"BICONOMY_WS_URL"`
+		ExchangeAPIKey     string        `file:"apiKey" envconfig:"BICONOMY_API_KEY"`
+		ExchangeAPISecret  string        `file:"apiSecret" envconfig:"BICONOMY_API_SECRET"`
+		ExchangeAPITimeout time.Duration `file:"apiTimeout" envconfig:"BICONOMY_API_TIMEOUT"`
 		client             interfaces.ExchangeClient
-	}
+		failures           interfaces.FailureCounter
+		breaker            *resilience.Breaker
+	} `file:"biconomy"`
 	BingX struct {
-		ExchangeAPIKey     string        `required:"1" envconfig:"BINGX_API_KEY"`
-		ExchangeAPISecret  string        `required:"1" envconfig:"BINGX_API_SECRET"`
-		ExchangeAPITimeout time.Duration `required:"1" envconfig:"BINGX_API_TIMEOUT"`
+		ExchangeAPIURL     string        `file:"apiUrl" envconfig:"BINGX_API_URL"`
+		ExchangeWSURL      string        `file:"wsUrl" envconfig:"BINGX_WS_URL"`
+		ExchangeAPIKey     string        `file:"apiKey" envconfig:"BINGX_API_KEY"`
+		ExchangeAPISecret  string        `file:"apiSecret" envconfig:"BINGX_API_SECRET"`
+		ExchangeAPITimeout time.Duration `file:"apiTimeout" envconfig:"BINGX_API_TIMEOUT"`
 		client             interfaces.ExchangeClient
-	}
+		failures           interfaces.FailureCounter
+		breaker            *resilience.Breaker
+	} `file:"bingx"`
+	Paper struct {
+		BaseBalance  float64 `file:"baseBalance" default:"0" envconfig:"PAPER_BASE_BALANCE"`
+		QuoteBalance float64 `file:"quoteBalance" default:"0" envconfig:"PAPER_QUOTE_BALANCE"`
+		MakerFee     float64 `file:"makerFee" default:"0.001" envconfig:"PAPER_MAKER_FEE"`
+		TakerFee     float64 `file:"takerFee" default:"0.001" envconfig:"PAPER_TAKER_FEE"`
+		client       interfaces.ExchangeClient
+	} `file:"paper"`
 }
 
 type TradeConfig struct {
-	Symbol            string  `required:"1" envconfig:"SYMBOL"`
-	OracleSymbol      string  `required:"1" envconfig:"ORACLE_SYMBOL"`
-	CandleHeight      float64 `required:"1" envconfig:"CANDLE_HEIGHT"`
-	SpreadMarginLower float64 `default:"0" envconfig:"SPREAD_MARGIN_LOWER"`
-	SpreadMarginUpper float64 `default:"1" envconfig:"SPREAD_MARGIN_UPPER"`
-	TradeAmountMin    float64 `required:"1" envconfig:"TRADE_AMOUNT_MIN"`
-	TradeAmountMax    float64 `required:"1" envconfig:"TRADE_AMOUNT_MAX"`
-	PriceDecimals     int     `default:"3" envconfig:"PRICE_DECIMALS_PRECISION"`
-	AmountDecimals    int     `default:"2" envconfig:"AMOUNT_DECIMALS_PRECISION"`
+	Symbol            string  `file:"symbol" envconfig:"SYMBOL"`
+	OracleSymbol      string  `file:"oracleSymbol" envconfig:"ORACLE_SYMBOL"`
+	CandleHeight      float64 `file:"candleHeight" envconfig:"CANDLE_HEIGHT"`
+	SpreadMarginLower float64 `file:"spreadMarginLower" default:"0" envconfig:"SPREAD_MARGIN_LOWER"`
+	SpreadMarginUpper float64 `file:"spreadMarginUpper" default:"1" envconfig:"SPREAD_MARGIN_UPPER"`
+	TradeAmountMin    float64 `file:"tradeAmountMin" envconfig:"TRADE_AMOUNT_MIN"`
+	TradeAmountMax    float64 `file:"tradeAmountMax" envconfig:"TRADE_AMOUNT_MAX"`
+	PriceDecimals     int     `file:"priceDecimals" default:"3" envconfig:"PRICE_DECIMALS_PRECISION"`
+	AmountDecimals    int     `file:"amountDecimals" default:"2" envconfig:"AMOUNT_DECIMALS_PRECISION"`
+	// Placed orders, the time in force defaults by the order type.
+	OrderType        models.OrderType   `file:"orderType" default:"limit" envconfig:"ORDER_TYPE"`
+	OrderTimeInForce models.TimeInForce `file:"orderTimeInForce" envconfig:"ORDER_TIME_IN_FORCE"`
+	// Inventory skew.
+	InventorySkewEnabled bool    `file:"inventorySkewEnabled" default:"false" envconfig:"INVENTORY_SKEW_ENABLED"`
+	InventoryTargetRatio float64 `file:"inventoryTargetRatio" default:"0.5" envconfig:"INVENTORY_TARGET_RATIO"`
+	InventoryMinRatio    float64 `file:"inventoryMinRatio" default:"0.2" envconfig:"INVENTORY_MIN_RATIO"`
+	InventoryMaxRatio    float64 `file:"inventoryMaxRatio" default:"0.8" envconfig:"INVENTORY_MAX_RATIO"`
+	InventoryPriceSkew   float64 `file:"inventoryPriceSkew" default:"0.5" envconfig:"INVENTORY_PRICE_SKEW"`
+	InventoryQtySkew     float64 `file:"inventoryQtySkew" default:"0.5" envconfig:"INVENTORY_QTY_SKEW"`
+	// Composite oracle, the ORACLE_SOURCES are aggregated along with the oracle exchange and symbol when set.
+	OracleSources            []string               `file:"oracleSources" envconfig:"ORACLE_SOURCES"`
+	OracleAggregation        marketdata.Aggregation `file:"oracleAggregation" default:"median" envconfig:"ORACLE_AGGREGATION"`
+	OracleSourceMaxAge       time.Duration          `file:"oracleSourceMaxAge" default:"0" envconfig:"ORACLE_SOURCE_MAX_AGE"`
+	OracleSourceMaxDeviation float64                `file:"oracleSourceMaxDeviation" default:"0" envconfig:"ORACLE_SOURCE_MAX_DEVIATION"`
+	OracleMinSources         int                    `file:"oracleMinSources" default:"1" envconfig:"ORACLE_MIN_SOURCES"`
+	// Oracle check, zero isn't enforced.
+	OracleMaxDeviation float64       `file:"oracleMaxDeviation" default:"0" envconfig:"ORACLE_MAX_DEVIATION"`
+	OracleMaxTickerAge time.Duration `file:"oracleMaxTickerAge" default:"0" envconfig:"ORACLE_MAX_TICKER_AGE"`
+	OracleHaltEnabled  bool          `file:"oracleHaltEnabled" default:"false" envconfig:"ORACLE_HALT_ENABLED"`
+	// Risk limits, zero isn't enforced.
+	RiskMaxOrderNotional      float64 `file:"riskMaxOrderNotional" default:"0" envconfig:"RISK_MAX_ORDER_NOTIONAL"`
+	RiskMaxDailyVolume        float64 `file:"riskMaxDailyVolume" default:"0" envconfig:"RISK_MAX_DAILY_VOLUME"`
+	RiskMaxDailyFees          float64 `file:"riskMaxDailyFees" default:"0" envconfig:"RISK_MAX_DAILY_FEES"`
+	RiskMaxInventoryDeviation float64 `file:"riskMaxInventoryDeviation" default:"0" envconfig:"RISK_MAX_INVENTORY_DEVIATION"`
+	RiskMaxOracleDeviation    float64 `file:"riskMaxOracleDeviation" default:"0" envconfig:"RISK_MAX_ORACLE_DEVIATION"`
 }
 
 type LogConfig struct {
-	Level  string `default:"all" envconfig:"LOGGER_LEVEL"`
+	Level  string `file:"level" default:"all" envconfig:"LOGGER_LEVEL"`
 	logger log.Logger
 }
 
+// Configuration is read from the CONFIG_FILE (see LoadConfig) and the environment variables, which override the file.
 type Configuration struct {
-	Service  ServiceConfig
-	Executor ExecutorConfig
-	Exchange ExchangeConfig
-	Trade    TradeConfig
-	Log      LogConfig
+	Service    ServiceConfig    `file:"service"`
+	Executor   ExecutorConfig   `file:"executor"`
+	Health     HealthConfig     `file:"health"`
+	Recorder   RecorderConfig   `file:"recorder"`
+	Exchange   ExchangeConfig   `file:"exchange"`
+	RateLimit  RateLimitConfig  `file:"rateLimit"`
+	Resilience ResilienceConfig `file:"resilience"`
+	Stream     StreamConfig     `file:"stream"`
+	Trade      TradeConfig      `file:"trade"`
+	Log        LogConfig        `file:"log"`
+	pairs      []*PairConfig
 }
 
+// LoadConfig reads the config file named by CONFIG_FILE, if any, and the environment variables, validates the stream
+// settings, resolves the pairs and checks the credentials of the exchanges they trade on.
 func LoadConfig(cfg *Configuration) error {
-	return envconfig.Process("", cfg)
+	if path := os.Getenv(configFileEnv); path != "" {
+		if err := loadConfigFile(path); err != nil {
+			return fmt.Errorf("failed to load config file %s: %w", path, err)
+		}
+	}
+	if err := envconfig.Process("", cfg); err != nil {
+		return err
+	}
+	if err := cfg.validateStream(); err != nil {
+		return err
+	}
+	if err := cfg.resolvePairs(); err != nil {
+		return err
+	}
+	return cfg.validateCredentials()
+}
+
+// validateStream rejects the durations which would make the streams reconnect in a tight loop, or time out every read.
+func (cfg *Configuration) validateStream() error {
+	if cfg.Stream.StaleAfter <= 0 {
+		return fmt.Errorf("invalid STREAM_STALE_AFTER: %s, expected a positive duration", cfg.Stream.StaleAfter)
+	}
+	if cfg.Stream.ReconnectDelay <= 0 {
+		return fmt.Errorf("invalid STREAM_RECONNECT_DELAY: %s, expected a positive duration", cfg.Stream.ReconnectDelay)
+	}
+	return nil
+}
+
+// validateCredentials requires the credentials of the exchanges referenced by the pairs, either as traded exchange or as oracle.
+func (cfg *Configuration) validateCredentials() error {
+	referenced := []exchanges.Exchange{cfg.Exchange.Name, cfg.Exchange.Oracle}
+	for _, pair := range cfg.pairs {
+		referenced = append(referenced, pair.Exchange, pair.Oracle)
+		for _, source := range pair.oracleSources {
+			referenced = append(referenced, source.Exchange)
+		}
+	}
+	var missing []string
+	for _, name := range lo.Uniq(referenced) {
+		switch name {
+		case exchanges.Biconomy:
+			exchangeCfg := cfg.Exchange.Biconomy
+			missing = append(missing, missingCredentials("BICONOMY", exchangeCfg.ExchangeAPIKey, exchangeCfg.ExchangeAPISecret, exchangeCfg.ExchangeAPITimeout)...)
+		case exchanges.BingX:
+			exchangeCfg := cfg.Exchange.BingX
+			missing = append(missing, missingCredentials("BINGX", exchangeCfg.ExchangeAPIKey, exchangeCfg.ExchangeAPISecret, exchangeCfg.ExchangeAPITimeout)...)
+		case exchanges.Bybit:
+			exchangeCfg := cfg.Exchange.Bybit
+			missing = append(missing, missingCredentials("BYBIT", exchangeCfg.ExchangeAPIKey, exchangeCfg.ExchangeAPISecret, exchangeCfg.ExchangeAPITimeout)...)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing exchange credentials: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+func missingCredentials(prefix, apiKey, apiSecret string, apiTimeout time.Duration) []string {
+	var missing []string
+	if apiKey == "" {
+		missing = append(missing, prefix+"_API_KEY")
+	}
+	if apiSecret == "" {
+		missing = append(missing, prefix+"_API_SECRET")
+	}
+	if apiTimeout <= 0 {
+		missing = append(missing, prefix+"_API_TIMEOUT")
+	}
+	return missing
 }
 
 func (cfg *Configuration) GetLogger() log.Logger {
@@ -88,7 +273,6 @@ func (cfg *Configuration) GetLogger() log.Logger {
 			log.NewLogfmtLogger(log.NewSyncWriter(os.Stdout)),
 			"ts", log.DefaultTimestampUTC,
 			"name", cfg.Service.Name,
-			"symbol", cfg.Trade.Symbol,
 		)
 		var logLevel level.Option
 		switch cfg.Log.Level {
@@ -110,15 +294,30 @@ func (cfg *Configuration) GetLogger() log.Logger {
 	return cfg.Log.logger
 }
 
-func (cfg *Configuration) GetExchangeClient(ctx context.Context) (interfaces.ExchangeClient, error) {
-	return cfg.getExchangeClientByName(ctx, cfg.Exchange.Name)
+// GetRiskStateFile returns RISK_STATE_FILE, by default vmm-bot/risk-state.json in the state directory of the user,
+// $XDG_STATE_HOME or ~/.local/state, so that the state isn't written to the working directory.
+func (cfg *Configuration) GetRiskStateFile() (string, error) {
+	if cfg.Service.RiskStateFile != "" {
+		return cfg.Service.RiskStateFile, nil
+	}
+	stateDir := os.Getenv("XDG_STATE_HOME")
+	if stateDir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve the risk state directory, set RISK_STATE_FILE: %w", err)
+		}
+		stateDir = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(stateDir, "vmm-bot", "risk-state.json"), nil
 }
 
-func (cfg *Configuration) GetPriceOracleClient(ctx context.Context) (interfaces.ExchangeClient, error) {
-	return cfg.getExchangeClientByName(ctx, cfg.Exchange.Oracle)
+// GetPairLogger labels the logs of the pair with its name and symbol.
+func (cfg *Configuration) GetPairLogger(pair *PairConfig) log.Logger {
+	return log.With(cfg.GetLogger(), "pair", pair.Name, "symbol", pair.Symbol)
 }
 
-func (cfg *Configuration) getExchangeClientByName(ctx context.Context, name exchanges.Exchange) (interfaces.ExchangeClient, error) {
+// GetExchangeClient returns the client of the exchange, every pair trading on the exchange shares the same client.
+func (cfg *Configuration) GetExchangeClient(ctx context.Context, name exchanges.Exchange) (interfaces.ExchangeClient, error) {
 	switch name {
 	case exchanges.Biconomy:
 		return cfg.getBiconomyClient(ctx)
@@ -126,67 +325,315 @@ func (cfg *Configuration) getExchangeClientByName(ctx context.Context, name exch
 		return cfg.getBingXClient(ctx)
 	case exchanges.Bybit:
 		return cfg.getBybitClient(ctx)
+	case exchanges.Paper:
+		return cfg.getPaperClient(ctx)
+	default:
+		return nil, fmt.Errorf("failed to resolve exchange client: %s", name)
+	}
+}
+
+// GetPriceOracleClient returns the price oracle client of the pair, a composite oracle of its oracle exchange and
+// its ORACLE_SOURCES when it has any.
+func (cfg *Configuration) GetPriceOracleClient(ctx context.Context, pair *PairConfig) (interfaces.ExchangeClient, error) {
+	client, err := cfg.GetExchangeClient(ctx, pair.Oracle)
+	if err != nil || len(pair.oracleSources) == 0 {
+		return client, err
+	}
+	sources := []marketdata.OracleSource{{Name: string(pair.Oracle), Client: client, Symbol: pair.OracleSymbol}}
+	for _, source := range pair.oracleSources {
+		sourceClient, err := cfg.GetExchangeClient(ctx, source.Exchange)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, marketdata.OracleSource{Name: string(source.Exchange), Client: sourceClient, Symbol: source.Symbol})
+	}
+	oracle, err := marketdata.NewCompositeOracle(ctx, &marketdata.NewCompositeOracleInput{
+		Sources:      sources,
+		Aggregation:  pair.OracleAggregation,
+		MaxAge:       pair.OracleSourceMaxAge,
+		MaxDeviation: pair.OracleSourceMaxDeviation,
+		MinSources:   pair.OracleMinSources,
+		Logger:       cfg.GetPairLogger(pair),
+	})
+	if err != nil {
+		level.Error(cfg.GetLogger()).Log("msg", "failed to create composite oracle", "pair", pair.Name, "err", err)
+		return nil, err
+	}
+	return oracle, nil
+}
+
+// GetCircuitBreaker returns the circuit breaker of the client of the exchange, once created by GetExchangeClient,
+// nil when it has none.
+func (cfg *Configuration) GetCircuitBreaker(name exchanges.Exchange) *resilience.Breaker {
+	switch name {
+	case exchanges.Biconomy:
+		return cfg.Exchange.Biconomy.breaker
+	case exchanges.BingX:
+		return cfg.Exchange.BingX.breaker
+	case exchanges.Bybit:
+		return cfg.Exchange.Bybit.breaker
 	default:
-		return nil, fmt.Errorf("failed to resolve exchange client: %s", cfg.Exchange)
+		return nil
+	}
+}
+
+// GetOracleCircuitBreakers returns the circuit breakers of the clients of the price oracle of the pair, and of the
+// sources of its composite oracle, once created by GetPriceOracleClient.
+func (cfg *Configuration) GetOracleCircuitBreakers(pair *PairConfig) []*resilience.Breaker {
+	exchangeNames := []exchanges.Exchange{pair.Oracle}
+	for _, source := range pair.oracleSources {
+		exchangeNames = append(exchangeNames, source.Exchange)
+	}
+	var breakers []*resilience.Breaker
+	for _, name := range lo.Uniq(exchangeNames) {
+		if breaker := cfg.GetCircuitBreaker(name); breaker != nil {
+			breakers = append(breakers, breaker)
+		}
+	}
+	return breakers
+}
+
+// GetFailureCounter returns the consecutive failures of the client of the exchange, once created by GetExchangeClient.
+// Paper orders never fail on their own, the market data they're filled against is fetched by the oracle client.
+func (cfg *Configuration) GetFailureCounter(name exchanges.Exchange) interfaces.FailureCounter {
+	switch name {
+	case exchanges.Biconomy:
+		return cfg.Exchange.Biconomy.failures
+	case exchanges.BingX:
+		return cfg.Exchange.BingX.failures
+	case exchanges.Bybit:
+		return cfg.Exchange.Bybit.failures
+	default:
+		return nil
 	}
 }
 
 func (cfg *Configuration) getBiconomyClient(ctx context.Context) (interfaces.ExchangeClient, error) {
-	exchangeCfg := cfg.Exchange.Biconomy
+	exchangeCfg := &cfg.Exchange.Biconomy
 	if exchangeCfg.client != nil {
 		return exchangeCfg.client, nil
 	}
 	logger := cfg.GetLogger()
+	rateLimiter, err := cfg.newRateLimiter(exchanges.Biconomy, biconomy.RateLimitRules())
+	if err != nil {
+		return nil, err
+	}
+	policy, err := cfg.newResiliencePolicy(exchanges.Biconomy, biconomy.RetryRules())
+	if err != nil {
+		return nil, err
+	}
 	apiClient, err := biconomy.NewClient(ctx, &biconomy.NewClientInput{
-		APIKey:     exchangeCfg.ExchangeAPIKey,
-		APISecret:  exchangeCfg.ExchangeAPISecret,
-		APITimeout: exchangeCfg.ExchangeAPITimeout,
-		Logger:     logger,
+		BaseURL:     exchangeCfg.ExchangeAPIURL,
+		APIKey:      exchangeCfg.ExchangeAPIKey,
+		APISecret:   exchangeCfg.ExchangeAPISecret,
+		APITimeout:  exchangeCfg.ExchangeAPITimeout,
+		RateLimiter: rateLimiter,
+		Resilience:  policy,
+		Logger:      logger,
 	})
 	if err != nil {
 		level.Error(logger).Log("msg", "failed to create biconomy client", "err", err)
 		return nil, err
 	}
-	exchangeCfg.client = apiClient
-	return apiClient, nil
+	monitoredClient := metrics.NewExchangeClient(string(exchanges.Biconomy), apiClient)
+	exchangeCfg.failures = monitoredClient
+	exchangeCfg.breaker = policy.Breaker()
+	client, err := cfg.withStream(ctx, monitoredClient, biconomy.NewStreamDialect(exchangeCfg.ExchangeWSURL))
+	if err != nil {
+		return nil, err
+	}
+	exchangeCfg.client = client
+	return client, nil
 }
 
 func (cfg *Configuration) getBingXClient(ctx context.Context) (interfaces.ExchangeClient, error) {
-	exchangeCfg := cfg.Exchange.BingX
+	exchangeCfg := &cfg.Exchange.BingX
 	if exchangeCfg.client != nil {
 		return exchangeCfg.client, nil
 	}
 	logger := cfg.GetLogger()
+	rateLimiter, err := cfg.newRateLimiter(exchanges.BingX, bingx.RateLimitRules())
+	if err != nil {
+		return nil, err
+	}
+	policy, err := cfg.newResiliencePolicy(exchanges.BingX, bingx.RetryRules())
+	if err != nil {
+		return nil, err
+	}
 	apiClient, err := bingx.NewClient(ctx, &bingx.NewClientInput{
-		APIKey:     exchangeCfg.ExchangeAPIKey,
-		APISecret:  exchangeCfg.ExchangeAPISecret,
-		APITimeout: exchangeCfg.ExchangeAPITimeout,
-		Logger:     logger,
+		BaseURL:     exchangeCfg.ExchangeAPIURL,
+		APIKey:      exchangeCfg.ExchangeAPIKey,
+		APISecret:   exchangeCfg.ExchangeAPISecret,
+		APITimeout:  exchangeCfg.ExchangeAPITimeout,
+		RateLimiter: rateLimiter,
+		Resilience:  policy,
+		Logger:      logger,
 	})
 	if err != nil {
 		level.Error(logger).Log("msg", "failed to create bingx client", "err", err)
 		return nil, err
 	}
-	exchangeCfg.client = apiClient
-	return apiClient, nil
+	monitoredClient := metrics.NewExchangeClient(string(exchanges.BingX), apiClient)
+	exchangeCfg.failures = monitoredClient
+	exchangeCfg.breaker = policy.Breaker()
+	client, err := cfg.withStream(ctx, monitoredClient, bingx.NewStreamDialect(exchangeCfg.ExchangeWSURL))
+	if err != nil {
+		return nil, err
+	}
+	exchangeCfg.client = client
+	return client, nil
 }
 
 func (cfg *Configuration) getBybitClient(ctx context.Context) (interfaces.ExchangeClient, error) {
-	exchangeCfg := cfg.Exchange.Bybit
+	exchangeCfg := &cfg.Exchange.Bybit
 	if exchangeCfg.client != nil {
 		return exchangeCfg.client, nil
 	}
 	logger := cfg.GetLogger()
+	rateLimiter, err := cfg.newRateLimiter(exchanges.Bybit, bybit.RateLimitRules())
+	if err != nil {
+		return nil, err
+	}
+	policy, err := cfg.newResiliencePolicy(exchanges.Bybit, bybit.RetryRules())
+	if err != nil {
+		return nil, err
+	}
 	apiClient, err := bybit.NewClient(ctx, &bybit.NewClientInput{
-		APIKey:     exchangeCfg.ExchangeAPIKey,
-		APISecret:  exchangeCfg.ExchangeAPISecret,
-		APITimeout: exchangeCfg.ExchangeAPITimeout,
-		Logger:     logger,
+		BaseURL:     exchangeCfg.ExchangeAPIURL,
+		APIKey:      exchangeCfg.ExchangeAPIKey,
+		APISecret:   exchangeCfg.ExchangeAPISecret,
+		APITimeout:  exchangeCfg.ExchangeAPITimeout,
+		RateLimiter: rateLimiter,
+		Resilience:  policy,
+		Logger:      logger,
 	})
 	if err != nil {
 		level.Error(logger).Log("msg", "failed to create bybit client", "err", err)
 		return nil, err
 	}
+	monitoredClient := metrics.NewExchangeClient(string(exchanges.Bybit), apiClient)
+	exchangeCfg.failures = monitoredClient
+	exchangeCfg.breaker = policy.Breaker()
+	client, err := cfg.withStream(ctx, monitoredClient, bybit.NewStreamDialect(exchangeCfg.ExchangeWSURL))
+	if err != nil {
+		return nil, err
+	}
+	exchangeCfg.client = client
+	return client, nil
+}
+
+func (cfg *Configuration) getPaperClient(ctx context.Context) (interfaces.ExchangeClient, error) {
+	exchangeCfg := &cfg.Exchange.Paper
+	if exchangeCfg.client != nil {
+		return exchangeCfg.client, nil
+	}
+	logger := cfg.GetLogger()
+	// The orders of every paper pair are filled against the market data of its own price oracle.
+	markets := make(map[string]paper.Market)
+	for _, pair := range cfg.pairs {
+		if pair.Exchange != exchanges.Paper {
+			continue
+		}
+		if pair.Oracle == exchanges.Paper {
+			return nil, fmt.Errorf("paper exchange can't be used as a price oracle")
+		}
+		marketDataClient, err := cfg.GetExchangeClient(ctx, pair.Oracle)
+		if err != nil {
+			return nil, err
+		}
+		markets[pair.Symbol] = paper.Market{Client: marketDataClient, Symbol: pair.OracleSymbol}
+	}
+	apiClient, err := paper.NewClient(ctx, &paper.NewClientInput{
+		Markets:      markets,
+		BaseBalance:  exchangeCfg.BaseBalance,
+		QuoteBalance: exchangeCfg.QuoteBalance,
+		MakerFee:     exchangeCfg.MakerFee,
+		TakerFee:     exchangeCfg.TakerFee,
+		Logger:       logger,
+	})
+	if err != nil {
+		level.Error(logger).Log("msg", "failed to create paper client", "err", err)
+		return nil, err
+	}
 	exchangeCfg.client = apiClient
 	return apiClient, nil
 }
+
+// newRateLimiter returns the rate limiter of the client of the exchange, nil when rate limiting is disabled.
+func (cfg *Configuration) newRateLimiter(exchange exchanges.Exchange, rules ratelimit.Rules) (*ratelimit.Limiter, error) {
+	if !cfg.RateLimit.Enabled {
+		return nil, nil
+	}
+	limiter, err := ratelimit.NewLimiter(&ratelimit.NewLimiterInput{
+		Exchange: string(exchange),
+		Rules:    rules,
+		Ratio:    cfg.RateLimit.Ratio,
+		MaxWait:  cfg.RateLimit.MaxWait,
+		Logger:   cfg.GetLogger(),
+	})
+	if err != nil {
+		level.Error(cfg.GetLogger()).Log("msg", "failed to create rate limiter", "exchange", exchange, "err", err)
+		return nil, err
+	}
+	metrics.RegisterRateLimiter(limiter)
+	return limiter, nil
+}
+
+// newResiliencePolicy returns the retries and circuit breaker of the client of the exchange, nil when both are disabled.
+func (cfg *Configuration) newResiliencePolicy(exchange exchanges.Exchange, rules resilience.Rules) (*resilience.Policy, error) {
+	resilienceCfg := cfg.Resilience
+	if resilienceCfg.MaxRetries <= 0 && resilienceCfg.BreakerThreshold <= 0 {
+		return nil, nil
+	}
+	logger := cfg.GetLogger()
+	var breaker *resilience.Breaker
+	if resilienceCfg.BreakerThreshold > 0 {
+		var err error
+		breaker, err = resilience.NewBreaker(&resilience.NewBreakerInput{
+			Exchange:  string(exchange),
+			Threshold: resilienceCfg.BreakerThreshold,
+			Cooldown:  resilienceCfg.BreakerCooldown,
+			Logger:    logger,
+		})
+		if err != nil {
+			level.Error(logger).Log("msg", "failed to create circuit breaker", "exchange", exchange, "err", err)
+			return nil, err
+		}
+	}
+	policy, err := resilience.NewPolicy(&resilience.NewPolicyInput{
+		Exchange:   string(exchange),
+		Rules:      rules,
+		MaxRetries: max(0, resilienceCfg.MaxRetries),
+		BackoffMin: resilienceCfg.BackoffMin,
+		BackoffMax: resilienceCfg.BackoffMax,
+		Breaker:    breaker,
+		Logger:     logger,
+	})
+	if err != nil {
+		level.Error(logger).Log("msg", "failed to create retry policy", "exchange", exchange, "err", err)
+		return nil, err
+	}
+	metrics.RegisterResiliencePolicy(policy)
+	return policy, nil
+}
+
+// withStream serves the tickers of the client from the exchange websocket when streaming is enabled.
+func (cfg *Configuration) withStream(ctx context.Context, client interfaces.ExchangeClient, dialect stream.Dialect) (interfaces.ExchangeClient, error) {
+	if !cfg.Stream.Enabled {
+		return client, nil
+	}
+	logger := cfg.GetLogger()
+	streamingClient, err := marketdata.NewStreamingClient(ctx, &marketdata.NewStreamingClientInput{
+		ExchangeClient: client,
+		Dialect:        dialect,
+		PingInterval:   cfg.Stream.PingInterval,
+		StaleAfter:     cfg.Stream.StaleAfter,
+		ReconnectDelay: cfg.Stream.ReconnectDelay,
+		Logger:         logger,
+	})
+	if err != nil {
+		level.Error(logger).Log("msg", "failed to create streaming client", "err", err)
+		return nil, err
+	}
+	return streamingClient, nil
+}
@@ -0,0 +1,213 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/kelseyhightower/envconfig"
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// configFileEnv is the environment variable holding the path of the config file.
+const configFileEnv = "CONFIG_FILE"
+
+var (
+	// fileVariables are the environment variables exported from the config file, which a reload may change or unset.
+	fileVariables   = make(map[string]bool)
+	fileVariablesMu sync.Mutex
+)
+
+// loadConfigFile exports every value of the config file as the environment variable it stands for, unless that variable is set by the environment.
+// The environment therefore overrides the file, and envconfig parses the values and applies the defaults of whatever neither sets.
+func loadConfigFile(path string) error {
+	values, err := readConfigFile(path)
+	if err != nil {
+		return err
+	}
+	env := make(map[string]string)
+	pairs, hasPairs := values["pairs"]
+	delete(values, "pairs")
+	if err = flattenSection(env, "", reflect.TypeOf(Configuration{}), values, ""); err != nil {
+		return err
+	}
+	if hasPairs {
+		if err = flattenPairs(env, pairs); err != nil {
+			return err
+		}
+	}
+	fileVariablesMu.Lock()
+	defer fileVariablesMu.Unlock()
+	for key := range fileVariables {
+		if _, found := env[key]; !found {
+			if err = os.Unsetenv(key); err != nil {
+				return err
+			}
+			delete(fileVariables, key)
+		}
+	}
+	for key, value := range env {
+		if _, found := os.LookupEnv(key); found && !fileVariables[key] {
+			continue
+		}
+		if err = os.Setenv(key, value); err != nil {
+			return err
+		}
+		fileVariables[key] = true
+	}
+	return nil
+}
+
+// ReloadPairs reads the config file and the environment variables again and resolves the pairs,
+// the environment still overrides the file.
+func ReloadPairs() ([]*PairConfig, error) {
+	path := os.Getenv(configFileEnv)
+	if path == "" {
+		return nil, fmt.Errorf("%s is not set", configFileEnv)
+	}
+	if err := loadConfigFile(path); err != nil {
+		return nil, fmt.Errorf("failed to load config file %s: %w", path, err)
+	}
+	cfg := &Configuration{}
+	if err := envconfig.Process("", cfg); err != nil {
+		return nil, err
+	}
+	if err := cfg.resolvePairs(); err != nil {
+		return nil, err
+	}
+	return cfg.pairs, nil
+}
+
+// GetConfigFile returns the path of the config file, empty when CONFIG_FILE isn't set.
+func (cfg *Configuration) GetConfigFile() string {
+	return os.Getenv(configFileEnv)
+}
+
+func readConfigFile(path string) (map[string]any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]any)
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &values)
+	case ".toml":
+		err = toml.Unmarshal(data, &values)
+	default:
+		return nil, fmt.Errorf("unsupported config file format: %s, expected .yaml, .yml or .toml", path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// flattenPairs exports the pair sections as PAIR_<NAME>_ prefixed variables, and their names, in order, as PAIRS.
+func flattenPairs(env map[string]string, pairs any) error {
+	list, ok := pairs.([]any)
+	if !ok {
+		return fmt.Errorf("pairs must be a list of pair sections")
+	}
+	names := make([]string, 0, len(list))
+	for i, item := range list {
+		section, ok := item.(map[string]any)
+		if !ok {
+			return fmt.Errorf("pairs[%d] must be a section", i)
+		}
+		name, ok := section["name"].(string)
+		if !ok || !pairNamePattern.MatchString(name) {
+			return fmt.Errorf("pairs[%d] must have a name of letters, digits and underscores", i)
+		}
+		delete(section, "name")
+		prefix := "PAIR_" + strings.ToUpper(name) + "_"
+		if err := flattenSection(env, prefix, reflect.TypeOf(PairConfig{}), section, "pairs."+name); err != nil {
+			return err
+		}
+		names = append(names, name)
+	}
+	env["PAIRS"] = strings.Join(names, ",")
+	return nil
+}
+
+// flattenSection maps the keys of the section to the fields of the config type by their file tags,
+// nested sections to struct fields and values to the envconfig variables of the fields.
+func flattenSection(env map[string]string, prefix string, configType reflect.Type, section map[string]any, path string) error {
+	fields := make(map[string]reflect.StructField)
+	collectFileFields(fields, configType)
+	for key, value := range section {
+		keyPath := key
+		if path != "" {
+			keyPath = path + "." + key
+		}
+		field, found := fields[key]
+		if !found {
+			return fmt.Errorf("unknown config key: %s", keyPath)
+		}
+		if variable := field.Tag.Get("envconfig"); variable != "" {
+			formatted, err := formatConfigValue(value)
+			if err != nil {
+				return fmt.Errorf("invalid value of %s: %w", keyPath, err)
+			}
+			env[prefix+variable] = formatted
+			continue
+		}
+		nested, ok := value.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s must be a section", keyPath)
+		}
+		if err := flattenSection(env, prefix, field.Type, nested, keyPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// collectFileFields indexes the fields by their file tags, the fields of embedded structs are promoted.
+func collectFileFields(fields map[string]reflect.StructField, configType reflect.Type) {
+	for i := range configType.NumField() {
+		field := configType.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			collectFileFields(fields, field.Type)
+			continue
+		}
+		if name := field.Tag.Get("file"); name != "" {
+			fields[name] = field
+		}
+	}
+}
+
+// formatConfigValue formats the value the way envconfig parses it, lists are comma separated.
+func formatConfigValue(value any) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case uint64:
+		return strconv.FormatUint(v, 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case []any:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			formatted, err := formatConfigValue(item)
+			if err != nil {
+				return "", err
+			}
+			items = append(items, formatted)
+		}
+		return strings.Join(items, ","), nil
+	default:
+		return "", fmt.Errorf("unsupported value: %v", value)
+	}
+}
@@ -0,0 +1,200 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+
+	"github.com/imbonda/vmm-bot/internal/marketdata"
+	"github.com/imbonda/vmm-bot/pkg/exchanges"
+	"github.com/imbonda/vmm-bot/pkg/models"
+	"github.com/imbonda/vmm-bot/pkg/utils"
+)
+
+// defaultPairName names the single pair configured by the global variables when PAIRS isn't set.
+const defaultPairName = "default"
+
+var pairNamePattern = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// PairConfig is a traded pair. Its settings are read from the PAIR_<NAME>_ prefixed variables, e.g. PAIR_STOP_CANDLE_HEIGHT,
+// and fall back to the global variables, e.g. CANDLE_HEIGHT.
+type PairConfig struct {
+	Name                           string             `ignored:"true"`
+	Enabled                        bool               `default:"true" envconfig:"ENABLED" file:"enabled"`
+	Exchange                       exchanges.Exchange `envconfig:"EXCHANGE_NAME" file:"exchange"`
+	Oracle                         exchanges.Exchange `envconfig:"ORACLE_EXCHANGE_NAME" file:"oracle"`
+	IntervalExecutionDuration      time.Duration      `default:"60s" envconfig:"INTERVAL_EXECUTION_DURATION" file:"intervalExecutionDuration"`
+	NumOfTradeIterationsInInterval int                `default:"2" envconfig:"NUM_OF_TRADE_ITERATIONS_IN_INTERVAL" file:"numOfTradeIterationsInInterval"`
+	TradeConfig
+	pair          models.Pair
+	oracleSources []OracleSource
+}
+
+// OracleSource is a source of the composite oracle of a pair besides its oracle exchange, listed in ORACLE_SOURCES
+// as <exchange>:<symbol>, e.g. bybit:BTC/USDT.
+type OracleSource struct {
+	Exchange exchanges.Exchange
+	Symbol   string
+}
+
+// GetPairs returns the traded pairs, resolved by LoadConfig.
+func (cfg *Configuration) GetPairs() []*PairConfig {
+	return cfg.pairs
+}
+
+// resolvePairs reads the pairs listed in PAIRS, or the single pair of the global variables when it isn't set,
+// and translates their canonical symbols to the symbol formats of their exchanges.
+func (cfg *Configuration) resolvePairs() error {
+	var pairs []*PairConfig
+	if len(cfg.Service.Pairs) == 0 {
+		pairs = append(pairs, &PairConfig{
+			Name:                           defaultPairName,
+			Enabled:                        true,
+			Exchange:                       cfg.Exchange.Name,
+			Oracle:                         cfg.Exchange.Oracle,
+			IntervalExecutionDuration:      cfg.Executor.IntervalExecutionDuration,
+			NumOfTradeIterationsInInterval: cfg.Executor.NumOfTradeIterationsInInterval,
+			TradeConfig:                    cfg.Trade,
+		})
+	}
+	for _, name := range cfg.Service.Pairs {
+		if !pairNamePattern.MatchString(name) {
+			return fmt.Errorf("invalid pair name: %s, only letters, digits and underscores are allowed", name)
+		}
+		pair := &PairConfig{Name: name}
+		if err := envconfig.Process("PAIR_"+strings.ToUpper(name), pair); err != nil {
+			return err
+		}
+		pairs = append(pairs, pair)
+	}
+
+	names := make(map[string]bool)
+	for _, pair := range pairs {
+		if names[pair.Name] {
+			return fmt.Errorf("duplicate pair: %s", pair.Name)
+		}
+		names[pair.Name] = true
+		if err := pair.resolve(); err != nil {
+			return err
+		}
+	}
+	cfg.pairs = pairs
+	return nil
+}
+
+// resolve validates the pair and translates its symbols.
+func (p *PairConfig) resolve() error {
+	switch {
+	case p.Symbol == "":
+		return fmt.Errorf("pair %s: missing SYMBOL", p.Name)
+	case p.OracleSymbol == "":
+		return fmt.Errorf("pair %s: missing ORACLE_SYMBOL", p.Name)
+	case p.CandleHeight <= 0:
+		return fmt.Errorf("pair %s: CANDLE_HEIGHT must be positive", p.Name)
+	case p.TradeAmountMin <= 0 || p.TradeAmountMax < p.TradeAmountMin:
+		return fmt.Errorf("pair %s: TRADE_AMOUNT_MIN must be positive and not above TRADE_AMOUNT_MAX", p.Name)
+	case p.IntervalExecutionDuration < utils.MinIntervalDuration || p.NumOfTradeIterationsInInterval <= 0:
+		return fmt.Errorf("pair %s: the execution interval must be at least %s and the iterations positive", p.Name, utils.MinIntervalDuration)
+	case p.Oracle == exchanges.Paper:
+		return fmt.Errorf("pair %s: paper exchange can't be used as a price oracle", p.Name)
+	case p.RiskMaxOrderNotional < 0 || p.RiskMaxDailyVolume < 0 || p.RiskMaxDailyFees < 0 ||
+		p.RiskMaxInventoryDeviation < 0 || p.RiskMaxOracleDeviation < 0:
+		return fmt.Errorf("pair %s: risk limits can't be negative", p.Name)
+	case p.OracleMaxDeviation < 0 || p.OracleMaxTickerAge < 0:
+		return fmt.Errorf("pair %s: ORACLE_MAX_DEVIATION and ORACLE_MAX_TICKER_AGE can't be negative", p.Name)
+	case p.OracleAggregation != marketdata.AggregationMedian && p.OracleAggregation != marketdata.AggregationVolumeWeighted:
+		return fmt.Errorf("pair %s: ORACLE_AGGREGATION must be %s or %s", p.Name, marketdata.AggregationMedian, marketdata.AggregationVolumeWeighted)
+	case p.OracleSourceMaxAge < 0 || p.OracleSourceMaxDeviation < 0:
+		return fmt.Errorf("pair %s: ORACLE_SOURCE_MAX_AGE and ORACLE_SOURCE_MAX_DEVIATION can't be negative", p.Name)
+	case p.OracleMinSources < 1 || p.OracleMinSources > len(p.OracleSources)+1:
+		return fmt.Errorf("pair %s: ORACLE_MIN_SOURCES must be between 1 and the number of oracle sources", p.Name)
+	case p.OracleSourceMaxDeviation > 0 && len(p.OracleSources)+1 < marketdata.MinOutlierSources:
+		return fmt.Errorf(
+			"pair %s: ORACLE_SOURCE_MAX_DEVIATION requires at least %d oracle sources, along with the oracle exchange",
+			p.Name,
+			marketdata.MinOutlierSources,
+		)
+	}
+	pair, err := parseSymbol(p.Exchange, p.Symbol)
+	if err != nil {
+		return fmt.Errorf("pair %s: invalid SYMBOL: %w", p.Name, err)
+	}
+	symbol, err := exchanges.FormatSymbol(p.Exchange, pair)
+	if err != nil {
+		return fmt.Errorf("pair %s: invalid SYMBOL: %w", p.Name, err)
+	}
+	oracleSymbol, err := translateSymbol(p.Oracle, p.OracleSymbol)
+	if err != nil {
+		return fmt.Errorf("pair %s: invalid ORACLE_SYMBOL: %w", p.Name, err)
+	}
+	p.pair, p.Symbol, p.OracleSymbol = pair, symbol, oracleSymbol
+	p.oracleSources = nil
+	for _, entry := range p.OracleSources {
+		exchange, sourceSymbol, found := strings.Cut(entry, ":")
+		if !found {
+			return fmt.Errorf("pair %s: invalid ORACLE_SOURCES entry: %s, expected <exchange>:<symbol>", p.Name, entry)
+		}
+		source := OracleSource{Exchange: exchanges.Exchange(exchange)}
+		if source.Exchange == exchanges.Paper {
+			return fmt.Errorf("pair %s: paper exchange can't be used as a price oracle", p.Name)
+		}
+		if source.Symbol, err = translateSymbol(source.Exchange, sourceSymbol); err != nil {
+			return fmt.Errorf("pair %s: invalid ORACLE_SOURCES entry: %s: %w", p.Name, entry, err)
+		}
+		p.oracleSources = append(p.oracleSources, source)
+	}
+	return nil
+}
+
+// Pair returns the canonical base and quote of the traded symbol, resolved by LoadConfig.
+func (p *PairConfig) Pair() models.Pair {
+	return p.pair
+}
+
+// Params returns the parameters of the pair which can be updated while it trades.
+func (p *PairConfig) Params() *models.PairParams {
+	return &models.PairParams{
+		Trade: models.TradeParams{
+			CandleHeight:      p.CandleHeight,
+			SpreadMarginLower: p.SpreadMarginLower,
+			SpreadMarginUpper: p.SpreadMarginUpper,
+			TradeAmountMin:    p.TradeAmountMin,
+			TradeAmountMax:    p.TradeAmountMax,
+		},
+		Schedule: &models.ScheduleParams{
+			IntervalExecutionDuration:      models.Duration(p.IntervalExecutionDuration),
+			NumOfTradeIterationsInInterval: p.NumOfTradeIterationsInInterval,
+		},
+	}
+}
+
+// RequiresRestart reports whether the pairs differ in settings other than their parameters.
+func (p *PairConfig) RequiresRestart(other *PairConfig) bool {
+	settings := func(pair PairConfig) PairConfig {
+		pair.CandleHeight, pair.SpreadMarginLower, pair.SpreadMarginUpper = 0, 0, 0
+		pair.TradeAmountMin, pair.TradeAmountMax = 0, 0
+		pair.IntervalExecutionDuration, pair.NumOfTradeIterationsInInterval = 0, 0
+		return pair
+	}
+	return !reflect.DeepEqual(settings(*p), settings(*other))
+}
+
+func translateSymbol(exchange exchanges.Exchange, symbol string) (string, error) {
+	pair, err := parseSymbol(exchange, symbol)
+	if err != nil {
+		return "", err
+	}
+	return exchanges.FormatSymbol(exchange, pair)
+}
+
+// parseSymbol reads a canonical BASE/QUOTE symbol, or a symbol written in the symbol format of the exchange.
+func parseSymbol(exchange exchanges.Exchange, symbol string) (models.Pair, error) {
+	if strings.Contains(symbol, models.PairSeparator) {
+		return models.ParsePair(symbol)
+	}
+	return exchanges.ParseSymbol(exchange, symbol)
+}
@@ -2,6 +2,7 @@ package interfaces
 
 import (
 	"context"
+	"time"
 
 	"github.com/imbonda/vmm-bot/pkg/models"
 )
@@ -9,6 +10,23 @@ import (
 type ExchangeClient interface {
 	GetOrderBook(ctx context.Context, symbol string) (*models.OrderBook, error)
 	GetLastTicker(ctx context.Context, symbol string) (*models.Ticker, error)
-	PlaceOrder(ctx context.Context, order *models.Order) error
+	PlaceOrder(ctx context.Context, order *models.Order) (*models.OrderRecord, error)
+	GetOrder(ctx context.Context, symbol string, orderID string) (*models.OrderRecord, error)
+	GetOrderByClientID(ctx context.Context, symbol string, clientOrderID string) (*models.OrderRecord, error)
+	ListOpenOrders(ctx context.Context, symbol string) ([]*models.OrderRecord, error)
+	CancelOrder(ctx context.Context, symbol string, orderID string) error
 	CancelAllOrders(ctx context.Context, symbol string) error
+	GetFills(ctx context.Context, symbol string, since time.Time) ([]*models.Fill, error)
+	GetBalances(ctx context.Context) ([]*models.Balance, error)
+	GetSymbolInfo(ctx context.Context, symbol string) (*models.SymbolInfo, error)
+}
+
+type PaperExchangeClient interface {
+	ExchangeClient
+	GetState(ctx context.Context) (*models.PaperState, error)
+}
+
+// FailureCounter counts the consecutive failed requests of an exchange client.
+type FailureCounter interface {
+	ConsecutiveFailures() int
 }
@@ -8,6 +8,8 @@ import (
 	mock "github.com/stretchr/testify/mock"
 
 	models "github.com/imbonda/vmm-bot/pkg/models"
+
+	time "time"
 )
 
 // ExchangeClient is an autogenerated mock type for the ExchangeClient type
@@ -15,6 +17,162 @@ type ExchangeClient struct {
 	mock.Mock
 }
 
+// CancelAllOrders provides a mock function with given fields: ctx, symbol
+func (_m *ExchangeClient) CancelAllOrders(ctx context.Context, symbol string) error {
+	ret := _m.Called(ctx, symbol)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CancelAllOrders")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, symbol)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CancelOrder provides a mock function with given fields: ctx, symbol, orderID
+func (_m *ExchangeClient) CancelOrder(ctx context.Context, symbol string, orderID string) error {
+	ret := _m.Called(ctx, symbol, orderID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CancelOrder")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, symbol, orderID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetBalances provides a mock function with given fields: ctx
+func (_m *ExchangeClient) GetBalances(ctx context.Context) ([]*models.Balance, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBalances")
+	}
+
+	var r0 []*models.Balance
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*models.Balance, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*models.Balance); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Balance)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetFills provides a mock function with given fields: ctx, symbol, since
+func (_m *ExchangeClient) GetFills(ctx context.Context, symbol string, since time.Time) ([]*models.Fill, error) {
+	ret := _m.Called(ctx, symbol, since)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetFills")
+	}
+
+	var r0 []*models.Fill
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) ([]*models.Fill, error)); ok {
+		return rf(ctx, symbol, since)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) []*models.Fill); ok {
+		r0 = rf(ctx, symbol, since)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Fill)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time) error); ok {
+		r1 = rf(ctx, symbol, since)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLastTicker provides a mock function with given fields: ctx, symbol
+func (_m *ExchangeClient) GetLastTicker(ctx context.Context, symbol string) (*models.Ticker, error) {
+	ret := _m.Called(ctx, symbol)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLastTicker")
+	}
+
+	var r0 *models.Ticker
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.Ticker, error)); ok {
+		return rf(ctx, symbol)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.Ticker); ok {
+		r0 = rf(ctx, symbol)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Ticker)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, symbol)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOrder provides a mock function with given fields: ctx, symbol, orderID
+func (_m *ExchangeClient) GetOrder(ctx context.Context, symbol string, orderID string) (*models.OrderRecord, error) {
+	ret := _m.Called(ctx, symbol, orderID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrder")
+	}
+
+	var r0 *models.OrderRecord
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*models.OrderRecord, error)); ok {
+		return rf(ctx, symbol, orderID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *models.OrderRecord); ok {
+		r0 = rf(ctx, symbol, orderID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.OrderRecord)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, symbol, orderID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetOrderBook provides a mock function with given fields: ctx, symbol
 func (_m *ExchangeClient) GetOrderBook(ctx context.Context, symbol string) (*models.OrderBook, error) {
 	ret := _m.Called(ctx, symbol)
@@ -43,22 +201,124 @@ func (_m *ExchangeClient) GetOrderBook(ctx context.Context, symbol string) (*mod
 	return r0, r1
 }
 
+// GetOrderByClientID provides a mock function with given fields: ctx, symbol, clientOrderID
+func (_m *ExchangeClient) GetOrderByClientID(ctx context.Context, symbol string, clientOrderID string) (*models.OrderRecord, error) {
+	ret := _m.Called(ctx, symbol, clientOrderID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrderByClientID")
+	}
+
+	var r0 *models.OrderRecord
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*models.OrderRecord, error)); ok {
+		return rf(ctx, symbol, clientOrderID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *models.OrderRecord); ok {
+		r0 = rf(ctx, symbol, clientOrderID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.OrderRecord)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, symbol, clientOrderID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSymbolInfo provides a mock function with given fields: ctx, symbol
+func (_m *ExchangeClient) GetSymbolInfo(ctx context.Context, symbol string) (*models.SymbolInfo, error) {
+	ret := _m.Called(ctx, symbol)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSymbolInfo")
+	}
+
+	var r0 *models.SymbolInfo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.SymbolInfo, error)); ok {
+		return rf(ctx, symbol)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.SymbolInfo); ok {
+		r0 = rf(ctx, symbol)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.SymbolInfo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, symbol)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListOpenOrders provides a mock function with given fields: ctx, symbol
+func (_m *ExchangeClient) ListOpenOrders(ctx context.Context, symbol string) ([]*models.OrderRecord, error) {
+	ret := _m.Called(ctx, symbol)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListOpenOrders")
+	}
+
+	var r0 []*models.OrderRecord
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*models.OrderRecord, error)); ok {
+		return rf(ctx, symbol)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*models.OrderRecord); ok {
+		r0 = rf(ctx, symbol)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.OrderRecord)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, symbol)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // PlaceOrder provides a mock function with given fields: ctx, order
-func (_m *ExchangeClient) PlaceOrder(ctx context.Context, order *models.Order) error {
+func (_m *ExchangeClient) PlaceOrder(ctx context.Context, order *models.Order) (*models.OrderRecord, error) {
 	ret := _m.Called(ctx, order)
 
 	if len(ret) == 0 {
 		panic("no return value specified for PlaceOrder")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *models.Order) error); ok {
+	var r0 *models.OrderRecord
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Order) (*models.OrderRecord, error)); ok {
+		return rf(ctx, order)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Order) *models.OrderRecord); ok {
 		r0 = rf(ctx, order)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.OrderRecord)
+		}
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, *models.Order) error); ok {
+		r1 = rf(ctx, order)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // NewExchangeClient creates a new instance of ExchangeClient. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
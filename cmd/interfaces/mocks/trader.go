@@ -8,6 +8,8 @@ import (
 	mock "github.com/stretchr/testify/mock"
 
 	models "github.com/imbonda/vmm-bot/pkg/models"
+
+	time "time"
 )
 
 // Trader is an autogenerated mock type for the Trader type
@@ -15,6 +17,148 @@ type Trader struct {
 	mock.Mock
 }
 
+// CancelAllOrders provides a mock function with given fields: ctx
+func (_m *Trader) CancelAllOrders(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CancelAllOrders")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DoIteration provides a mock function with given fields: ctx
+func (_m *Trader) DoIteration(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DoIteration")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetFills provides a mock function with given fields: ctx, since
+func (_m *Trader) GetFills(ctx context.Context, since time.Time) (*models.FillsOutput, error) {
+	ret := _m.Called(ctx, since)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetFills")
+	}
+
+	var r0 *models.FillsOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (*models.FillsOutput, error)); ok {
+		return rf(ctx, since)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) *models.FillsOutput); ok {
+		r0 = rf(ctx, since)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.FillsOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, since)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOpenOrders provides a mock function with given fields: ctx
+func (_m *Trader) GetOpenOrders(ctx context.Context) ([]*models.OrderRecord, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOpenOrders")
+	}
+
+	var r0 []*models.OrderRecord
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*models.OrderRecord, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*models.OrderRecord); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.OrderRecord)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Params provides a mock function with given fields:
+func (_m *Trader) Params() models.TradeParams {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Params")
+	}
+
+	var r0 models.TradeParams
+	if rf, ok := ret.Get(0).(func() models.TradeParams); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(models.TradeParams)
+	}
+
+	return r0
+}
+
+// RollbackParams provides a mock function with given fields:
+func (_m *Trader) RollbackParams() (models.TradeParams, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for RollbackParams")
+	}
+
+	var r0 models.TradeParams
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (models.TradeParams, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() models.TradeParams); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(models.TradeParams)
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // TradeOnce provides a mock function with given fields: ctx
 func (_m *Trader) TradeOnce(ctx context.Context) (*models.TradeOnceOutput, error) {
 	ret := _m.Called(ctx)
@@ -45,6 +189,34 @@ func (_m *Trader) TradeOnce(ctx context.Context) (*models.TradeOnceOutput, error
 	return r0, r1
 }
 
+// UpdateParams provides a mock function with given fields: params
+func (_m *Trader) UpdateParams(params models.TradeParams) (models.TradeParams, error) {
+	ret := _m.Called(params)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateParams")
+	}
+
+	var r0 models.TradeParams
+	var r1 error
+	if rf, ok := ret.Get(0).(func(models.TradeParams) (models.TradeParams, error)); ok {
+		return rf(params)
+	}
+	if rf, ok := ret.Get(0).(func(models.TradeParams) models.TradeParams); ok {
+		r0 = rf(params)
+	} else {
+		r0 = ret.Get(0).(models.TradeParams)
+	}
+
+	if rf, ok := ret.Get(1).(func(models.TradeParams) error); ok {
+		r1 = rf(params)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewTrader creates a new instance of Trader. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewTrader(t interface {
@@ -2,9 +2,42 @@ package interfaces
 
 import (
 	"context"
+
+	"github.com/imbonda/vmm-bot/pkg/models"
 )
 
 type TraderService interface {
 	Start(ctx context.Context) error
 	Shutdown(ctx context.Context) error
 }
+
+// PairsService runs a trader per pair, every pair can be started and stopped on its own.
+type PairsService interface {
+	TraderService
+	StartPair(ctx context.Context, name string) error
+	StopPair(ctx context.Context, name string) error
+}
+
+// ParamsService updates the parameters of the pairs while they trade.
+type ParamsService interface {
+	GetParams(ctx context.Context, name string) (*models.PairParams, error)
+	UpdateParams(ctx context.Context, name string, params *models.PairParams) (*models.ParamsChange, error)
+	RollbackParams(ctx context.Context, name string) (*models.ParamsChange, error)
+}
+
+// HealthService reports whether the pairs are live and ready to trade.
+type HealthService interface {
+	GetHealth(ctx context.Context) *models.Health
+}
+
+// ControlService pauses, resumes and reports the pairs while they trade.
+type ControlService interface {
+	ParamsService
+	HealthService
+	StartPair(ctx context.Context, name string) error
+	StopPair(ctx context.Context, name string) error
+	GetStatus(ctx context.Context, name string) (*models.PairStatus, error)
+	CancelAllOrders(ctx context.Context, name string) error
+	// ResumeTrading lifts the halt of a pair which breached its risk limits.
+	ResumeTrading(ctx context.Context, name string) error
+}
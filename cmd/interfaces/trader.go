@@ -2,10 +2,18 @@ package interfaces
 
 import (
 	"context"
+	"time"
 
 	"github.com/imbonda/vmm-bot/pkg/models"
 )
 
 type Trader interface {
+	DoIteration(ctx context.Context) error
 	TradeOnce(ctx context.Context) (*models.TradeOnceOutput, error)
+	GetFills(ctx context.Context, since time.Time) (*models.FillsOutput, error)
+	CancelAllOrders(ctx context.Context) error
+	GetOpenOrders(ctx context.Context) ([]*models.OrderRecord, error)
+	Params() models.TradeParams
+	UpdateParams(params models.TradeParams) (models.TradeParams, error)
+	RollbackParams() (models.TradeParams, error)
 }
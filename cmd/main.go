@@ -40,6 +40,9 @@ func main() {
 		level.Error(logger).Log("msg", "failed to start the trader", "err", err)
 		return
 	}
+	watchCtx, stopWatching := context.WithCancel(ctx)
+	defer stopWatching()
+	service.WatchConfigFile(watchCtx, cfg, traderService)
 
 	quit := make(chan os.Signal, 1)
 	// kill (no param) default send syscall.SIGTERM
@@ -55,6 +58,7 @@ func main() {
 		level.Debug(logger).Log("msg", "server canceled. shutdown server ...")
 	}
 
+	stopWatching()
 	// gracefully shutdown the server, waiting max 5 seconds for current operations to complete
 	ctx1, cancel1 := context.WithTimeout(context.Background(), cfg.Service.GracefulShutdown)
 	defer cancel1()
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/kelseyhightower/envconfig"
+
+	"github.com/imbonda/vmm-bot/pkg/exchanges/sim"
+	"github.com/imbonda/vmm-bot/pkg/utils"
+)
+
+type Configuration struct {
+	ListenAddress    string             `default:":9000" envconfig:"MOCK_EXCHANGE_LISTEN_ADDRESS"`
+	Markets          map[string]float64 `required:"1" envconfig:"MOCK_EXCHANGE_MARKETS"`
+	Spread           float64            `default:"0.01" envconfig:"MOCK_EXCHANGE_SPREAD"`
+	Depth            float64            `default:"100000" envconfig:"MOCK_EXCHANGE_DEPTH"`
+	Fee              float64            `default:"0.001" envconfig:"MOCK_EXCHANGE_FEE"`
+	Balance          float64            `default:"1000000" envconfig:"MOCK_EXCHANGE_BALANCE"`
+	Balances         map[string]float64 `envconfig:"MOCK_EXCHANGE_BALANCES"`
+	TickSize         float64            `default:"0.0001" envconfig:"MOCK_EXCHANGE_TICK_SIZE"`
+	LotStep          float64            `default:"0.01" envconfig:"MOCK_EXCHANGE_LOT_STEP"`
+	MinQty           float64            `default:"0" envconfig:"MOCK_EXCHANGE_MIN_QTY"`
+	MinNotional      float64            `default:"0" envconfig:"MOCK_EXCHANGE_MIN_NOTIONAL"`
+	Halted           bool               `default:"false" envconfig:"MOCK_EXCHANGE_HALTED"`
+	GracefulShutdown time.Duration      `default:"5s" envconfig:"GRACEFUL_SHUTDOWN"`
+	// The same credentials the bot is configured with.
+	BybitAPIKey       string `envconfig:"BYBIT_API_KEY"`
+	BybitAPISecret    string `envconfig:"BYBIT_API_SECRET"`
+	BiconomyAPIKey    string `envconfig:"BICONOMY_API_KEY"`
+	BiconomyAPISecret string `envconfig:"BICONOMY_API_SECRET"`
+	BingXAPIKey       string `envconfig:"BINGX_API_KEY"`
+	BingXAPISecret    string `envconfig:"BINGX_API_SECRET"`
+}
+
+func main() {
+	cfg := &Configuration{}
+	if err := envconfig.Process("", cfg); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	logger := log.With(
+		log.NewLogfmtLogger(log.NewSyncWriter(os.Stdout)),
+		"ts", log.DefaultTimestampUTC,
+		"name", "mock-exchange",
+	)
+
+	ctx := context.Background()
+	server := sim.NewServer(&sim.NewServerInput{
+		ListenAddress: cfg.ListenAddress,
+		Engine: sim.NewEngine(&sim.NewEngineInput{
+			Markets:  cfg.Markets,
+			Spread:   cfg.Spread,
+			Depth:    cfg.Depth,
+			Fee:      cfg.Fee,
+			Balance:  cfg.Balance,
+			Balances: cfg.Balances,
+			Rules: sim.SymbolRules{
+				TickSize:    cfg.TickSize,
+				LotStep:     cfg.LotStep,
+				MinQty:      cfg.MinQty,
+				MinNotional: cfg.MinNotional,
+				Halted:      cfg.Halted,
+			},
+		}),
+		Bybit: &utils.Credentials{
+			APIKey:    cfg.BybitAPIKey,
+			APISecret: cfg.BybitAPISecret,
+		},
+		Biconomy: &utils.Credentials{
+			APIKey:    cfg.BiconomyAPIKey,
+			APISecret: cfg.BiconomyAPISecret,
+		},
+		BingX: &utils.Credentials{
+			APIKey:    cfg.BingXAPIKey,
+			APISecret: cfg.BingXAPISecret,
+		},
+		Logger: logger,
+	})
+	if err := server.Start(ctx); err != nil {
+		level.Error(logger).Log("msg", "failed to start the mock exchange", "err", err)
+		return
+	}
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	sig := <-quit
+	level.Info(logger).Log("msg", "received signal", "signal", sig)
+
+	ctx1, cancel1 := context.WithTimeout(context.Background(), cfg.GracefulShutdown)
+	defer cancel1()
+	if err := server.Shutdown(ctx1); err != nil {
+		level.Error(logger).Log("msg", "server shutdown:", "err", err)
+		return
+	}
+
+	level.Info(logger).Log("msg", "exiting")
+}
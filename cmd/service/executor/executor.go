@@ -2,61 +2,339 @@ package executor
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
 
 	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
 
 	"github.com/imbonda/vmm-bot/cmd/interfaces"
+	"github.com/imbonda/vmm-bot/cmd/service/http"
 	"github.com/imbonda/vmm-bot/cmd/service/models"
-	"github.com/imbonda/vmm-bot/internal/trader"
+	"github.com/imbonda/vmm-bot/pkg/exchanges/resilience"
+	pkgModels "github.com/imbonda/vmm-bot/pkg/models"
 	"github.com/imbonda/vmm-bot/pkg/utils"
 )
 
 type traderExecutor struct {
+	mu    sync.Mutex
+	pairs map[string]*pairExecutor
+	order []string
+	// server serves the API of the pairs when control is enabled, and the metrics and health probes alone otherwise.
+	server interfaces.TraderService
+	health models.HealthConfig
+	logger log.Logger
+}
+
+// pairExecutor schedules the iterations of a pair, its scheduler is kept across stops so that the schedule updates are too.
+type pairExecutor struct {
+	pair             *models.PairConfig
 	traderClient     interfaces.Trader
-	intervalExecutor *utils.IterationsExecutor[*trader.Trader]
-	logger           log.Logger
-}
-
-func NewTraderService(ctx context.Context, input *models.NewTraderServiceInput) (interfaces.TraderService, error) {
-	traderClient, err := trader.NewTrader(ctx, &trader.NewTraderInput{
-		ExchangeClient:    input.ExchangeClient,
-		PriceOracleClient: input.PriceOracleClient,
-		Symbol:            input.Trade.Symbol,
-		OracleSymbol:      input.Trade.OracleSymbol,
-		CandleHeight:      input.Trade.CandleHeight,
-		SpreadMarginLower: input.Trade.SpreadMarginLower,
-		SpreadMarginUpper: input.Trade.SpreadMarginUpper,
-		TradeAmountMin:    input.Trade.TradeAmountMin,
-		TradeAmountMax:    input.Trade.TradeAmountMax,
-		PriceDecimals:     input.Trade.PriceDecimals,
-		AmountDecimals:    input.Trade.AmountDecimals,
-		Logger:            input.Logger,
-	})
-	if err != nil {
-		return nil, err
-	}
-	executor, err := utils.NewIterationsExecutor(
-		ctx,
-		&utils.NewIterationsExecutorInput[*trader.Trader]{
-			Callee:                         traderClient,
-			IntervalExecutionDuration:      input.Executor.IntervalExecutionDuration,
-			NumOfTradeIterationsInInterval: input.Executor.NumOfTradeIterationsInInterval,
-			Logger:                         input.Logger,
+	intervalExecutor *utils.IterationsExecutor[interfaces.Trader]
+	running          bool
+	startedAt        time.Time
+}
+
+func NewTraderService(ctx context.Context, input *models.NewTraderServiceInput) (interfaces.PairsService, error) {
+	service := &traderExecutor{
+		pairs:  make(map[string]*pairExecutor),
+		health: input.Health,
+		logger: input.Logger,
+	}
+	for _, pair := range input.Pairs {
+		traderClient, err := pair.NewTrader(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("pair %s: %w", pair.Name, err)
+		}
+		intervalExecutor, err := utils.NewIterationsExecutor(
+			ctx,
+			&utils.NewIterationsExecutorInput[interfaces.Trader]{
+				Callee:                         traderClient,
+				IntervalExecutionDuration:      pair.Schedule.IntervalExecutionDuration,
+				NumOfTradeIterationsInInterval: pair.Schedule.NumOfTradeIterationsInInterval,
+				Logger:                         pair.Logger,
+			})
+		if err != nil {
+			return nil, fmt.Errorf("pair %s: %w", pair.Name, err)
+		}
+		service.pairs[pair.Name] = &pairExecutor{
+			pair:             pair,
+			traderClient:     traderClient,
+			intervalExecutor: intervalExecutor,
+		}
+		service.order = append(service.order, pair.Name)
+	}
+	if input.Executor.ControlEnabled {
+		traders := make(map[string]interfaces.Trader)
+		for name, executor := range service.pairs {
+			traders[name] = executor.traderClient
+		}
+		control, err := http.NewTraderBackend(ctx, &http.NewTraderBackendInput{
+			ListenAddress: input.Executor.ListenAddress,
+			Pairs:         input.Pairs,
+			Traders:       traders,
+			Control:       service,
+			Health:        input.Health,
+			Logger:        input.Logger,
 		})
-	if err != nil {
-		return nil, err
+		if err != nil {
+			return nil, err
+		}
+		service.server = control
+	} else {
+		service.server = newProbeServer(input.Executor.ListenAddress, service, input.Logger)
 	}
-	return &traderExecutor{
-		traderClient:     traderClient,
-		intervalExecutor: executor,
-		logger:           input.Logger,
-	}, nil
+	return service, nil
 }
 
+// Start starts the server and the enabled pairs.
 func (s *traderExecutor) Start(ctx context.Context) error {
-	return s.intervalExecutor.Start(ctx)
+	if err := s.server.Start(ctx); err != nil {
+		return err
+	}
+	for _, name := range s.order {
+		if !s.pairs[name].pair.Enabled {
+			level.Info(s.logger).Log("msg", "pair is disabled", "pair", name)
+			continue
+		}
+		if err := s.StartPair(ctx, name); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (s *traderExecutor) Shutdown(ctx context.Context) error {
-	return s.intervalExecutor.Shutdown(ctx)
+	if err := s.server.Shutdown(ctx); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, name := range s.order {
+		if executor := s.pairs[name]; executor.running {
+			if err := s.stop(ctx, executor); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *traderExecutor) StartPair(ctx context.Context, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	executor, err := s.getPair(name)
+	if err != nil {
+		return err
+	}
+	if executor.running {
+		return fmt.Errorf("%w: %s", models.ErrPairRunning, name)
+	}
+	// The pair outlives the request which may have started it.
+	if err = executor.intervalExecutor.Start(context.WithoutCancel(ctx)); err != nil {
+		return err
+	}
+	executor.running, executor.startedAt = true, time.Now()
+	level.Info(executor.pair.Logger).Log("msg", "started pair")
+	return nil
+}
+
+func (s *traderExecutor) StopPair(ctx context.Context, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	executor, err := s.getPair(name)
+	if err != nil {
+		return err
+	}
+	if !executor.running {
+		return fmt.Errorf("%w: %s", models.ErrPairNotRunning, name)
+	}
+	return s.stop(ctx, executor)
+}
+
+func (s *traderExecutor) GetParams(ctx context.Context, name string) (*pkgModels.PairParams, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	executor, err := s.getPair(name)
+	if err != nil {
+		return nil, err
+	}
+	return executor.params(), nil
+}
+
+// UpdateParams validates every parameter before applying any, the trader applies them from its next iteration
+// and the scheduler from its next interval.
+func (s *traderExecutor) UpdateParams(ctx context.Context, name string, params *pkgModels.PairParams) (*pkgModels.ParamsChange, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	executor, err := s.getPair(name)
+	if err != nil {
+		return nil, err
+	}
+	if params.Schedule == nil {
+		return nil, fmt.Errorf("%w: missing schedule", pkgModels.ErrInvalidParams)
+	}
+	if err = params.Validate(); err != nil {
+		return nil, err
+	}
+	previous := executor.params()
+	if _, err = executor.traderClient.UpdateParams(params.Trade); err != nil {
+		return nil, err
+	}
+	// Updating both, even when one is unchanged, keeps their previous values in step for RollbackParams.
+	if _, err = executor.intervalExecutor.UpdateSchedule(utils.Schedule{
+		IntervalDuration:   time.Duration(params.Schedule.IntervalExecutionDuration),
+		NumTasksInInterval: params.Schedule.NumOfTradeIterationsInInterval,
+	}); err != nil {
+		return nil, err
+	}
+	return &pkgModels.ParamsChange{Pair: name, Previous: previous, Current: executor.params()}, nil
+}
+
+func (s *traderExecutor) RollbackParams(ctx context.Context, name string) (*pkgModels.ParamsChange, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	executor, err := s.getPair(name)
+	if err != nil {
+		return nil, err
+	}
+	previous := executor.params()
+	if _, err = executor.traderClient.RollbackParams(); err != nil {
+		return nil, err
+	}
+	if _, err = executor.intervalExecutor.RollbackSchedule(); err != nil {
+		return nil, err
+	}
+	return &pkgModels.ParamsChange{Pair: name, Previous: previous, Current: executor.params()}, nil
+}
+
+// GetStatus reports the pair, its open orders are fetched from the exchange.
+func (s *traderExecutor) GetStatus(ctx context.Context, name string) (*pkgModels.PairStatus, error) {
+	s.mu.Lock()
+	executor, err := s.getPair(name)
+	if err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+	status := &pkgModels.PairStatus{
+		Pair:   name,
+		Symbol: executor.pair.Trade.Symbol,
+		Paused: !executor.running,
+		Params: executor.params(),
+	}
+	s.mu.Unlock()
+	if lastRun := executor.intervalExecutor.LastRun(); !lastRun.IsZero() {
+		status.LastIteration = &lastRun
+	}
+	if err = executor.intervalExecutor.LastError(); err != nil {
+		status.LastError = err.Error()
+	}
+	status.Risk = executor.pair.Risk.State()
+	status.Circuits = executor.pair.CircuitStatuses()
+	if status.OpenOrders, err = executor.traderClient.GetOpenOrders(ctx); err != nil && !errors.Is(err, resilience.ErrCircuitOpen) {
+		return nil, err
+	}
+	return status, nil
+}
+
+// CancelAllOrders cancels the open orders of the pair, a running pair places new orders from its next iteration.
+func (s *traderExecutor) CancelAllOrders(ctx context.Context, name string) error {
+	s.mu.Lock()
+	executor, err := s.getPair(name)
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if err = executor.traderClient.CancelAllOrders(ctx); err != nil {
+		return err
+	}
+	level.Info(executor.pair.Logger).Log("msg", "cancelled all orders")
+	return nil
+}
+
+// ResumeTrading lifts the halt of a pair which breached its risk limits, a running pair trades from its next iteration.
+func (s *traderExecutor) ResumeTrading(ctx context.Context, name string) error {
+	s.mu.Lock()
+	executor, err := s.getPair(name)
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	_, err = executor.pair.Risk.Resume(ctx)
+	return err
+}
+
+// GetHealth reports the pairs as not live when a running pair's scheduler has exited, or hasn't completed an iteration
+// within the allowed intervals, and as not ready either when they are halted or their clients keep failing. Paused pairs pass both probes.
+func (s *traderExecutor) GetHealth(ctx context.Context) *pkgModels.Health {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pairs := make([]*pkgModels.PairHealth, 0, len(s.order))
+	for _, name := range s.order {
+		executor := s.pairs[name]
+		var problems []string
+		live := true
+		if executor.running {
+			problems = executor.livenessProblems(s.health.MaxMissedIntervals)
+			live = len(problems) == 0
+			problems = append(problems, executor.pair.ReadinessProblems(s.health.MaxConsecutiveFailures)...)
+		}
+		pairs = append(pairs, &pkgModels.PairHealth{
+			Pair:     name,
+			Live:     live,
+			Ready:    len(problems) == 0,
+			Problems: problems,
+		})
+	}
+	return pkgModels.NewHealth(pairs)
+}
+
+func (s *traderExecutor) getPair(name string) (*pairExecutor, error) {
+	executor, found := s.pairs[name]
+	if !found {
+		return nil, fmt.Errorf("%w: %s", models.ErrUnknownPair, name)
+	}
+	return executor, nil
+}
+
+func (s *traderExecutor) stop(ctx context.Context, executor *pairExecutor) error {
+	if err := executor.intervalExecutor.Shutdown(ctx); err != nil {
+		return err
+	}
+	executor.running = false
+	level.Info(executor.pair.Logger).Log("msg", "stopped pair")
+	return nil
+}
+
+func (e *pairExecutor) params() *pkgModels.PairParams {
+	schedule := e.intervalExecutor.Schedule()
+	return &pkgModels.PairParams{
+		Trade: e.traderClient.Params(),
+		Schedule: &pkgModels.ScheduleParams{
+			IntervalExecutionDuration:      pkgModels.Duration(schedule.IntervalDuration),
+			NumOfTradeIterationsInInterval: schedule.NumTasksInInterval,
+		},
+	}
+}
+
+// livenessProblems checks the scheduler of a running pair, and the time of its last iteration, or of its start when
+// it hasn't completed one since.
+func (e *pairExecutor) livenessProblems(maxMissedIntervals int) []string {
+	if !e.intervalExecutor.Running() {
+		return []string{"scheduler exited"}
+	}
+	if maxMissedIntervals <= 0 {
+		return nil
+	}
+	lastRun := e.intervalExecutor.LastRun()
+	if lastRun.Before(e.startedAt) {
+		lastRun = e.startedAt
+	}
+	allowed := time.Duration(maxMissedIntervals) * e.intervalExecutor.Schedule().IntervalDuration
+	if idle := time.Since(lastRun); idle > allowed {
+		return []string{fmt.Sprintf("no iteration completed in %s", idle.Round(time.Second))}
+	}
+	return nil
 }
@@ -0,0 +1,52 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+
+	"github.com/imbonda/vmm-bot/cmd/interfaces"
+	"github.com/imbonda/vmm-bot/cmd/service/health"
+	"github.com/imbonda/vmm-bot/cmd/service/metrics"
+)
+
+// probeServer serves the metrics and the health probes when the control server isn't enabled.
+type probeServer struct {
+	addr   string
+	server *http.Server
+	logger log.Logger
+}
+
+func newProbeServer(listenAddress string, service interfaces.HealthService, logger log.Logger) *probeServer {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+	mux.Handle("/healthz", health.LivenessHandler(service))
+	mux.Handle("/readyz", health.ReadinessHandler(service))
+	return &probeServer{
+		addr: listenAddress,
+		server: &http.Server{
+			Addr:    listenAddress,
+			Handler: mux,
+		},
+		logger: logger,
+	}
+}
+
+func (s *probeServer) Start(ctx context.Context) error {
+	go func() {
+		level.Info(s.logger).Log("msg", "starting probe server on port", "address", s.addr)
+
+		err := s.server.ListenAndServe()
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			level.Error(s.logger).Log("msg", "error starting probe server", "err", err)
+		}
+	}()
+	return nil
+}
+
+func (s *probeServer) Shutdown(ctx context.Context) error {
+	return s.server.Shutdown(ctx)
+}
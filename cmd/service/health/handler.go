@@ -0,0 +1,35 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/imbonda/vmm-bot/cmd/interfaces"
+	"github.com/imbonda/vmm-bot/pkg/models"
+)
+
+// LivenessHandler serves the health of the pairs, with 503 when a pair is stuck and the service should be restarted.
+func LivenessHandler(service interfaces.HealthService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		health := service.GetHealth(r.Context())
+		write(w, health, health.Live)
+	})
+}
+
+// ReadinessHandler serves the health of the pairs, with 503 when a pair can't trade, including when its exchange fails.
+func ReadinessHandler(service interfaces.HealthService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		health := service.GetHealth(r.Context())
+		write(w, health, health.Ready)
+	})
+}
+
+func write(w http.ResponseWriter, health *models.Health, ok bool) {
+	status := http.StatusOK
+	if !ok {
+		status = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(health)
+}
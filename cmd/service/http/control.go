@@ -0,0 +1,216 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log/level"
+
+	"github.com/imbonda/vmm-bot/cmd/interfaces"
+	"github.com/imbonda/vmm-bot/cmd/service/models"
+	"github.com/imbonda/vmm-bot/pkg/exchanges/resilience"
+	pkgModels "github.com/imbonda/vmm-bot/pkg/models"
+)
+
+var ErrPairPaused = fmt.Errorf("pair is paused")
+
+// requestState is the state of a pair traded on request, a paused pair rejects the trade requests.
+type requestState struct {
+	mu      sync.Mutex
+	paused  bool
+	lastRun time.Time
+	lastErr error
+}
+
+func (s *requestState) isPaused() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.paused
+}
+
+func (s *requestState) setPaused(paused bool) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	changed := s.paused != paused
+	s.paused = paused
+	return changed
+}
+
+func (s *requestState) record(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastRun, s.lastErr = time.Now(), err
+}
+
+// The methods below implement interfaces.ControlService for the pairs traded on request.
+
+// GetParams returns the trading parameters of the pair, pairs traded on request have no schedule.
+func (b *TraderBackend) GetParams(ctx context.Context, name string) (*pkgModels.PairParams, error) {
+	traderClient, err := b.findTrader(name)
+	if err != nil {
+		return nil, err
+	}
+	return &pkgModels.PairParams{Trade: traderClient.Params()}, nil
+}
+
+func (b *TraderBackend) UpdateParams(ctx context.Context, name string, params *pkgModels.PairParams) (*pkgModels.ParamsChange, error) {
+	traderClient, err := b.findTrader(name)
+	if err != nil {
+		return nil, err
+	}
+	if params.Schedule != nil {
+		return nil, fmt.Errorf("%w: pairs traded on request have no schedule", pkgModels.ErrInvalidParams)
+	}
+	previous, err := traderClient.UpdateParams(params.Trade)
+	if err != nil {
+		return nil, err
+	}
+	return &pkgModels.ParamsChange{
+		Pair:     name,
+		Previous: &pkgModels.PairParams{Trade: previous},
+		Current:  &pkgModels.PairParams{Trade: traderClient.Params()},
+	}, nil
+}
+
+func (b *TraderBackend) RollbackParams(ctx context.Context, name string) (*pkgModels.ParamsChange, error) {
+	traderClient, err := b.findTrader(name)
+	if err != nil {
+		return nil, err
+	}
+	previous := traderClient.Params()
+	current, err := traderClient.RollbackParams()
+	if err != nil {
+		return nil, err
+	}
+	return &pkgModels.ParamsChange{
+		Pair:     name,
+		Previous: &pkgModels.PairParams{Trade: previous},
+		Current:  &pkgModels.PairParams{Trade: current},
+	}, nil
+}
+
+// GetHealth reports the pairs traded on request as live, and as ready unless they are halted or their clients keep failing.
+func (b *TraderBackend) GetHealth(ctx context.Context) *pkgModels.Health {
+	pairs := make([]*pkgModels.PairHealth, 0, len(b.configs))
+	for _, pair := range b.configs {
+		problems := pair.ReadinessProblems(b.health.MaxConsecutiveFailures)
+		pairs = append(pairs, &pkgModels.PairHealth{
+			Pair:     pair.Name,
+			Live:     true,
+			Ready:    len(problems) == 0,
+			Problems: problems,
+		})
+	}
+	return pkgModels.NewHealth(pairs)
+}
+
+func (b *TraderBackend) findTrader(name string) (interfaces.Trader, error) {
+	traderClient, found := b.traders[name]
+	if !found {
+		return nil, fmt.Errorf("%w: %s", models.ErrUnknownPair, name)
+	}
+	return traderClient, nil
+}
+
+func (b *TraderBackend) StartPair(ctx context.Context, name string) error {
+	if _, err := b.findTrader(name); err != nil {
+		return err
+	}
+	if !b.requests[name].setPaused(false) {
+		return fmt.Errorf("%w: %s", models.ErrPairRunning, name)
+	}
+	level.Info(b.logger).Log("msg", "resumed pair", "pair", name)
+	return nil
+}
+
+func (b *TraderBackend) StopPair(ctx context.Context, name string) error {
+	if _, err := b.findTrader(name); err != nil {
+		return err
+	}
+	if !b.requests[name].setPaused(true) {
+		return fmt.Errorf("%w: %s", models.ErrPairNotRunning, name)
+	}
+	level.Info(b.logger).Log("msg", "paused pair", "pair", name)
+	return nil
+}
+
+func (b *TraderBackend) GetStatus(ctx context.Context, name string) (*pkgModels.PairStatus, error) {
+	traderClient, err := b.findTrader(name)
+	if err != nil {
+		return nil, err
+	}
+	params, err := b.GetParams(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	// The circuits of the status tell why the orders are missing.
+	orders, err := traderClient.GetOpenOrders(ctx)
+	if err != nil && !errors.Is(err, resilience.ErrCircuitOpen) {
+		return nil, err
+	}
+	state := b.requests[name]
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	status := &pkgModels.PairStatus{
+		Pair:       name,
+		Symbol:     b.getSymbol(name),
+		Paused:     state.paused,
+		Params:     params,
+		OpenOrders: orders,
+	}
+	if !state.lastRun.IsZero() {
+		lastRun := state.lastRun
+		status.LastIteration = &lastRun
+	}
+	if state.lastErr != nil {
+		status.LastError = state.lastErr.Error()
+	}
+	if pair, err := b.findPair(name); err == nil {
+		status.Risk = pair.Risk.State()
+		status.Circuits = pair.CircuitStatuses()
+	}
+	return status, nil
+}
+
+func (b *TraderBackend) CancelAllOrders(ctx context.Context, name string) error {
+	traderClient, err := b.findTrader(name)
+	if err != nil {
+		return err
+	}
+	if err = traderClient.CancelAllOrders(ctx); err != nil {
+		return err
+	}
+	level.Info(b.logger).Log("msg", "cancelled all orders", "pair", name)
+	return nil
+}
+
+// ResumeTrading lifts the halt of a pair which breached its risk limits.
+func (b *TraderBackend) ResumeTrading(ctx context.Context, name string) error {
+	pair, err := b.findPair(name)
+	if err != nil {
+		return err
+	}
+	_, err = pair.Risk.Resume(ctx)
+	return err
+}
+
+func (b *TraderBackend) findPair(name string) (*models.PairConfig, error) {
+	for _, pair := range b.configs {
+		if pair.Name == name {
+			return pair, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", models.ErrUnknownPair, name)
+}
+
+func (b *TraderBackend) getSymbol(name string) string {
+	for _, pair := range b.pairs {
+		if pair.Name == name {
+			return pair.Symbol
+		}
+	}
+	return ""
+}
@@ -15,6 +15,472 @@ const docTemplateTraderBackend = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/api/v1/cancel-all": {
+            "post": {
+                "description": "Cancel every open order of the pair, a running pair places new orders from its next iteration",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Cancel the open orders of a pair",
+                "operationId": "cancel_all",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of the pair, required when more than one pair is traded",
+                        "name": "pair",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http.actionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/fills": {
+            "get": {
+                "description": "Get the fills since the given time with their executed volume, average prices and fees",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Fills of the configured symbol",
+                "operationId": "fills",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of the pair, required when more than one pair is traded",
+                        "name": "pair",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC3339 time or a duration back from now, such as 1h (default 24h)",
+                        "name": "since",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.FillsOutput"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/pairs": {
+            "get": {
+                "description": "List the configured pairs and their symbols",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Traded pairs",
+                "operationId": "pairs",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/http.pairResponse"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/paper/state": {
+            "get": {
+                "description": "Get the balances, open orders and fills of the paper exchange",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Paper trading state",
+                "operationId": "paper_state",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PaperState"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/params": {
+            "get": {
+                "description": "Get the parameters of the pair which can be updated while it trades",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Trading parameters of a pair",
+                "operationId": "get_params",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of the pair, required when more than one pair is traded",
+                        "name": "pair",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PairParams"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Update the given parameters of the pair, the omitted ones are kept. The update is applied from the next iteration.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Update the trading parameters of a pair",
+                "operationId": "update_params",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of the pair, required when more than one pair is traded",
+                        "name": "pair",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Parameters to update",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PairParams"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ParamsChange"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/params/rollback": {
+            "post": {
+                "description": "Restore the parameters replaced by the last update, rolling back twice restores the update",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Roll back the trading parameters of a pair",
+                "operationId": "rollback_params",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of the pair, required when more than one pair is traded",
+                        "name": "pair",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ParamsChange"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/pause": {
+            "post": {
+                "description": "Stop the schedule of the pair, or reject its trade requests when traded on request. The open orders are kept.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Pause a pair",
+                "operationId": "pause",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of the pair, required when more than one pair is traded",
+                        "name": "pair",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http.actionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/resume": {
+            "post": {
+                "description": "Start the schedule of the pair again, or accept its trade requests when traded on request",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Resume a pair",
+                "operationId": "resume",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of the pair, required when more than one pair is traded",
+                        "name": "pair",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http.actionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/risk/resume": {
+            "post": {
+                "description": "Lift the halt of a pair which breached its risk limits, its daily limits still apply",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Resume the trading of a halted pair",
+                "operationId": "resume_trading",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of the pair, required when more than one pair is traded",
+                        "name": "pair",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http.actionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/status": {
+            "get": {
+                "description": "Get the params, last iteration, last error and open orders of the pair, or of every pair when omitted",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Status of the pairs",
+                "operationId": "status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of the pair",
+                        "name": "pair",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.PairStatus"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/api/v1/trade": {
             "post": {
                 "description": "Call the trade once method to execute a trade",
@@ -26,6 +492,14 @@ const docTemplateTraderBackend = `{
                 ],
                 "summary": "Trade once for the configure symbol",
                 "operationId": "trade_once",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of the pair, required when more than one pair is traded",
+                        "name": "pair",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -33,17 +507,58 @@ const docTemplateTraderBackend = `{
                             "$ref": "#/definitions/models.TradeOnceOutput"
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/http.errorResponse"
                         }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
                     }
                 }
             }
         }
     },
     "definitions": {
+        "http.actionResponse": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "pair": {
+                    "type": "string"
+                }
+            }
+        },
         "http.errorResponse": {
             "type": "object",
             "properties": {
@@ -52,8 +567,618 @@ const docTemplateTraderBackend = `{
                 }
             }
         },
+        "http.pairResponse": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "oracleSymbol": {
+                    "type": "string"
+                },
+                "symbol": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CircuitState": {
+            "type": "string",
+            "enum": [
+                "closed",
+                "open",
+                "half_open"
+            ],
+            "x-enum-varnames": [
+                "CircuitClosed",
+                "CircuitOpen",
+                "CircuitHalfOpen"
+            ]
+        },
+        "models.CircuitStatus": {
+            "type": "object",
+            "properties": {
+                "consecutiveFailures": {
+                    "type": "integer"
+                },
+                "exchange": {
+                    "type": "string"
+                },
+                "openedAt": {
+                    "type": "string"
+                },
+                "state": {
+                    "$ref": "#/definitions/models.CircuitState"
+                }
+            }
+        },
+        "models.Fill": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "$ref": "#/definitions/models.OrderAction"
+                },
+                "fee": {
+                    "type": "string"
+                },
+                "feeAsset": {
+                    "type": "string"
+                },
+                "maker": {
+                    "type": "boolean"
+                },
+                "orderId": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "string"
+                },
+                "qty": {
+                    "type": "string"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "time": {
+                    "type": "string"
+                },
+                "tradeId": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.FillsOutput": {
+            "type": "object",
+            "properties": {
+                "fills": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Fill"
+                    }
+                },
+                "since": {
+                    "type": "string"
+                },
+                "summary": {
+                    "$ref": "#/definitions/models.FillsSummary"
+                }
+            }
+        },
+        "models.FillsSummary": {
+            "type": "object",
+            "properties": {
+                "avgBuyPrice": {
+                    "type": "number"
+                },
+                "avgSellPrice": {
+                    "type": "number"
+                },
+                "buyQty": {
+                    "type": "number"
+                },
+                "buyQuote": {
+                    "type": "number"
+                },
+                "fees": {
+                    "description": "Fees paid per asset.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
+                "fills": {
+                    "type": "integer"
+                },
+                "sellQty": {
+                    "type": "number"
+                },
+                "sellQuote": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.FundingCheck": {
+            "type": "object",
+            "properties": {
+                "base": {
+                    "type": "string"
+                },
+                "freeBase": {
+                    "type": "number"
+                },
+                "freeQuote": {
+                    "type": "number"
+                },
+                "fundedBuyQty": {
+                    "type": "number"
+                },
+                "fundedSellQty": {
+                    "type": "number"
+                },
+                "quote": {
+                    "type": "string"
+                },
+                "requestedBuyQty": {
+                    "type": "number"
+                },
+                "requestedSellQty": {
+                    "type": "number"
+                },
+                "shrunk": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.InventorySkew": {
+            "type": "object",
+            "properties": {
+                "base": {
+                    "type": "number"
+                },
+                "buyQtyFactor": {
+                    "type": "number"
+                },
+                "buyStopped": {
+                    "type": "boolean"
+                },
+                "deviation": {
+                    "description": "Deviation from the target ratio in [-1, 1], positive when holding too much base and -1/1 at the bounds.",
+                    "type": "number"
+                },
+                "quote": {
+                    "type": "number"
+                },
+                "ratio": {
+                    "description": "Ratio is the share of the base currency value in the inventory value.",
+                    "type": "number"
+                },
+                "sellQtyFactor": {
+                    "type": "number"
+                },
+                "sellStopped": {
+                    "type": "boolean"
+                },
+                "targetRatio": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.OracleCheck": {
+            "type": "object",
+            "properties": {
+                "deviation": {
+                    "description": "Deviation is the distance of the last price from the oracle price, relative to the oracle price.",
+                    "type": "number"
+                },
+                "halted": {
+                    "type": "boolean"
+                },
+                "lastPrice": {
+                    "type": "number"
+                },
+                "oraclePrice": {
+                    "type": "number"
+                },
+                "oracleTickerAge": {
+                    "type": "string",
+                    "example": "1s"
+                },
+                "skipReason": {
+                    "$ref": "#/definitions/models.OracleSkipReason"
+                },
+                "tickerAge": {
+                    "description": "TickerAge and OracleTickerAge are zero when the exchanges don't report the time of their tickers.",
+                    "type": "string",
+                    "example": "1s"
+                }
+            }
+        },
+        "models.OracleSkipReason": {
+            "type": "string",
+            "enum": [
+                "deviation",
+                "stale_ticker",
+                "stale_oracle_ticker",
+                "not_converged"
+            ],
+            "x-enum-varnames": [
+                "OracleSkipDeviation",
+                "OracleSkipStaleTicker",
+                "OracleSkipStaleOracle",
+                "OracleSkipNotConverged"
+            ]
+        },
+        "models.OrderAction": {
+            "type": "string",
+            "enum": [
+                "buy",
+                "sell"
+            ],
+            "x-enum-varnames": [
+                "Buy",
+                "Sell"
+            ]
+        },
+        "models.OrderRecord": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "$ref": "#/definitions/models.OrderAction"
+                },
+                "avgPrice": {
+                    "type": "string"
+                },
+                "clientOrderId": {
+                    "type": "string"
+                },
+                "filledQty": {
+                    "type": "string"
+                },
+                "orderId": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "string"
+                },
+                "qty": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.OrderStatus"
+                },
+                "symbol": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.OrderStatus": {
+            "type": "string",
+            "enum": [
+                "new",
+                "partially_filled",
+                "filled",
+                "cancelled",
+                "rejected",
+                "unknown"
+            ],
+            "x-enum-varnames": [
+                "OrderStatusNew",
+                "OrderStatusPartiallyFilled",
+                "OrderStatusFilled",
+                "OrderStatusCancelled",
+                "OrderStatusRejected",
+                "OrderStatusUnknown"
+            ]
+        },
+        "models.PairParams": {
+            "type": "object",
+            "properties": {
+                "schedule": {
+                    "$ref": "#/definitions/models.ScheduleParams"
+                },
+                "trade": {
+                    "$ref": "#/definitions/models.TradeParams"
+                }
+            }
+        },
+        "models.PairStatus": {
+            "type": "object",
+            "properties": {
+                "circuits": {
+                    "description": "Circuits are the circuit breakers of the exchanges the pair trades on and takes prices from. The open orders are\nomitted while the circuit of the exchange is open.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CircuitStatus"
+                    }
+                },
+                "lastError": {
+                    "type": "string"
+                },
+                "lastIteration": {
+                    "description": "LastIteration is the end time of the last iteration, and LastError its error.",
+                    "type": "string"
+                },
+                "openOrders": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OrderRecord"
+                    }
+                },
+                "pair": {
+                    "type": "string"
+                },
+                "params": {
+                    "$ref": "#/definitions/models.PairParams"
+                },
+                "paused": {
+                    "type": "boolean"
+                },
+                "risk": {
+                    "$ref": "#/definitions/models.RiskState"
+                },
+                "symbol": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PaperAccount": {
+            "type": "object",
+            "properties": {
+                "base": {
+                    "type": "number"
+                },
+                "feesPaid": {
+                    "type": "number"
+                },
+                "filledVolume": {
+                    "type": "number"
+                },
+                "fills": {
+                    "description": "Fills and FilledVolume, in base currency, count every fill of the account, the fills of the state are only the\nlatest ones.",
+                    "type": "integer"
+                },
+                "lockedBase": {
+                    "type": "number"
+                },
+                "lockedQuote": {
+                    "type": "number"
+                },
+                "quote": {
+                    "type": "number"
+                },
+                "symbol": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PaperFill": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "$ref": "#/definitions/models.OrderAction"
+                },
+                "fee": {
+                    "type": "number"
+                },
+                "maker": {
+                    "type": "boolean"
+                },
+                "orderId": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "qty": {
+                    "type": "number"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "time": {
+                    "type": "string"
+                },
+                "tradeId": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PaperState": {
+            "type": "object",
+            "properties": {
+                "accounts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PaperAccount"
+                    }
+                },
+                "fills": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PaperFill"
+                    }
+                },
+                "openOrders": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OrderRecord"
+                    }
+                }
+            }
+        },
+        "models.ParamsChange": {
+            "type": "object",
+            "properties": {
+                "current": {
+                    "$ref": "#/definitions/models.PairParams"
+                },
+                "pair": {
+                    "type": "string"
+                },
+                "previous": {
+                    "$ref": "#/definitions/models.PairParams"
+                }
+            }
+        },
+        "models.PriceBranch": {
+            "type": "string",
+            "enum": [
+                "oracle_range_in_margin",
+                "oracle_lower_in_margin",
+                "oracle_upper_in_margin",
+                "candle_range_in_margin",
+                "candle_lower_in_margin",
+                "candle_upper_in_margin",
+                "oracle_range_in_spread",
+                "oracle_lower_in_spread",
+                "oracle_upper_in_spread",
+                "candle_range_in_spread",
+                "candle_lower_in_spread",
+                "candle_upper_in_spread",
+                "spread_above_oracle",
+                "spread_below_oracle",
+                "spread"
+            ],
+            "x-enum-varnames": [
+                "BranchOracleRangeInMargin",
+                "BranchOracleLowerInMargin",
+                "BranchOracleUpperInMargin",
+                "BranchCandleRangeInMargin",
+                "BranchCandleLowerInMargin",
+                "BranchCandleUpperInMargin",
+                "BranchOracleRangeInSpread",
+                "BranchOracleLowerInSpread",
+                "BranchOracleUpperInSpread",
+                "BranchCandleRangeInSpread",
+                "BranchCandleLowerInSpread",
+                "BranchCandleUpperInSpread",
+                "BranchSpreadAboveOracle",
+                "BranchSpreadBelowOracle",
+                "BranchSpread"
+            ]
+        },
+        "models.PriceDecision": {
+            "type": "object",
+            "properties": {
+                "ask": {
+                    "type": "number"
+                },
+                "bid": {
+                    "type": "number"
+                },
+                "branch": {
+                    "$ref": "#/definitions/models.PriceBranch"
+                },
+                "crossed": {
+                    "type": "boolean"
+                },
+                "lastPrice": {
+                    "type": "number"
+                },
+                "max": {
+                    "type": "number"
+                },
+                "min": {
+                    "type": "number"
+                },
+                "oraclePrice": {
+                    "type": "number"
+                },
+                "price": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.RiskState": {
+            "type": "object",
+            "properties": {
+                "day": {
+                    "description": "Day is the UTC date of the daily totals, which are in quote currency. Volume is the notional of the orders placed\nwithin the day, and FilledVolume the notional of its fills, which also counts the orders placed by others.",
+                    "type": "string"
+                },
+                "fees": {
+                    "type": "number"
+                },
+                "filledVolume": {
+                    "type": "number"
+                },
+                "fillsSyncedAt": {
+                    "description": "FillsSyncedAt is the time of the last fill counted in the daily totals, and SyncedTradeIDs the trade ids of the\nfills of that time, so that the next fills are fetched from it without counting them twice.",
+                    "type": "string"
+                },
+                "halted": {
+                    "type": "boolean"
+                },
+                "haltedAt": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "syncedTradeIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "volume": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.ScheduleParams": {
+            "type": "object",
+            "properties": {
+                "intervalExecutionDuration": {
+                    "type": "string",
+                    "example": "60s"
+                },
+                "numOfTradeIterationsInInterval": {
+                    "type": "integer"
+                }
+            }
+        },
         "models.TradeOnceOutput": {
-            "type": "object"
+            "type": "object",
+            "properties": {
+                "funding": {
+                    "$ref": "#/definitions/models.FundingCheck"
+                },
+                "inventory": {
+                    "$ref": "#/definitions/models.InventorySkew"
+                },
+                "oracle": {
+                    "$ref": "#/definitions/models.OracleCheck"
+                },
+                "orders": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OrderRecord"
+                    }
+                },
+                "pricing": {
+                    "$ref": "#/definitions/models.PriceDecision"
+                }
+            }
+        },
+        "models.TradeParams": {
+            "type": "object",
+            "properties": {
+                "candleHeight": {
+                    "type": "number"
+                },
+                "spreadMarginLower": {
+                    "type": "number"
+                },
+                "spreadMarginUpper": {
+                    "type": "number"
+                },
+                "tradeAmountMax": {
+                    "type": "number"
+                },
+                "tradeAmountMin": {
+                    "type": "number"
+                }
+            }
         }
     }
 }`
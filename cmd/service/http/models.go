@@ -1,5 +1,50 @@
 package http
 
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-kit/log"
+
+	"github.com/imbonda/vmm-bot/cmd/interfaces"
+	"github.com/imbonda/vmm-bot/cmd/service/models"
+)
+
+const defaultFillsWindow = 24 * time.Hour
+
+type NewTraderBackendInput struct {
+	ListenAddress string
+	Pairs         []*models.PairConfig
+	Traders       map[string]interfaces.Trader
+	// Control runs the pairs, when nil they're traded on request.
+	Control interfaces.ControlService
+	Health  models.HealthConfig
+	Logger  log.Logger
+}
+
 type errorResponse struct {
 	Error string `json:"error"`
 }
+
+type pairResponse struct {
+	Name         string `json:"name"`
+	Symbol       string `json:"symbol"`
+	OracleSymbol string `json:"oracleSymbol"`
+}
+
+type actionResponse struct {
+	Pair   string `json:"pair"`
+	Action string `json:"action"`
+}
+
+// parseSince accepts either an RFC3339 time or a duration to look back from now.
+func parseSince(value string) (time.Time, error) {
+	if since, err := time.Parse(time.RFC3339, value); err == nil {
+		return since, nil
+	}
+	window, err := time.ParseDuration(value)
+	if err != nil || window < 0 {
+		return time.Time{}, fmt.Errorf("invalid since: %s", value)
+	}
+	return time.Now().Add(-window), nil
+}
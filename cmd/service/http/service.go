@@ -2,6 +2,9 @@ package http
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -11,37 +14,68 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 
 	"github.com/imbonda/vmm-bot/cmd/interfaces"
+	"github.com/imbonda/vmm-bot/cmd/service/health"
 	"github.com/imbonda/vmm-bot/cmd/service/http/docs"
+	"github.com/imbonda/vmm-bot/cmd/service/metrics"
 	"github.com/imbonda/vmm-bot/cmd/service/models"
+	"github.com/imbonda/vmm-bot/internal/risk"
 	"github.com/imbonda/vmm-bot/internal/trader"
+	exchangeErrors "github.com/imbonda/vmm-bot/pkg/exchanges/errors"
+	pkgModels "github.com/imbonda/vmm-bot/pkg/models"
+	"github.com/imbonda/vmm-bot/pkg/utils"
 )
 
 // @title Trader API
 // @version 1.0
 // @description This is a sample server to perform symbol trading requests
 type TraderBackend struct {
-	addr   string
-	server *http.Server
-	trader interfaces.Trader
-	logger log.Logger
+	addr    string
+	server  *http.Server
+	traders map[string]interfaces.Trader
+	pairs   []pairResponse
+	paper   interfaces.PaperExchangeClient
+	// control runs the pairs, it's the backend itself when they're traded on request.
+	control  interfaces.ControlService
+	requests map[string]*requestState
+	configs  []*models.PairConfig
+	health   models.HealthConfig
+	logger   log.Logger
 }
 
 func NewTraderService(ctx context.Context, input *models.NewTraderServiceInput) (interfaces.TraderService, error) {
-	traderClient, err := trader.NewTrader(ctx, &trader.NewTraderInput{
-		ExchangeClient:    input.ExchangeClient,
-		PriceOracleClient: input.PriceOracleClient,
-		Symbol:            input.Trade.Symbol,
-		OracleSymbol:      input.Trade.OracleSymbol,
-		SpreadMarginLower: input.Trade.SpreadMarginLower,
-		SpreadMarginUpper: input.Trade.SpreadMarginUpper,
-		TradeAmountMin:    input.Trade.TradeAmountMin,
-		TradeAmountMax:    input.Trade.TradeAmountMax,
-		PriceDecimals:     input.Trade.PriceDecimals,
-		AmountDecimals:    input.Trade.AmountDecimals,
-		Logger:            input.Logger,
+	traders := make(map[string]interfaces.Trader)
+	for _, pair := range input.Pairs {
+		traderClient, err := pair.NewTrader(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("pair %s: %w", pair.Name, err)
+		}
+		traders[pair.Name] = traderClient
+	}
+	return NewTraderBackend(ctx, &NewTraderBackendInput{
+		ListenAddress: input.Executor.ListenAddress,
+		Pairs:         input.Pairs,
+		Traders:       traders,
+		Health:        input.Health,
+		Logger:        input.Logger,
 	})
-	if err != nil {
-		return nil, err
+}
+
+// NewTraderBackend serves the API of the traders, either traded on request or run by the given control service.
+func NewTraderBackend(ctx context.Context, input *NewTraderBackendInput) (*TraderBackend, error) {
+	pairs := make([]pairResponse, 0, len(input.Pairs))
+	var paperClient interfaces.PaperExchangeClient
+	for _, pair := range input.Pairs {
+		if _, found := input.Traders[pair.Name]; !found {
+			return nil, fmt.Errorf("%w: missing the trader of %s", models.ErrUnknownPair, pair.Name)
+		}
+		pairs = append(pairs, pairResponse{
+			Name:         pair.Name,
+			Symbol:       pair.Trade.Symbol,
+			OracleSymbol: pair.Trade.OracleSymbol,
+		})
+		if client, ok := pair.ExchangeClient.(interfaces.PaperExchangeClient); ok {
+			paperClient = client
+		}
 	}
 	router := gin.Default()
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler, func(config *ginSwagger.Config) {
@@ -49,19 +83,47 @@ func NewTraderService(ctx context.Context, input *models.NewTraderServiceInput)
 	}))
 
 	backend := &TraderBackend{
-		addr: input.Executor.ListenAddress,
+		addr: input.ListenAddress,
 		server: &http.Server{
-			Addr:    input.Executor.ListenAddress,
+			Addr:    input.ListenAddress,
 			Handler: router,
 		},
-		trader: traderClient,
-		logger: input.Logger,
+		traders: input.Traders,
+		pairs:   pairs,
+		control: input.Control,
+		configs: input.Pairs,
+		health:  input.Health,
+		logger:  input.Logger,
+	}
+	if backend.control == nil {
+		backend.control = backend
+		backend.requests = make(map[string]*requestState)
+		for _, pair := range pairs {
+			backend.requests[pair.Name] = &requestState{}
+		}
 	}
 
 	// Register routes
+	router.GET("/metrics", gin.WrapH(metrics.Handler()))
+	router.GET("/healthz", gin.WrapH(health.LivenessHandler(backend.control)))
+	router.GET("/readyz", gin.WrapH(health.ReadinessHandler(backend.control)))
 	v1 := router.Group("/api/v1")
 	{
+		v1.GET("/pairs", backend.handlePairs)
 		v1.POST("/trade", backend.handleTrade)
+		v1.GET("/fills", backend.handleFills)
+		v1.GET("/params", backend.handleGetParams)
+		v1.PATCH("/params", backend.handleUpdateParams)
+		v1.POST("/params/rollback", backend.handleRollbackParams)
+		v1.GET("/status", backend.handleStatus)
+		v1.POST("/pause", backend.handlePause)
+		v1.POST("/resume", backend.handleResume)
+		v1.POST("/cancel-all", backend.handleCancelAll)
+		v1.POST("/risk/resume", backend.handleResumeTrading)
+	}
+	if paperClient != nil {
+		backend.paper = paperClient
+		v1.GET("/paper/state", backend.handlePaperState)
 	}
 
 	return backend, nil
@@ -83,18 +145,142 @@ func (b *TraderBackend) Shutdown(ctx context.Context) error {
 	return b.server.Shutdown(ctx)
 }
 
+// getPairName resolves the pair query parameter, which may be omitted when a single pair is traded.
+func (b *TraderBackend) getPairName(c *gin.Context) (string, bool) {
+	name := c.Query("pair")
+	if name == "" {
+		if len(b.pairs) != 1 {
+			c.JSON(http.StatusBadRequest, errorResponse{
+				Error: "the pair query parameter is required when more than one pair is traded",
+			})
+			return "", false
+		}
+		name = b.pairs[0].Name
+	}
+	return name, true
+}
+
+// getTrader resolves the pair query parameter and its trader.
+func (b *TraderBackend) getTrader(c *gin.Context) (string, interfaces.Trader, bool) {
+	name, ok := b.getPairName(c)
+	if !ok {
+		return "", nil, false
+	}
+	traderClient, err := b.findTrader(name)
+	if err != nil {
+		c.JSON(http.StatusNotFound, errorResponse{
+			Error: err.Error(),
+		})
+		return "", nil, false
+	}
+	return name, traderClient, true
+}
+
+// errorStatus maps the errors of the control service to the status codes of the responses.
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrUnknownPair):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrPairRunning),
+		errors.Is(err, models.ErrPairNotRunning),
+		errors.Is(err, ErrPairPaused),
+		errors.Is(err, risk.ErrTradingHalted),
+		errors.Is(err, risk.ErrRiskLimitBreached),
+		errors.Is(err, risk.ErrNotHalted),
+		errors.Is(err, trader.ErrOracleCheckFailed),
+		errors.Is(err, exchangeErrors.ErrInsufficientBalance),
+		errors.Is(err, exchangeErrors.ErrSymbolHalted):
+		return http.StatusConflict
+	case errors.Is(err, pkgModels.ErrInvalidParams),
+		errors.Is(err, trader.ErrNoPreviousParams),
+		errors.Is(err, utils.ErrNoPreviousSchedule):
+		return http.StatusBadRequest
+	// The exchange rate limits and outages, client side ones included.
+	case errors.Is(err, exchangeErrors.ErrRateLimited):
+		return http.StatusTooManyRequests
+	case errors.Is(err, exchangeErrors.ErrTransient):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// @Summary		Traded pairs
+// @Description	List the configured pairs and their symbols
+// @ID			pairs
+// @Produce		json
+// @Success		200		{array}		pairResponse
+// @Router			/api/v1/pairs [get]
+func (b *TraderBackend) handlePairs(c *gin.Context) {
+	c.JSON(http.StatusOK, b.pairs)
+}
+
 // @Summary		Trade once for the configure symbol
 // @Description	Call the trade once method to execute a trade
 // @ID			trade_once
 // @Accept		json
 // @Produce		json
+// @Param		pair	query		string	false	"Name of the pair, required when more than one pair is traded"
 // @Success		200		{object}	models.TradeOnceOutput
+// @Failure		400		{object} 	errorResponse
+// @Failure		404		{object} 	errorResponse
+// @Failure		409		{object} 	errorResponse
+// @Failure		429		{object} 	errorResponse
+// @Failure		503		{object} 	errorResponse
 // @Failure		500		{object} 	errorResponse
 // @Router			/api/v1/trade [post]
 func (b *TraderBackend) handleTrade(c *gin.Context) {
-	output, err := b.trader.TradeOnce(c.Request.Context())
+	name, traderClient, ok := b.getTrader(c)
+	if !ok {
+		return
+	}
+	state := b.requests[name]
+	if state != nil && state.isPaused() {
+		c.JSON(http.StatusConflict, errorResponse{
+			Error: fmt.Sprintf("%s: %s", ErrPairPaused, name),
+		})
+		return
+	}
+	output, err := traderClient.TradeOnce(c.Request.Context())
+	if state != nil {
+		state.record(err)
+	}
 	if err != nil {
 		level.Error(b.logger).Log("msg", "error executing trade", "err", err)
+		c.JSON(errorStatus(err), errorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, output)
+}
+
+// @Summary		Fills of the configured symbol
+// @Description	Get the fills since the given time with their executed volume, average prices and fees
+// @ID			fills
+// @Produce		json
+// @Param		pair	query		string	false	"Name of the pair, required when more than one pair is traded"
+// @Param		since	query		string	false	"RFC3339 time or a duration back from now, such as 1h (default 24h)"
+// @Success		200		{object}	models.FillsOutput
+// @Failure		400		{object} 	errorResponse
+// @Failure		404		{object} 	errorResponse
+// @Failure		500		{object} 	errorResponse
+// @Router			/api/v1/fills [get]
+func (b *TraderBackend) handleFills(c *gin.Context) {
+	_, traderClient, ok := b.getTrader(c)
+	if !ok {
+		return
+	}
+	since, err := parseSince(c.DefaultQuery("since", defaultFillsWindow.String()))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+	output, err := traderClient.GetFills(c.Request.Context(), since)
+	if err != nil {
+		level.Error(b.logger).Log("msg", "error getting fills", "err", err)
 		c.JSON(http.StatusInternalServerError, errorResponse{
 			Error: err.Error(),
 		})
@@ -102,3 +288,226 @@ func (b *TraderBackend) handleTrade(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, output)
 }
+
+// @Summary		Paper trading state
+// @Description	Get the balances, open orders and fills of the paper exchange
+// @ID			paper_state
+// @Produce		json
+// @Success		200		{object}	models.PaperState
+// @Failure		500		{object} 	errorResponse
+// @Router			/api/v1/paper/state [get]
+func (b *TraderBackend) handlePaperState(c *gin.Context) {
+	state, err := b.paper.GetState(c.Request.Context())
+	if err != nil {
+		level.Error(b.logger).Log("msg", "error getting paper state", "err", err)
+		c.JSON(http.StatusInternalServerError, errorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, state)
+}
+
+// @Summary		Trading parameters of a pair
+// @Description	Get the parameters of the pair which can be updated while it trades
+// @ID			get_params
+// @Produce		json
+// @Param		pair	query		string	false	"Name of the pair, required when more than one pair is traded"
+// @Success		200		{object}	models.PairParams
+// @Failure		400		{object} 	errorResponse
+// @Failure		404		{object} 	errorResponse
+// @Router			/api/v1/params [get]
+func (b *TraderBackend) handleGetParams(c *gin.Context) {
+	name, ok := b.getPairName(c)
+	if !ok {
+		return
+	}
+	params, err := b.control.GetParams(c.Request.Context(), name)
+	if err != nil {
+		c.JSON(errorStatus(err), errorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, params)
+}
+
+// @Summary		Update the trading parameters of a pair
+// @Description	Update the given parameters of the pair, the omitted ones are kept. The update is applied from the next iteration.
+// @ID			update_params
+// @Accept		json
+// @Produce		json
+// @Param		pair	query		string				false	"Name of the pair, required when more than one pair is traded"
+// @Param		params	body		models.PairParams	true	"Parameters to update"
+// @Success		200		{object}	models.ParamsChange
+// @Failure		400		{object} 	errorResponse
+// @Failure		404		{object} 	errorResponse
+// @Failure		500		{object} 	errorResponse
+// @Router			/api/v1/params [patch]
+func (b *TraderBackend) handleUpdateParams(c *gin.Context) {
+	name, ok := b.getPairName(c)
+	if !ok {
+		return
+	}
+	current, err := b.control.GetParams(c.Request.Context(), name)
+	if err != nil {
+		c.JSON(errorStatus(err), errorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+	// Decoding onto the current parameters keeps the omitted ones.
+	params := current.Clone()
+	decoder := json.NewDecoder(c.Request.Body)
+	decoder.DisallowUnknownFields()
+	if err = decoder.Decode(params); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse{
+			Error: fmt.Sprintf("invalid params: %s", err),
+		})
+		return
+	}
+	change, err := b.control.UpdateParams(c.Request.Context(), name, params)
+	if err != nil {
+		level.Error(b.logger).Log("msg", "error updating params", "pair", name, "err", err)
+		c.JSON(errorStatus(err), errorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, change)
+}
+
+// @Summary		Roll back the trading parameters of a pair
+// @Description	Restore the parameters replaced by the last update, rolling back twice restores the update
+// @ID			rollback_params
+// @Produce		json
+// @Param		pair	query		string	false	"Name of the pair, required when more than one pair is traded"
+// @Success		200		{object}	models.ParamsChange
+// @Failure		400		{object} 	errorResponse
+// @Failure		404		{object} 	errorResponse
+// @Failure		500		{object} 	errorResponse
+// @Router			/api/v1/params/rollback [post]
+func (b *TraderBackend) handleRollbackParams(c *gin.Context) {
+	name, ok := b.getPairName(c)
+	if !ok {
+		return
+	}
+	change, err := b.control.RollbackParams(c.Request.Context(), name)
+	if err != nil {
+		level.Error(b.logger).Log("msg", "error rolling back params", "pair", name, "err", err)
+		c.JSON(errorStatus(err), errorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, change)
+}
+
+// @Summary		Status of the pairs
+// @Description	Get the params, last iteration, last error and open orders of the pair, or of every pair when omitted
+// @ID			status
+// @Produce		json
+// @Param		pair	query		string	false	"Name of the pair"
+// @Success		200		{array}		models.PairStatus
+// @Failure		404		{object} 	errorResponse
+// @Failure		500		{object} 	errorResponse
+// @Router			/api/v1/status [get]
+func (b *TraderBackend) handleStatus(c *gin.Context) {
+	names := []string{c.Query("pair")}
+	if names[0] == "" {
+		names = names[:0]
+		for _, pair := range b.pairs {
+			names = append(names, pair.Name)
+		}
+	}
+	statuses := make([]*pkgModels.PairStatus, 0, len(names))
+	for _, name := range names {
+		status, err := b.control.GetStatus(c.Request.Context(), name)
+		if err != nil {
+			level.Error(b.logger).Log("msg", "error getting status", "pair", name, "err", err)
+			c.JSON(errorStatus(err), errorResponse{
+				Error: err.Error(),
+			})
+			return
+		}
+		statuses = append(statuses, status)
+	}
+	c.JSON(http.StatusOK, statuses)
+}
+
+// @Summary		Pause a pair
+// @Description	Stop the schedule of the pair, or reject its trade requests when traded on request. The open orders are kept.
+// @ID			pause
+// @Produce		json
+// @Param		pair	query		string	false	"Name of the pair, required when more than one pair is traded"
+// @Success		200		{object}	actionResponse
+// @Failure		400		{object} 	errorResponse
+// @Failure		404		{object} 	errorResponse
+// @Failure		409		{object} 	errorResponse
+// @Failure		500		{object} 	errorResponse
+// @Router			/api/v1/pause [post]
+func (b *TraderBackend) handlePause(c *gin.Context) {
+	b.handleAction(c, "pause", b.control.StopPair)
+}
+
+// @Summary		Resume a pair
+// @Description	Start the schedule of the pair again, or accept its trade requests when traded on request
+// @ID			resume
+// @Produce		json
+// @Param		pair	query		string	false	"Name of the pair, required when more than one pair is traded"
+// @Success		200		{object}	actionResponse
+// @Failure		400		{object} 	errorResponse
+// @Failure		404		{object} 	errorResponse
+// @Failure		409		{object} 	errorResponse
+// @Failure		500		{object} 	errorResponse
+// @Router			/api/v1/resume [post]
+func (b *TraderBackend) handleResume(c *gin.Context) {
+	b.handleAction(c, "resume", b.control.StartPair)
+}
+
+// @Summary		Cancel the open orders of a pair
+// @Description	Cancel every open order of the pair, a running pair places new orders from its next iteration
+// @ID			cancel_all
+// @Produce		json
+// @Param		pair	query		string	false	"Name of the pair, required when more than one pair is traded"
+// @Success		200		{object}	actionResponse
+// @Failure		400		{object} 	errorResponse
+// @Failure		404		{object} 	errorResponse
+// @Failure		500		{object} 	errorResponse
+// @Router			/api/v1/cancel-all [post]
+func (b *TraderBackend) handleCancelAll(c *gin.Context) {
+	b.handleAction(c, "cancel-all", b.control.CancelAllOrders)
+}
+
+// @Summary		Resume the trading of a halted pair
+// @Description	Lift the halt of a pair which breached its risk limits, its daily limits still apply
+// @ID			resume_trading
+// @Produce		json
+// @Param		pair	query		string	false	"Name of the pair, required when more than one pair is traded"
+// @Success		200		{object}	actionResponse
+// @Failure		400		{object} 	errorResponse
+// @Failure		404		{object} 	errorResponse
+// @Failure		409		{object} 	errorResponse
+// @Failure		500		{object} 	errorResponse
+// @Router			/api/v1/risk/resume [post]
+func (b *TraderBackend) handleResumeTrading(c *gin.Context) {
+	b.handleAction(c, "resume-trading", b.control.ResumeTrading)
+}
+
+func (b *TraderBackend) handleAction(c *gin.Context, action string, do func(ctx context.Context, name string) error) {
+	name, ok := b.getPairName(c)
+	if !ok {
+		return
+	}
+	if err := do(c.Request.Context(), name); err != nil {
+		level.Error(b.logger).Log("msg", "error executing action", "action", action, "pair", name, "err", err)
+		c.JSON(errorStatus(err), errorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, actionResponse{
+		Pair:   name,
+		Action: action,
+	})
+}
@@ -0,0 +1,113 @@
+package metrics
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/imbonda/vmm-bot/cmd/interfaces"
+	exchangeErrors "github.com/imbonda/vmm-bot/pkg/exchanges/errors"
+	"github.com/imbonda/vmm-bot/pkg/models"
+)
+
+// ExchangeClient records the latency of the API requests of an exchange client, the orders it places
+// and its consecutive failures.
+type ExchangeClient struct {
+	client   interfaces.ExchangeClient
+	exchange string
+	failures atomic.Int64
+}
+
+func NewExchangeClient(exchange string, client interfaces.ExchangeClient) *ExchangeClient {
+	return &ExchangeClient{
+		client:   client,
+		exchange: exchange,
+	}
+}
+
+func (c *ExchangeClient) GetOrderBook(ctx context.Context, symbol string) (book *models.OrderBook, err error) {
+	defer c.observe("get_order_book", time.Now(), &err)
+	return c.client.GetOrderBook(ctx, symbol)
+}
+
+func (c *ExchangeClient) GetLastTicker(ctx context.Context, symbol string) (ticker *models.Ticker, err error) {
+	defer c.observe("get_last_ticker", time.Now(), &err)
+	return c.client.GetLastTicker(ctx, symbol)
+}
+
+func (c *ExchangeClient) PlaceOrder(ctx context.Context, order *models.Order) (record *models.OrderRecord, err error) {
+	defer c.observe("place_order", time.Now(), &err)
+	record, err = c.client.PlaceOrder(ctx, order)
+	if err != nil {
+		ordersFailed.WithLabelValues(c.exchange, string(order.Action)).Inc()
+	} else {
+		ordersPlaced.WithLabelValues(c.exchange, string(order.Action)).Inc()
+	}
+	return record, err
+}
+
+func (c *ExchangeClient) GetOrder(ctx context.Context, symbol string, orderID string) (record *models.OrderRecord, err error) {
+	defer c.observe("get_order", time.Now(), &err)
+	return c.client.GetOrder(ctx, symbol, orderID)
+}
+
+func (c *ExchangeClient) GetOrderByClientID(ctx context.Context, symbol string, clientOrderID string) (record *models.OrderRecord, err error) {
+	defer c.observe("get_order_by_client_id", time.Now(), &err)
+	return c.client.GetOrderByClientID(ctx, symbol, clientOrderID)
+}
+
+func (c *ExchangeClient) ListOpenOrders(ctx context.Context, symbol string) (records []*models.OrderRecord, err error) {
+	defer c.observe("list_open_orders", time.Now(), &err)
+	return c.client.ListOpenOrders(ctx, symbol)
+}
+
+func (c *ExchangeClient) CancelOrder(ctx context.Context, symbol string, orderID string) (err error) {
+	defer c.observe("cancel_order", time.Now(), &err)
+	return c.client.CancelOrder(ctx, symbol, orderID)
+}
+
+func (c *ExchangeClient) CancelAllOrders(ctx context.Context, symbol string) (err error) {
+	defer c.observe("cancel_all_orders", time.Now(), &err)
+	return c.client.CancelAllOrders(ctx, symbol)
+}
+
+func (c *ExchangeClient) GetFills(ctx context.Context, symbol string, since time.Time) (fills []*models.Fill, err error) {
+	defer c.observe("get_fills", time.Now(), &err)
+	return c.client.GetFills(ctx, symbol, since)
+}
+
+func (c *ExchangeClient) GetBalances(ctx context.Context) (balances []*models.Balance, err error) {
+	defer c.observe("get_balances", time.Now(), &err)
+	return c.client.GetBalances(ctx)
+}
+
+func (c *ExchangeClient) GetSymbolInfo(ctx context.Context, symbol string) (info *models.SymbolInfo, err error) {
+	defer c.observe("get_symbol_info", time.Now(), &err)
+	return c.client.GetSymbolInfo(ctx, symbol)
+}
+
+// ConsecutiveFailures returns the number of requests which failed since the last successful one.
+func (c *ExchangeClient) ConsecutiveFailures() int {
+	return int(c.failures.Load())
+}
+
+// observe is deferred with the address of the named error result, so that it reads the returned error.
+// The rejections of the exchange, like an order it can't fund, show the client works and aren't counted as failures.
+func (c *ExchangeClient) observe(endpoint string, start time.Time, err *error) {
+	result := "success"
+	switch {
+	case *err == nil:
+		c.failures.Store(0)
+	case exchangeErrors.IsRejection(*err):
+		result = "error"
+		c.failures.Store(0)
+	default:
+		result = "error"
+		c.failures.Add(1)
+	}
+	if *err != nil {
+		exchangeFailures.WithLabelValues(c.exchange, endpoint, exchangeErrors.ClassName(*err)).Inc()
+	}
+	consecutiveFailures.WithLabelValues(c.exchange).Set(float64(c.failures.Load()))
+	exchangeLatency.WithLabelValues(c.exchange, endpoint, result).Observe(time.Since(start).Seconds())
+}
@@ -1 +1,87 @@
 package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "vmm"
+
+var (
+	iterationsStarted = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "iterations_started_total",
+		Help:      "Trade iterations started, scheduled or requested.",
+	}, []string{"pair"})
+	iterationsFailed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "iterations_failed_total",
+		Help:      "Trade iterations which returned an error.",
+	}, []string{"pair"})
+	lastSuccessfulIteration = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "last_successful_iteration_timestamp_seconds",
+		Help:      "Unix time of the end of the last successful trade iteration.",
+	}, []string{"pair"})
+	pricingBranches = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "pricing_branch_total",
+		Help:      "Pricing branches chosen for the trade price.",
+	}, []string{"pair", "branch"})
+	spreadWidth = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "spread_width_ratio",
+		Help:      "Width of the spread relative to its mid price, negative when crossed.",
+		Buckets:   []float64{-0.001, 0, 0.0005, 0.001, 0.002, 0.005, 0.01, 0.02, 0.05, 0.1},
+	}, []string{"pair"})
+	oracleDeviation = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "oracle_deviation_ratio",
+		Help:      "Deviation of the last price from the oracle price, relative to the oracle price.",
+		Buckets:   []float64{-0.05, -0.02, -0.01, -0.005, -0.002, -0.001, 0, 0.001, 0.002, 0.005, 0.01, 0.02, 0.05},
+	}, []string{"pair"})
+	oracleSkips = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "oracle_check_skips_total",
+		Help:      "Trade iterations skipped by the oracle check, by reason.",
+	}, []string{"pair", "reason"})
+	oracleHalted = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "oracle_check_halted",
+		Help:      "Whether the trading of the pair is halted until the venue and oracle prices reconverge.",
+	}, []string{"pair"})
+	ordersPlaced = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "orders_placed_total",
+		Help:      "Orders accepted by the exchange.",
+	}, []string{"exchange", "side"})
+	ordersFailed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "orders_failed_total",
+		Help:      "Orders which failed to be placed.",
+	}, []string{"exchange", "side"})
+	exchangeLatency = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "exchange_request_duration_seconds",
+		Help:      "Latency of the exchange API requests.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"exchange", "endpoint", "result"})
+	exchangeFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "exchange_errors_total",
+		Help:      "Exchange API requests which failed, by the class of their failure.",
+	}, []string{"exchange", "endpoint", "class"})
+	consecutiveFailures = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "exchange_consecutive_failures",
+		Help:      "Exchange API requests which failed since the last successful one.",
+	}, []string{"exchange"})
+)
+
+// Handler serves the metrics in the Prometheus exposition format.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
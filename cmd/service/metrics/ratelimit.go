@@ -0,0 +1,34 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/imbonda/vmm-bot/pkg/exchanges/ratelimit"
+)
+
+// RegisterRateLimiter exports the remaining budget of every endpoint class of the limiter, and its throttled and
+// rejected requests. It's called once per exchange client.
+func RegisterRateLimiter(limiter *ratelimit.Limiter) {
+	for _, class := range limiter.Classes() {
+		labels := prometheus.Labels{"exchange": limiter.Exchange(), "class": string(class)}
+		promauto.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace:   namespace,
+			Name:        "rate_limit_remaining",
+			Help:        "Request weight the endpoint class may still take without waiting.",
+			ConstLabels: labels,
+		}, func() float64 { return limiter.Remaining(class) })
+		promauto.NewCounterFunc(prometheus.CounterOpts{
+			Namespace:   namespace,
+			Name:        "rate_limit_throttled_total",
+			Help:        "Requests which waited for the rate limit budget of their endpoint class.",
+			ConstLabels: labels,
+		}, func() float64 { return float64(limiter.Throttled(class)) })
+		promauto.NewCounterFunc(prometheus.CounterOpts{
+			Namespace:   namespace,
+			Name:        "rate_limit_rejected_total",
+			Help:        "Requests which failed without reaching the exchange, as their rate limit budget ran out.",
+			ConstLabels: labels,
+		}, func() float64 { return float64(limiter.Rejected(class)) })
+	}
+}
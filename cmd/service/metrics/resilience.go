@@ -0,0 +1,44 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/imbonda/vmm-bot/pkg/exchanges/resilience"
+	"github.com/imbonda/vmm-bot/pkg/models"
+)
+
+// circuitStates are the values the circuit breaker state gauge exports.
+var circuitStates = map[models.CircuitState]float64{
+	models.CircuitClosed:   0,
+	models.CircuitHalfOpen: 1,
+	models.CircuitOpen:     2,
+}
+
+// RegisterResiliencePolicy exports the retries of the policy, and the state of its circuit breaker. It's called once
+// per exchange client.
+func RegisterResiliencePolicy(policy *resilience.Policy) {
+	labels := prometheus.Labels{"exchange": policy.Exchange()}
+	promauto.NewCounterFunc(prometheus.CounterOpts{
+		Namespace:   namespace,
+		Name:        "exchange_retries_total",
+		Help:        "Exchange requests sent again after a network or server side failure.",
+		ConstLabels: labels,
+	}, func() float64 { return float64(policy.Retries()) })
+	breaker := policy.Breaker()
+	if breaker == nil {
+		return
+	}
+	promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace:   namespace,
+		Name:        "circuit_breaker_state",
+		Help:        "State of the circuit breaker of the exchange: 0 closed, 1 half open, 2 open.",
+		ConstLabels: labels,
+	}, func() float64 { return circuitStates[breaker.Status().State] })
+	promauto.NewCounterFunc(prometheus.CounterOpts{
+		Namespace:   namespace,
+		Name:        "circuit_breaker_opened_total",
+		Help:        "Times the circuit breaker of the exchange opened.",
+		ConstLabels: labels,
+	}, func() float64 { return float64(breaker.Opened()) })
+}
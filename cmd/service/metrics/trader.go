@@ -0,0 +1,86 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"runtime/debug"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+
+	"github.com/imbonda/vmm-bot/cmd/interfaces"
+	"github.com/imbonda/vmm-bot/internal/trader"
+	"github.com/imbonda/vmm-bot/pkg/models"
+)
+
+// Trader records the iterations of the trader of a pair.
+type Trader struct {
+	interfaces.Trader
+	pair   string
+	logger log.Logger
+}
+
+func NewTrader(pair string, trader interfaces.Trader, logger log.Logger) *Trader {
+	return &Trader{
+		Trader: trader,
+		pair:   pair,
+		logger: logger,
+	}
+}
+
+// DoIteration trades once through the recording TradeOnce, so it recovers the panics of the iteration itself.
+func (t *Trader) DoIteration(ctx context.Context) error {
+	defer func() {
+		if r := recover(); r != nil {
+			iterationsFailed.WithLabelValues(t.pair).Inc()
+			level.Error(t.logger).Log("msg", "panic recovered in iteration", "err", r)
+			debug.PrintStack()
+		}
+	}()
+	_, err := t.TradeOnce(ctx)
+	return err
+}
+
+func (t *Trader) TradeOnce(ctx context.Context) (*models.TradeOnceOutput, error) {
+	iterationsStarted.WithLabelValues(t.pair).Inc()
+	output, err := t.Trader.TradeOnce(ctx)
+	switch {
+	case errors.Is(err, trader.ErrOracleCheckFailed):
+		// Skipped iterations are counted by their reason.
+	case err != nil:
+		iterationsFailed.WithLabelValues(t.pair).Inc()
+	default:
+		lastSuccessfulIteration.WithLabelValues(t.pair).Set(float64(time.Now().Unix()))
+	}
+	if output != nil && output.Pricing != nil {
+		t.observePricing(output.Pricing)
+	}
+	if output != nil && output.Oracle != nil {
+		t.observeOracle(output.Oracle)
+	}
+	return output, err
+}
+
+func (t *Trader) observePricing(pricing *models.PriceDecision) {
+	if pricing.Branch != "" {
+		pricingBranches.WithLabelValues(t.pair, string(pricing.Branch)).Inc()
+	}
+	if mid := (pricing.Ask + pricing.Bid) / 2; mid > 0 {
+		spreadWidth.WithLabelValues(t.pair).Observe((pricing.Ask - pricing.Bid) / mid)
+	}
+	if pricing.OraclePrice > 0 {
+		oracleDeviation.WithLabelValues(t.pair).Observe((pricing.LastPrice - pricing.OraclePrice) / pricing.OraclePrice)
+	}
+}
+
+func (t *Trader) observeOracle(check *models.OracleCheck) {
+	if check.SkipReason != "" {
+		oracleSkips.WithLabelValues(t.pair, string(check.SkipReason)).Inc()
+	}
+	halted := 0.0
+	if check.Halted {
+		halted = 1
+	}
+	oracleHalted.WithLabelValues(t.pair).Set(halted)
+}
@@ -1,11 +1,26 @@
 package models
 
 import (
+	"context"
+	"fmt"
 	"time"
 
 	"github.com/go-kit/log"
+	"github.com/samber/lo"
 
 	"github.com/imbonda/vmm-bot/cmd/interfaces"
+	"github.com/imbonda/vmm-bot/cmd/service/metrics"
+	"github.com/imbonda/vmm-bot/internal/marketdata"
+	"github.com/imbonda/vmm-bot/internal/risk"
+	"github.com/imbonda/vmm-bot/internal/trader"
+	"github.com/imbonda/vmm-bot/pkg/exchanges/resilience"
+	pkgModels "github.com/imbonda/vmm-bot/pkg/models"
+)
+
+var (
+	ErrUnknownPair    = fmt.Errorf("unknown pair")
+	ErrPairRunning    = fmt.Errorf("pair is already running")
+	ErrPairNotRunning = fmt.Errorf("pair is not running")
 )
 
 type TradeConfig struct {
@@ -18,18 +33,186 @@ type TradeConfig struct {
 	TradeAmountMax    float64
 	PriceDecimals     int
 	AmountDecimals    int
+	// Placed orders.
+	OrderType        pkgModels.OrderType
+	OrderTimeInForce pkgModels.TimeInForce
+	// Inventory skew.
+	InventorySkewEnabled bool
+	InventoryTargetRatio float64
+	InventoryMinRatio    float64
+	InventoryMaxRatio    float64
+	InventoryPriceSkew   float64
+	InventoryQtySkew     float64
+	// Composite oracle.
+	OracleSources            []string
+	OracleAggregation        marketdata.Aggregation
+	OracleSourceMaxAge       time.Duration
+	OracleSourceMaxDeviation float64
+	OracleMinSources         int
+	// Oracle check.
+	OracleMaxDeviation float64
+	OracleMaxTickerAge time.Duration
+	OracleHaltEnabled  bool
+	// Risk limits.
+	RiskMaxOrderNotional      float64
+	RiskMaxDailyVolume        float64
+	RiskMaxDailyFees          float64
+	RiskMaxInventoryDeviation float64
+	RiskMaxOracleDeviation    float64
+}
+
+// Inventory returns the inventory skew configuration of the trader, nil when disabled.
+func (c *TradeConfig) Inventory() *trader.InventoryConfig {
+	if !c.InventorySkewEnabled {
+		return nil
+	}
+	return &trader.InventoryConfig{
+		TargetRatio: c.InventoryTargetRatio,
+		MinRatio:    c.InventoryMinRatio,
+		MaxRatio:    c.InventoryMaxRatio,
+		PriceSkew:   c.InventoryPriceSkew,
+		QtySkew:     c.InventoryQtySkew,
+	}
+}
+
+// OracleGuard returns the oracle check of the trader, nil when no limit is set.
+func (c *TradeConfig) OracleGuard() *trader.OracleGuardConfig {
+	if c.OracleMaxDeviation <= 0 && c.OracleMaxTickerAge <= 0 {
+		return nil
+	}
+	return &trader.OracleGuardConfig{
+		MaxDeviation: c.OracleMaxDeviation,
+		MaxTickerAge: c.OracleMaxTickerAge,
+		HaltEnabled:  c.OracleHaltEnabled,
+	}
+}
+
+// RiskLimits returns the risk limits of the trader, the inventory deviation is measured from the inventory target ratio.
+func (c *TradeConfig) RiskLimits() risk.Limits {
+	return risk.Limits{
+		MaxOrderNotional:      c.RiskMaxOrderNotional,
+		MaxDailyVolume:        c.RiskMaxDailyVolume,
+		MaxDailyFees:          c.RiskMaxDailyFees,
+		MaxInventoryDeviation: c.RiskMaxInventoryDeviation,
+		TargetRatio:           c.InventoryTargetRatio,
+		MaxOracleDeviation:    c.RiskMaxOracleDeviation,
+	}
 }
 
 type ExecutorConfig struct {
 	IntervalExecutionDuration      time.Duration
 	NumOfTradeIterationsInInterval int
 	ListenAddress                  string
+	ControlEnabled                 bool
 }
 
-type NewTraderServiceInput struct {
+type HealthConfig struct {
+	MaxMissedIntervals     int
+	MaxConsecutiveFailures int
+}
+
+type RecorderConfig struct {
+	Interval         time.Duration
+	OutputDir        string
+	FilePrefix       string
+	RotationInterval time.Duration
+	MaxFileSize      int64
+	OrderBooks       bool
+}
+
+type ScheduleConfig struct {
+	IntervalExecutionDuration      time.Duration
+	NumOfTradeIterationsInInterval int
+}
+
+// PairConfig is a traded pair, pairs trading on the same exchange share its client.
+type PairConfig struct {
+	Name    string
+	Enabled bool
+	// Pair is the canonical base and quote of the traded symbol.
+	Pair              pkgModels.Pair
 	ExchangeClient    interfaces.ExchangeClient
 	PriceOracleClient interfaces.ExchangeClient
 	Trade             TradeConfig
-	Executor          ExecutorConfig
+	Schedule          ScheduleConfig
 	Logger            log.Logger
+	// Risk guards the orders of the pair, the trader places them through it.
+	Risk *risk.Guard
+	// ExchangeFailures and OracleFailures count the consecutive failures of the clients, nil when they aren't counted.
+	ExchangeFailures interfaces.FailureCounter
+	OracleFailures   interfaces.FailureCounter
+	// ExchangeCircuit and OracleCircuits are the circuit breakers of the clients, nil or empty when they're disabled.
+	// A composite oracle has the circuits of all of its sources.
+	ExchangeCircuit *resilience.Breaker
+	OracleCircuits  []*resilience.Breaker
+}
+
+// ReadinessProblems describes why the pair can't trade: its trading is halted, or its clients failed too many requests
+// in a row, zero disables the check of the clients.
+func (p *PairConfig) ReadinessProblems(maxConsecutiveFailures int) []string {
+	var problems []string
+	if state := p.Risk.State(); state.Halted {
+		problems = append(problems, fmt.Sprintf("trading halted: %s", state.Reason))
+	}
+	if p.ExchangeCircuit != nil && p.ExchangeCircuit.Status().State == pkgModels.CircuitOpen {
+		problems = append(problems, "exchange circuit breaker is open")
+	}
+	if maxConsecutiveFailures <= 0 {
+		return problems
+	}
+	if p.ExchangeFailures != nil && p.ExchangeFailures.ConsecutiveFailures() >= maxConsecutiveFailures {
+		problems = append(problems, fmt.Sprintf("exchange client failed %d consecutive requests", p.ExchangeFailures.ConsecutiveFailures()))
+	}
+	// A pair may use its exchange as price oracle too.
+	if p.OracleFailures != nil && p.OracleFailures != p.ExchangeFailures &&
+		p.OracleFailures.ConsecutiveFailures() >= maxConsecutiveFailures {
+		problems = append(problems, fmt.Sprintf("oracle client failed %d consecutive requests", p.OracleFailures.ConsecutiveFailures()))
+	}
+	return problems
+}
+
+// CircuitStatuses returns the states of the circuit breakers of the clients, the ones shared by both clients once.
+func (p *PairConfig) CircuitStatuses() []pkgModels.CircuitStatus {
+	var statuses []pkgModels.CircuitStatus
+	for _, breaker := range lo.Uniq(append([]*resilience.Breaker{p.ExchangeCircuit}, p.OracleCircuits...)) {
+		if breaker != nil {
+			statuses = append(statuses, breaker.Status())
+		}
+	}
+	return statuses
+}
+
+// NewTrader creates the trader of the pair, recording its metrics.
+func (p *PairConfig) NewTrader(ctx context.Context) (interfaces.Trader, error) {
+	traderClient, err := trader.NewTrader(ctx, &trader.NewTraderInput{
+		ExchangeClient:    p.Risk,
+		PriceOracleClient: p.PriceOracleClient,
+		Pair:              p.Pair,
+		Symbol:            p.Trade.Symbol,
+		OracleSymbol:      p.Trade.OracleSymbol,
+		CandleHeight:      p.Trade.CandleHeight,
+		SpreadMarginLower: p.Trade.SpreadMarginLower,
+		SpreadMarginUpper: p.Trade.SpreadMarginUpper,
+		TradeAmountMin:    p.Trade.TradeAmountMin,
+		TradeAmountMax:    p.Trade.TradeAmountMax,
+		PriceDecimals:     p.Trade.PriceDecimals,
+		AmountDecimals:    p.Trade.AmountDecimals,
+		OrderType:         p.Trade.OrderType,
+		TimeInForce:       p.Trade.OrderTimeInForce,
+		Inventory:         p.Trade.Inventory(),
+		OracleGuard:       p.Trade.OracleGuard(),
+		Logger:            p.Logger,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return metrics.NewTrader(p.Name, traderClient, p.Logger), nil
+}
+
+type NewTraderServiceInput struct {
+	Pairs    []*PairConfig
+	Executor ExecutorConfig
+	Health   HealthConfig
+	Recorder RecorderConfig
+	Logger   log.Logger
 }
@@ -0,0 +1,109 @@
+package recorder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+
+	"github.com/imbonda/vmm-bot/cmd/interfaces"
+	"github.com/imbonda/vmm-bot/cmd/service/models"
+	"github.com/imbonda/vmm-bot/internal/recorder"
+)
+
+type recorderService struct {
+	recorders []*pairRecorder
+	interval  time.Duration
+	logger    log.Logger
+	stopChan  chan struct{}
+	doneChan  chan struct{}
+}
+
+type pairRecorder struct {
+	recorder *recorder.Recorder
+	logger   log.Logger
+}
+
+func NewRecorderService(ctx context.Context, input *models.NewTraderServiceInput) (interfaces.TraderService, error) {
+	if input.Recorder.Interval <= 0 {
+		return nil, fmt.Errorf("invalid recorder interval: %s", input.Recorder.Interval)
+	}
+	var recorders []*pairRecorder
+	for _, pair := range input.Pairs {
+		// Every pair is recorded to its own files, so each of them can be replayed by the backtest.
+		filePrefix := input.Recorder.FilePrefix
+		if len(input.Pairs) > 1 {
+			filePrefix += "-" + pair.Name
+		}
+		marketDataRecorder, err := recorder.NewRecorder(ctx, &recorder.NewRecorderInput{
+			ExchangeClient:    pair.ExchangeClient,
+			PriceOracleClient: pair.PriceOracleClient,
+			Symbol:            pair.Trade.Symbol,
+			OracleSymbol:      pair.Trade.OracleSymbol,
+			OrderBooks:        input.Recorder.OrderBooks,
+			OutputDir:         input.Recorder.OutputDir,
+			FilePrefix:        filePrefix,
+			RotationInterval:  input.Recorder.RotationInterval,
+			MaxFileSize:       input.Recorder.MaxFileSize,
+			Logger:            pair.Logger,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("pair %s: %w", pair.Name, err)
+		}
+		recorders = append(recorders, &pairRecorder{
+			recorder: marketDataRecorder,
+			logger:   pair.Logger,
+		})
+	}
+	return &recorderService{
+		recorders: recorders,
+		interval:  input.Recorder.Interval,
+		logger:    input.Logger,
+		stopChan:  make(chan struct{}),
+		doneChan:  make(chan struct{}),
+	}, nil
+}
+
+func (s *recorderService) Start(ctx context.Context) error {
+	go s.run(ctx)
+	return nil
+}
+
+func (s *recorderService) Shutdown(ctx context.Context) error {
+	close(s.stopChan)
+	select {
+	case <-s.doneChan:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	var errs []error
+	for _, pairRecorder := range s.recorders {
+		errs = append(errs, pairRecorder.recorder.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// run records on a fixed cadence, unlike the trader executor which spreads its iterations randomly.
+func (s *recorderService) run(ctx context.Context) {
+	defer close(s.doneChan)
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		for _, pairRecorder := range s.recorders {
+			if err := pairRecorder.recorder.DoIteration(ctx); err != nil {
+				pairRecorder.logger.Log("msg", "failed to record", "err", err)
+			} else {
+				level.Debug(pairRecorder.logger).Log("msg", "recorded market data")
+			}
+		}
+		select {
+		case <-s.stopChan:
+			level.Info(s.logger).Log("msg", "stopped recording")
+			return
+		case <-ticker.C:
+		}
+	}
+}
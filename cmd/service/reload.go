@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"reflect"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+
+	"github.com/imbonda/vmm-bot/cmd/config"
+	"github.com/imbonda/vmm-bot/cmd/interfaces"
+	"github.com/imbonda/vmm-bot/cmd/service/models"
+	pkgModels "github.com/imbonda/vmm-bot/pkg/models"
+)
+
+// WatchConfigFile applies the parameters of the pairs from the config file whenever it changes, until the context is done.
+// It's a no-op without a config file or for services which don't support parameter updates.
+func WatchConfigFile(ctx context.Context, cfg *config.Configuration, traderService interfaces.TraderService) {
+	logger := cfg.GetLogger()
+	path := cfg.GetConfigFile()
+	paramsService, ok := traderService.(interfaces.ParamsService)
+	if path == "" || !ok || cfg.Service.ConfigWatchInterval <= 0 {
+		return
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		level.Error(logger).Log("msg", "failed to watch config file", "path", path, "err", err)
+		return
+	}
+	level.Info(logger).Log("msg", "watching config file", "path", path, "interval", cfg.Service.ConfigWatchInterval)
+	go func() {
+		ticker := time.NewTicker(cfg.Service.ConfigWatchInterval)
+		defer ticker.Stop()
+		modTime, size := info.ModTime(), info.Size()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			info, err := os.Stat(path)
+			if err != nil {
+				level.Warn(logger).Log("msg", "failed to stat config file", "path", path, "err", err)
+				continue
+			}
+			if info.ModTime().Equal(modTime) && info.Size() == size {
+				continue
+			}
+			modTime, size = info.ModTime(), info.Size()
+			level.Info(logger).Log("msg", "config file changed, reloading params", "path", path)
+			reloadParams(ctx, cfg, paramsService, logger)
+		}
+	}()
+}
+
+// reloadParams validates the parameters of every pair before updating any, so an invalid file leaves the parameters as they are.
+func reloadParams(ctx context.Context, cfg *config.Configuration, paramsService interfaces.ParamsService, logger log.Logger) {
+	pairs, err := config.ReloadPairs()
+	if err != nil {
+		level.Error(logger).Log("msg", "failed to reload config file, keeping the current params", "err", err)
+		return
+	}
+	initial := make(map[string]*config.PairConfig)
+	for _, pair := range cfg.GetPairs() {
+		initial[pair.Name] = pair
+	}
+	updates := make(map[string]*pkgModels.PairParams)
+	for _, pair := range pairs {
+		if _, found := initial[pair.Name]; !found {
+			level.Warn(logger).Log("msg", "adding a pair requires a restart", "pair", pair.Name)
+			continue
+		}
+		if pair.RequiresRestart(initial[pair.Name]) {
+			level.Warn(logger).Log("msg", "changed settings other than the params require a restart", "pair", pair.Name)
+		}
+		current, err := paramsService.GetParams(ctx, pair.Name)
+		if errors.Is(err, models.ErrUnknownPair) {
+			continue
+		} else if err != nil {
+			level.Error(logger).Log("msg", "failed to get params, keeping the current params", "pair", pair.Name, "err", err)
+			return
+		}
+		params := pair.Params()
+		if current.Schedule == nil {
+			params.Schedule = nil
+		}
+		if reflect.DeepEqual(current, params) {
+			continue
+		}
+		if err = params.Validate(); err != nil {
+			level.Error(logger).Log("msg", "invalid params in config file, keeping the current params", "pair", pair.Name, "err", err)
+			return
+		}
+		updates[pair.Name] = params
+	}
+	for name, params := range updates {
+		if _, err = paramsService.UpdateParams(ctx, name, params); err != nil {
+			level.Error(logger).Log("msg", "failed to update params", "pair", name, "err", err)
+			continue
+		}
+		level.Info(logger).Log("msg", "reloaded params", "pair", name)
+	}
+}
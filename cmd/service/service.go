@@ -11,39 +11,108 @@ import (
 	"github.com/imbonda/vmm-bot/cmd/service/executor"
 	"github.com/imbonda/vmm-bot/cmd/service/http"
 	"github.com/imbonda/vmm-bot/cmd/service/models"
+	"github.com/imbonda/vmm-bot/cmd/service/recorder"
+	"github.com/imbonda/vmm-bot/internal/marketdata"
+	"github.com/imbonda/vmm-bot/internal/risk"
 	"github.com/imbonda/vmm-bot/pkg/utils"
 )
 
 func GetTraderService(ctx context.Context, cfg *config.Configuration) (interfaces.TraderService, error) {
 	logger := cfg.GetLogger()
-	exchangeClient, err := cfg.GetExchangeClient(ctx)
+	pairs, err := getPairs(ctx, cfg)
 	if err != nil {
-		level.Error(logger).Log("msg", "failed to create exchange client", "err", err)
-		return nil, err
-	}
-	priceOracleClient, err := cfg.GetPriceOracleClient(ctx)
-	if err != nil {
-		level.Error(logger).Log("msg", "failed to create price oracle client", "err", err)
 		return nil, err
 	}
 	if cfg.Service.Orchestration == utils.Executor {
 		return executor.NewTraderService(ctx, &models.NewTraderServiceInput{
-			ExchangeClient:    exchangeClient,
-			PriceOracleClient: priceOracleClient,
-			Trade:             models.TradeConfig(cfg.Trade),
-			Executor:          models.ExecutorConfig(cfg.Executor),
-			Logger:            logger,
+			Pairs:    pairs,
+			Executor: models.ExecutorConfig(cfg.Executor),
+			Health:   models.HealthConfig(cfg.Health),
+			Logger:   logger,
 		})
 	} else if cfg.Service.Orchestration == utils.HTTP {
 		return http.NewTraderService(ctx, &models.NewTraderServiceInput{
-			ExchangeClient:    exchangeClient,
-			PriceOracleClient: priceOracleClient,
-			Trade:             models.TradeConfig(cfg.Trade),
-			Executor:          models.ExecutorConfig(cfg.Executor),
-			Logger:            logger,
+			Pairs:    pairs,
+			Executor: models.ExecutorConfig(cfg.Executor),
+			Health:   models.HealthConfig(cfg.Health),
+			Logger:   logger,
+		})
+	} else if cfg.Service.Orchestration == utils.Recorder {
+		return recorder.NewRecorderService(ctx, &models.NewTraderServiceInput{
+			Pairs:    pairs,
+			Recorder: models.RecorderConfig(cfg.Recorder),
+			Logger:   logger,
 		})
 	} else {
 		level.Error(logger).Log("msg", "invalid orchestration", "orchestration", cfg.Service.Orchestration)
 		return nil, fmt.Errorf("invalid orchestration")
 	}
 }
+
+// getPairs resolves the exchange clients of the pairs, pairs of the same exchange share its client,
+// and guards the orders of every pair with its risk limits.
+func getPairs(ctx context.Context, cfg *config.Configuration) ([]*models.PairConfig, error) {
+	logger := cfg.GetLogger()
+	riskStateFile, err := cfg.GetRiskStateFile()
+	if err != nil {
+		return nil, err
+	}
+	riskStore, err := risk.NewStore(riskStateFile)
+	if err != nil {
+		level.Error(logger).Log("msg", "failed to load the risk state", "err", err)
+		return nil, err
+	}
+	var pairs []*models.PairConfig
+	for _, pair := range cfg.GetPairs() {
+		exchangeClient, err := cfg.GetExchangeClient(ctx, pair.Exchange)
+		if err != nil {
+			level.Error(logger).Log("msg", "failed to create exchange client", "pair", pair.Name, "err", err)
+			return nil, err
+		}
+		priceOracleClient, err := cfg.GetPriceOracleClient(ctx, pair)
+		if err != nil {
+			level.Error(logger).Log("msg", "failed to create price oracle client", "pair", pair.Name, "err", err)
+			return nil, err
+		}
+		oracleFailures := cfg.GetFailureCounter(pair.Oracle)
+		if compositeOracle, ok := priceOracleClient.(*marketdata.CompositeOracle); ok {
+			// A composite oracle only fails when too few of its sources are kept.
+			oracleFailures = compositeOracle
+		}
+		pairLogger := cfg.GetPairLogger(pair)
+		tradeConfig := models.TradeConfig(pair.TradeConfig)
+		riskGuard, err := risk.NewGuard(ctx, &risk.NewGuardInput{
+			ExchangeClient:    exchangeClient,
+			PriceOracleClient: priceOracleClient,
+			Name:              pair.Name,
+			Pair:              pair.Pair(),
+			Symbol:            pair.Symbol,
+			OracleSymbol:      pair.OracleSymbol,
+			Limits:            tradeConfig.RiskLimits(),
+			Store:             riskStore,
+			Logger:            pairLogger,
+		})
+		if err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, &models.PairConfig{
+			Name:              pair.Name,
+			Enabled:           pair.Enabled,
+			Pair:              pair.Pair(),
+			ExchangeClient:    exchangeClient,
+			PriceOracleClient: priceOracleClient,
+			ExchangeFailures:  cfg.GetFailureCounter(pair.Exchange),
+			OracleFailures:    oracleFailures,
+			ExchangeCircuit:   cfg.GetCircuitBreaker(pair.Exchange),
+			OracleCircuits:    cfg.GetOracleCircuitBreakers(pair),
+			Trade:             tradeConfig,
+			Schedule: models.ScheduleConfig{
+				IntervalExecutionDuration:      pair.IntervalExecutionDuration,
+				NumOfTradeIterationsInInterval: pair.NumOfTradeIterationsInInterval,
+			},
+			Logger: pairLogger,
+			Risk:   riskGuard,
+		})
+	}
+	return pairs, nil
+}
@@ -0,0 +1,94 @@
+package marketdata
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+
+	"github.com/imbonda/vmm-bot/cmd/interfaces"
+	"github.com/imbonda/vmm-bot/pkg/exchanges/stream"
+	"github.com/imbonda/vmm-bot/pkg/models"
+)
+
+// StreamingClient serves tickers from a websocket cached top-of-book, and falls back to the
+// wrapped REST client while the stream of a symbol is stale. All other calls go to the REST client.
+type StreamingClient struct {
+	interfaces.ExchangeClient
+	ctx            context.Context
+	dialect        stream.Dialect
+	pingInterval   time.Duration
+	staleAfter     time.Duration
+	reconnectDelay time.Duration
+	logger         log.Logger
+	mu             sync.Mutex
+	streams        map[string]*stream.Stream
+}
+
+type NewStreamingClientInput struct {
+	ExchangeClient interfaces.ExchangeClient
+	Dialect        stream.Dialect
+	PingInterval   time.Duration
+	StaleAfter     time.Duration
+	ReconnectDelay time.Duration
+	Logger         log.Logger
+}
+
+// NewStreamingClient wraps a REST client, the streams are tied to the given context.
+func NewStreamingClient(ctx context.Context, input *NewStreamingClientInput) (*StreamingClient, error) {
+	return &StreamingClient{
+		ExchangeClient: input.ExchangeClient,
+		ctx:            ctx,
+		dialect:        input.Dialect,
+		pingInterval:   input.PingInterval,
+		staleAfter:     input.StaleAfter,
+		reconnectDelay: input.ReconnectDelay,
+		logger:         input.Logger,
+		streams:        make(map[string]*stream.Stream),
+	}, nil
+}
+
+// GetLastTicker subscribes to the symbol on first use, the stream takes over once synced.
+func (c *StreamingClient) GetLastTicker(ctx context.Context, symbol string) (*models.Ticker, error) {
+	symbolStream := c.getStream(symbol)
+	if ticker, ok := symbolStream.Ticker(); ok {
+		return ticker, nil
+	}
+	level.Debug(c.logger).Log("msg", "stream is stale, falling back to rest", "symbol", symbol)
+	ticker, err := c.ExchangeClient.GetLastTicker(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	symbolStream.SeedLastPrice(ticker.LastPrice)
+	return ticker, nil
+}
+
+// Close stops all the streams.
+func (c *StreamingClient) Close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, symbolStream := range c.streams {
+		symbolStream.Close()
+	}
+}
+
+func (c *StreamingClient) getStream(symbol string) *stream.Stream {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if symbolStream, found := c.streams[symbol]; found {
+		return symbolStream
+	}
+	symbolStream := stream.NewStream(&stream.NewStreamInput{
+		Dialect:        c.dialect,
+		Symbol:         symbol,
+		PingInterval:   c.pingInterval,
+		StaleAfter:     c.staleAfter,
+		ReconnectDelay: c.reconnectDelay,
+		Logger:         c.logger,
+	})
+	symbolStream.Start(c.ctx)
+	c.streams[symbol] = symbolStream
+	return symbolStream
+}
@@ -0,0 +1,219 @@
+package marketdata
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+
+	"github.com/imbonda/vmm-bot/cmd/interfaces"
+	"github.com/imbonda/vmm-bot/pkg/models"
+	"github.com/imbonda/vmm-bot/pkg/utils"
+)
+
+var ErrNotEnoughOracleSources = fmt.Errorf("not enough oracle sources")
+
+// MinOutlierSources is how many sources the outlier filter needs, the median of two sources is their mean, which both
+// of them are as far from, so a single glitching source would discard both.
+const MinOutlierSources = 3
+
+// Aggregation is how the composite oracle combines the prices of its sources.
+type Aggregation string
+
+const (
+	AggregationMedian Aggregation = "median"
+	// AggregationVolumeWeighted weights the prices by the 24h volume of their sources, and falls back to the median
+	// when a source doesn't report its volume.
+	AggregationVolumeWeighted Aggregation = "vwap"
+)
+
+// OracleSource is a symbol of an exchange the composite oracle takes prices from.
+type OracleSource struct {
+	Name   string
+	Client interfaces.ExchangeClient
+	Symbol string
+}
+
+// CompositeOracle serves tickers aggregated from several sources, queried in parallel, so that a single exchange outage
+// or glitch doesn't move the oracle price. All other calls go to the client of the first source.
+type CompositeOracle struct {
+	interfaces.ExchangeClient
+	sources      []OracleSource
+	aggregation  Aggregation
+	maxAge       time.Duration
+	maxDeviation float64
+	minSources   int
+	logger       log.Logger
+	failures     atomic.Int64
+}
+
+type NewCompositeOracleInput struct {
+	// Sources are the aggregated sources, the first one serves the symbol of the oracle.
+	Sources     []OracleSource
+	Aggregation Aggregation
+	// MaxAge discards the sources whose reported ticker is older, zero keeps them.
+	MaxAge time.Duration
+	// MaxDeviation discards the sources whose price is further from the median price, relative to it, zero keeps them.
+	// It requires at least MinOutlierSources sources.
+	MaxDeviation float64
+	// MinSources is how many sources must be kept to serve a ticker.
+	MinSources int
+	Logger     log.Logger
+}
+
+func NewCompositeOracle(_ context.Context, input *NewCompositeOracleInput) (*CompositeOracle, error) {
+	switch {
+	case len(input.Sources) == 0:
+		return nil, fmt.Errorf("composite oracle requires at least one source")
+	case input.Aggregation != AggregationMedian && input.Aggregation != AggregationVolumeWeighted:
+		return nil, fmt.Errorf("invalid oracle aggregation: %s, expected %s or %s", input.Aggregation, AggregationMedian, AggregationVolumeWeighted)
+	case input.MinSources < 1 || input.MinSources > len(input.Sources):
+		return nil, fmt.Errorf("invalid oracle min sources: %d, expected between 1 and %d", input.MinSources, len(input.Sources))
+	case input.MaxAge < 0 || input.MaxDeviation < 0:
+		return nil, fmt.Errorf("invalid oracle source limits, expected non negative. age: %s, deviation: %f", input.MaxAge, input.MaxDeviation)
+	case input.MaxDeviation > 0 && len(input.Sources) < MinOutlierSources:
+		return nil, fmt.Errorf(
+			"invalid oracle source max deviation: %f, discarding outliers requires at least %d sources, got %d",
+			input.MaxDeviation,
+			MinOutlierSources,
+			len(input.Sources),
+		)
+	}
+	return &CompositeOracle{
+		ExchangeClient: input.Sources[0].Client,
+		sources:        input.Sources,
+		aggregation:    input.Aggregation,
+		maxAge:         input.MaxAge,
+		maxDeviation:   input.MaxDeviation,
+		minSources:     input.MinSources,
+		logger:         input.Logger,
+	}, nil
+}
+
+type sourceQuote struct {
+	source *OracleSource
+	ticker *models.Ticker
+	price  float64
+}
+
+// GetLastTicker aggregates the last, ask and bid prices of the sources which are neither failing, stale nor outliers.
+// The ticker is as old as the oldest kept source.
+func (o *CompositeOracle) GetLastTicker(ctx context.Context, symbol string) (*models.Ticker, error) {
+	if symbol != o.sources[0].Symbol {
+		return nil, fmt.Errorf("composite oracle doesn't serve symbol: %s, expected %s", symbol, o.sources[0].Symbol)
+	}
+	quotes := o.getQuotes(ctx)
+	if len(quotes) > 0 && o.maxDeviation > 0 {
+		median := medianOf(quotes, func(quote *sourceQuote) float64 { return quote.price })
+		quotes = slices.DeleteFunc(quotes, func(quote *sourceQuote) bool {
+			deviation := math.Abs(quote.price-median) / median
+			if deviation > o.maxDeviation {
+				level.Warn(o.logger).Log("msg", "discarded outlier oracle source", "source", quote.source.Name, "symbol", quote.source.Symbol, "price", quote.price, "median", median)
+				return true
+			}
+			return false
+		})
+	}
+	if len(quotes) < o.minSources {
+		o.failures.Add(1)
+		return nil, fmt.Errorf("%w: %d of %d sources kept, expected at least %d", ErrNotEnoughOracleSources, len(quotes), len(o.sources), o.minSources)
+	}
+
+	ticker := &models.Ticker{
+		Symbol:    symbol,
+		LastPrice: utils.FormatFloatToString(o.aggregate(quotes), -1),
+		BestAsk:   utils.FormatFloatToString(medianOf(quotes, quotePrice(func(ticker *models.Ticker) string { return ticker.BestAsk })), -1),
+		BestBid:   utils.FormatFloatToString(medianOf(quotes, quotePrice(func(ticker *models.Ticker) string { return ticker.BestBid })), -1),
+	}
+	o.failures.Store(0)
+	for _, quote := range quotes {
+		if !quote.ticker.Time.IsZero() && (ticker.Time.IsZero() || quote.ticker.Time.Before(ticker.Time)) {
+			ticker.Time = quote.ticker.Time
+		}
+	}
+	return ticker, nil
+}
+
+// ConsecutiveFailures counts the tickers in a row which couldn't be served, the failures of single sources don't count.
+func (o *CompositeOracle) ConsecutiveFailures() int {
+	return int(o.failures.Load())
+}
+
+// getQuotes queries the sources in parallel, and keeps the ones which replied with a fresh and valid price.
+func (o *CompositeOracle) getQuotes(ctx context.Context) []*sourceQuote {
+	results := make([]*sourceQuote, len(o.sources))
+	var wg sync.WaitGroup
+	for i := range o.sources {
+		wg.Add(1)
+		go func(source *OracleSource) {
+			defer wg.Done()
+			ticker, err := source.Client.GetLastTicker(ctx, source.Symbol)
+			if err != nil {
+				level.Warn(o.logger).Log("msg", "failed to get oracle source ticker", "source", source.Name, "symbol", source.Symbol, "err", err)
+				return
+			}
+			price, err := ticker.Price()
+			if err != nil || price <= 0 {
+				level.Warn(o.logger).Log("msg", "invalid oracle source price", "source", source.Name, "symbol", source.Symbol, "price", ticker.LastPrice)
+				return
+			}
+			if age := time.Since(ticker.Time); o.maxAge > 0 && !ticker.Time.IsZero() && age > o.maxAge {
+				level.Warn(o.logger).Log("msg", "discarded stale oracle source", "source", source.Name, "symbol", source.Symbol, "age", age)
+				return
+			}
+			results[i] = &sourceQuote{source: source, ticker: ticker, price: price}
+		}(&o.sources[i])
+	}
+	wg.Wait()
+	return slices.DeleteFunc(results, func(quote *sourceQuote) bool { return quote == nil })
+}
+
+func (o *CompositeOracle) aggregate(quotes []*sourceQuote) float64 {
+	if o.aggregation == AggregationVolumeWeighted {
+		var notional, volume float64
+		for _, quote := range quotes {
+			quoteVolume, err := utils.ParseFloat(quote.ticker.Volume)
+			if err != nil || quoteVolume <= 0 {
+				level.Debug(o.logger).Log("msg", "oracle source doesn't report its volume, using the median", "source", quote.source.Name)
+				return medianOf(quotes, func(quote *sourceQuote) float64 { return quote.price })
+			}
+			notional += quote.price * quoteVolume
+			volume += quoteVolume
+		}
+		return notional / volume
+	}
+	return medianOf(quotes, func(quote *sourceQuote) float64 { return quote.price })
+}
+
+// quotePrice reads a price of the ticker of a quote, zero when it can't be parsed.
+func quotePrice(field func(ticker *models.Ticker) string) func(quote *sourceQuote) float64 {
+	return func(quote *sourceQuote) float64 {
+		price, _ := utils.ParseFloat(field(quote.ticker))
+		return price
+	}
+}
+
+// medianOf returns the median of the positive values of the quotes, zero when there are none.
+func medianOf(quotes []*sourceQuote, value func(quote *sourceQuote) float64) float64 {
+	values := make([]float64, 0, len(quotes))
+	for _, quote := range quotes {
+		if v := value(quote); v > 0 {
+			values = append(values, v)
+		}
+	}
+	if len(values) == 0 {
+		return 0
+	}
+	slices.Sort(values)
+	middle := len(values) / 2
+	if len(values)%2 == 0 {
+		return (values[middle-1] + values[middle]) / 2
+	}
+	return values[middle]
+}
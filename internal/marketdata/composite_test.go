@@ -0,0 +1,178 @@
+package marketdata_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/imbonda/vmm-bot/cmd/interfaces/mocks"
+	"github.com/imbonda/vmm-bot/internal/marketdata"
+	"github.com/imbonda/vmm-bot/pkg/models"
+)
+
+const symbol = "BTCUSDT"
+
+// quote is the reply of a source, a nil ticker fails it.
+type quote struct {
+	ticker *models.Ticker
+}
+
+func ticker(price, volume string, age time.Duration) quote {
+	t := &models.Ticker{Symbol: symbol, LastPrice: price, BestAsk: price, BestBid: price, Volume: volume}
+	if age > 0 {
+		t.Time = time.Now().Add(-age)
+	}
+	return quote{ticker: t}
+}
+
+func newOracle(t *testing.T, input marketdata.NewCompositeOracleInput, quotes ...quote) *marketdata.CompositeOracle {
+	t.Helper()
+	for i, q := range quotes {
+		client := mocks.NewExchangeClient(t)
+		if q.ticker == nil {
+			client.On("GetLastTicker", mock.Anything, symbol).Return(nil, fmt.Errorf("unavailable")).Maybe()
+		} else {
+			client.On("GetLastTicker", mock.Anything, symbol).Return(q.ticker, nil).Maybe()
+		}
+		input.Sources = append(input.Sources, marketdata.OracleSource{Name: fmt.Sprintf("source-%d", i), Client: client, Symbol: symbol})
+	}
+	if input.Aggregation == "" {
+		input.Aggregation = marketdata.AggregationMedian
+	}
+	if input.MinSources == 0 {
+		input.MinSources = 1
+	}
+	input.Logger = log.NewNopLogger()
+	oracle, err := marketdata.NewCompositeOracle(context.Background(), &input)
+	require.NoError(t, err)
+	return oracle
+}
+
+func TestGetLastTicker(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  marketdata.NewCompositeOracleInput
+		quotes []quote
+		price  string
+	}{
+		{
+			name:   "median of an odd count",
+			quotes: []quote{ticker("101", "", 0), ticker("100", "", 0), ticker("105", "", 0)},
+			price:  "101",
+		},
+		{
+			name:   "median of an even count",
+			quotes: []quote{ticker("100", "", 0), ticker("102", "", 0)},
+			price:  "101",
+		},
+		{
+			name:   "volume weighted",
+			input:  marketdata.NewCompositeOracleInput{Aggregation: marketdata.AggregationVolumeWeighted},
+			quotes: []quote{ticker("100", "3", 0), ticker("104", "1", 0)},
+			price:  "101",
+		},
+		{
+			name:   "volume weighted falls back to the median without a volume",
+			input:  marketdata.NewCompositeOracleInput{Aggregation: marketdata.AggregationVolumeWeighted},
+			quotes: []quote{ticker("100", "3", 0), ticker("104", "", 0), ticker("101", "1", 0)},
+			price:  "101",
+		},
+		{
+			name:   "failing sources are discarded",
+			quotes: []quote{ticker("100", "", 0), {}, ticker("102", "", 0)},
+			price:  "101",
+		},
+		{
+			name:   "stale sources are discarded",
+			input:  marketdata.NewCompositeOracleInput{MaxAge: time.Minute},
+			quotes: []quote{ticker("100", "", time.Second), ticker("200", "", time.Hour), ticker("102", "", 0)},
+			price:  "101",
+		},
+		{
+			name:   "outliers are discarded",
+			input:  marketdata.NewCompositeOracleInput{MaxDeviation: 0.05},
+			quotes: []quote{ticker("100", "", 0), ticker("150", "", 0), ticker("102", "", 0)},
+			price:  "101",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			oracle := newOracle(t, test.input, test.quotes...)
+
+			result, err := oracle.GetLastTicker(context.Background(), symbol)
+			require.NoError(t, err)
+			assert.Equal(t, test.price, result.LastPrice)
+			assert.Equal(t, 0, oracle.ConsecutiveFailures())
+		})
+	}
+}
+
+func TestGetLastTickerIsAsOldAsTheOldestSource(t *testing.T) {
+	oracle := newOracle(t, marketdata.NewCompositeOracleInput{MaxAge: time.Minute},
+		ticker("100", "", 10*time.Second), ticker("101", "", time.Second), ticker("102", "", 0))
+
+	result, err := oracle.GetLastTicker(context.Background(), symbol)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(-10*time.Second), result.Time, time.Second)
+}
+
+func TestGetLastTickerRequiresMinSources(t *testing.T) {
+	oracle := newOracle(t, marketdata.NewCompositeOracleInput{MaxDeviation: 0.05, MinSources: 2},
+		ticker("100", "", 0), quote{}, ticker("150", "", 0))
+
+	// Two sources are left which deviate from their median, so neither is kept.
+	for i := range 2 {
+		_, err := oracle.GetLastTicker(context.Background(), symbol)
+		assert.ErrorIs(t, err, marketdata.ErrNotEnoughOracleSources)
+		assert.Equal(t, i+1, oracle.ConsecutiveFailures())
+	}
+}
+
+func TestGetLastTickerRejectsOtherSymbols(t *testing.T) {
+	oracle := newOracle(t, marketdata.NewCompositeOracleInput{}, ticker("100", "", 0))
+
+	_, err := oracle.GetLastTicker(context.Background(), "ETHUSDT")
+	assert.Error(t, err)
+}
+
+func TestNewCompositeOracleRejectsInvalidInput(t *testing.T) {
+	source := marketdata.OracleSource{Name: "source", Client: mocks.NewExchangeClient(t), Symbol: symbol}
+	tests := []struct {
+		name  string
+		input marketdata.NewCompositeOracleInput
+	}{
+		{
+			name:  "no sources",
+			input: marketdata.NewCompositeOracleInput{Aggregation: marketdata.AggregationMedian, MinSources: 1},
+		},
+		{
+			name:  "unknown aggregation",
+			input: marketdata.NewCompositeOracleInput{Sources: []marketdata.OracleSource{source}, Aggregation: "mean", MinSources: 1},
+		},
+		{
+			name:  "more min sources than sources",
+			input: marketdata.NewCompositeOracleInput{Sources: []marketdata.OracleSource{source}, Aggregation: marketdata.AggregationMedian, MinSources: 2},
+		},
+		{
+			name: "outlier filter of two sources",
+			input: marketdata.NewCompositeOracleInput{
+				Sources:      []marketdata.OracleSource{source, source},
+				Aggregation:  marketdata.AggregationMedian,
+				MaxDeviation: 0.05,
+				MinSources:   1,
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := marketdata.NewCompositeOracle(context.Background(), &test.input)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package recorder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+
+	"github.com/imbonda/vmm-bot/cmd/interfaces"
+	"github.com/imbonda/vmm-bot/pkg/models"
+)
+
+// Recorder persists snapshots of the venue and oracle market data, in the format replayed by the backtest command.
+type Recorder struct {
+	exchangeClient    interfaces.ExchangeClient
+	priceOracleClient interfaces.ExchangeClient
+	symbol            string
+	oracleSymbol      string
+	orderBooks        bool
+	writer            *rotatingWriter
+	logger            log.Logger
+}
+
+type NewRecorderInput struct {
+	ExchangeClient    interfaces.ExchangeClient
+	PriceOracleClient interfaces.ExchangeClient
+	Symbol            string
+	OracleSymbol      string
+	// OrderBooks records the order books next to the tickers.
+	OrderBooks       bool
+	OutputDir        string
+	FilePrefix       string
+	RotationInterval time.Duration
+	MaxFileSize      int64
+	Logger           log.Logger
+}
+
+func NewRecorder(ctx context.Context, input *NewRecorderInput) (*Recorder, error) {
+	writer, err := newRotatingWriter(input.OutputDir, input.FilePrefix, input.RotationInterval, input.MaxFileSize)
+	if err != nil {
+		return nil, err
+	}
+	return &Recorder{
+		exchangeClient:    input.ExchangeClient,
+		priceOracleClient: input.PriceOracleClient,
+		symbol:            input.Symbol,
+		oracleSymbol:      input.OracleSymbol,
+		orderBooks:        input.OrderBooks,
+		writer:            writer,
+		logger:            input.Logger,
+	}, nil
+}
+
+func (r *Recorder) DoIteration(ctx context.Context) error {
+	defer func() {
+		if rec := recover(); rec != nil {
+			r.logger.Log("msg", "panic recovered in iteration", "err", rec)
+			debug.PrintStack()
+		}
+	}()
+	return r.RecordOnce(ctx)
+}
+
+// RecordOnce writes a single snapshot of every recorded source, a failing source doesn't prevent recording the others.
+func (r *Recorder) RecordOnce(ctx context.Context) error {
+	var errs []error
+	errs = append(errs, r.recordTicker(ctx, r.exchangeClient, r.symbol, models.VenueSource))
+	errs = append(errs, r.recordTicker(ctx, r.priceOracleClient, r.oracleSymbol, models.OracleSource))
+	if r.orderBooks {
+		errs = append(errs, r.recordOrderBook(ctx, r.exchangeClient, r.symbol, models.VenueSource))
+		errs = append(errs, r.recordOrderBook(ctx, r.priceOracleClient, r.oracleSymbol, models.OracleSource))
+	}
+	return errors.Join(errs...)
+}
+
+func (r *Recorder) Close() error {
+	return r.writer.Close()
+}
+
+func (r *Recorder) recordTicker(ctx context.Context, client interfaces.ExchangeClient, symbol string, source models.MarketDataSource) error {
+	ticker, err := client.GetLastTicker(ctx, symbol)
+	if err != nil {
+		level.Warn(r.logger).Log("msg", "failed fetching ticker", "source", source, "symbol", symbol, "err", err)
+		return fmt.Errorf("%s ticker: %w", source, err)
+	}
+	return r.writer.WriteJSON(&models.TickerSnapshot{
+		Time:   time.Now().UTC(),
+		Source: source,
+		Ticker: ticker,
+	})
+}
+
+func (r *Recorder) recordOrderBook(ctx context.Context, client interfaces.ExchangeClient, symbol string, source models.MarketDataSource) error {
+	orderBook, err := client.GetOrderBook(ctx, symbol)
+	if err != nil {
+		level.Warn(r.logger).Log("msg", "failed fetching order book", "source", source, "symbol", symbol, "err", err)
+		return fmt.Errorf("%s order book: %w", source, err)
+	}
+	return r.writer.WriteJSON(&models.OrderBookSnapshot{
+		Time:      time.Now().UTC(),
+		Source:    source,
+		OrderBook: orderBook,
+	})
+}
@@ -0,0 +1,99 @@
+package recorder
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const fileTimeLayout = "20060102T150405.000Z"
+
+// rotatingWriter appends JSON lines to files, starting a new file once the current one
+// is older than the rotation interval or larger than the max size.
+type rotatingWriter struct {
+	mu               sync.Mutex
+	dir              string
+	prefix           string
+	rotationInterval time.Duration
+	maxFileSize      int64
+	file             *os.File
+	openedAt         time.Time
+	size             int64
+}
+
+func newRotatingWriter(dir, prefix string, rotationInterval time.Duration, maxFileSize int64) (*rotatingWriter, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed creating output directory: %w", err)
+	}
+	return &rotatingWriter{
+		dir:              dir,
+		prefix:           prefix,
+		rotationInterval: rotationInterval,
+		maxFileSize:      maxFileSize,
+	}, nil
+}
+
+func (w *rotatingWriter) WriteJSON(value any) error {
+	line, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err = w.rotate(); err != nil {
+		return err
+	}
+	n, err := w.file.Write(line)
+	w.size += int64(n)
+	return err
+}
+
+func (w *rotatingWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.file == nil {
+		return nil
+	}
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
+
+func (w *rotatingWriter) rotate() error {
+	now := time.Now().UTC()
+	if w.file != nil && !w.shouldRotate(now) {
+		return nil
+	}
+	if w.file != nil {
+		if err := w.file.Close(); err != nil {
+			return err
+		}
+		w.file = nil
+	}
+	name := fmt.Sprintf("%s-%s.jsonl", w.prefix, now.Format(fileTimeLayout))
+	file, err := os.OpenFile(filepath.Join(w.dir, name), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed opening recording file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	w.file = file
+	w.openedAt = now
+	w.size = info.Size()
+	return nil
+}
+
+func (w *rotatingWriter) shouldRotate(now time.Time) bool {
+	if w.rotationInterval > 0 && now.Sub(w.openedAt) >= w.rotationInterval {
+		return true
+	}
+	return w.maxFileSize > 0 && w.size >= w.maxFileSize
+}
@@ -0,0 +1,318 @@
+package risk
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+
+	"github.com/imbonda/vmm-bot/cmd/interfaces"
+	"github.com/imbonda/vmm-bot/pkg/models"
+	"github.com/imbonda/vmm-bot/pkg/utils"
+)
+
+const dayLayout = "2006-01-02"
+
+var (
+	ErrTradingHalted     = fmt.Errorf("trading is halted")
+	ErrRiskLimitBreached = fmt.Errorf("risk limit breached")
+	ErrNotHalted         = fmt.Errorf("trading is not halted")
+	// ErrCheckFailed is an order which wasn't sent since its limits couldn't be checked, along with the failure.
+	ErrCheckFailed = fmt.Errorf("failed check risk limits")
+)
+
+// Limits are the risk limits of a pair, a zero limit isn't enforced. The notional, volume and fees are in quote currency.
+type Limits struct {
+	MaxOrderNotional float64
+	// MaxDailyVolume caps the notional of the orders placed within a UTC day, or of its fills when they're more.
+	MaxDailyVolume float64
+	// MaxDailyFees caps the fees of the fills of a UTC day, paid in base or quote currency.
+	MaxDailyFees float64
+	// MaxInventoryDeviation caps the distance of the base share of the inventory value from TargetRatio.
+	MaxInventoryDeviation float64
+	TargetRatio           float64
+	// MaxOracleDeviation caps the distance of the order price from the oracle price, relative to the oracle price.
+	MaxOracleDeviation float64
+}
+
+// Guard checks the orders of a pair against its risk limits before they're placed. On a breach it cancels the open
+// orders of the pair and halts its trading, until it's resumed.
+type Guard struct {
+	interfaces.ExchangeClient
+	priceOracleClient interfaces.ExchangeClient
+	name              string
+	pair              models.Pair
+	symbol            string
+	oracleSymbol      string
+	limits            Limits
+	store             *Store
+	// mu orders the checks and the volume updates of the orders of the pair.
+	mu     sync.Mutex
+	logger log.Logger
+}
+
+type NewGuardInput struct {
+	ExchangeClient    interfaces.ExchangeClient
+	PriceOracleClient interfaces.ExchangeClient
+	// Name of the pair, its risk state is stored by it.
+	Name string
+	// Pair is the canonical base and quote of the symbol, its balances and fees are looked up by them.
+	Pair         models.Pair
+	Symbol       string
+	OracleSymbol string
+	Limits       Limits
+	Store        *Store
+	Logger       log.Logger
+}
+
+func NewGuard(ctx context.Context, input *NewGuardInput) (*Guard, error) {
+	guard := &Guard{
+		ExchangeClient:    input.ExchangeClient,
+		priceOracleClient: input.PriceOracleClient,
+		name:              input.Name,
+		pair:              input.Pair,
+		symbol:            input.Symbol,
+		oracleSymbol:      input.OracleSymbol,
+		limits:            input.Limits,
+		store:             input.Store,
+		logger:            input.Logger,
+	}
+	if state := guard.State(); state.Halted {
+		level.Warn(guard.logger).Log("msg", "trading is halted", "reason", state.Reason, "haltedAt", state.HaltedAt)
+	}
+	return guard, nil
+}
+
+// State returns the risk state of the pair.
+func (g *Guard) State() *models.RiskState {
+	state := g.store.Get(g.name)
+	return &state
+}
+
+// Resume lifts the halt of the pair, the daily limits still apply to its next orders.
+func (g *Guard) Resume(ctx context.Context) (*models.RiskState, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if !g.store.Get(g.name).Halted {
+		return nil, fmt.Errorf("%w: %s", ErrNotHalted, g.name)
+	}
+	state, err := g.store.Update(g.name, func(state *models.RiskState) {
+		state.Halted, state.Reason, state.HaltedAt = false, "", nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	level.Info(g.logger).Log("msg", "resumed trading")
+	return &state, nil
+}
+
+func (g *Guard) PlaceOrder(ctx context.Context, order *models.Order) (*models.OrderRecord, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	state := g.store.Get(g.name)
+	if state.Halted {
+		return nil, fmt.Errorf("%w: %s", ErrTradingHalted, state.Reason)
+	}
+	price, err := utils.ParseFloat(order.Price)
+	if err != nil {
+		return nil, fmt.Errorf("failed parse order price: %s", order.Price)
+	}
+	qty, err := utils.ParseFloat(order.Qty)
+	if err != nil {
+		return nil, fmt.Errorf("failed parse order qty: %s", order.Qty)
+	}
+	notional := price * qty
+	breaches, err := g.check(ctx, price, notional)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCheckFailed, err)
+	}
+	if len(breaches) > 0 {
+		return nil, g.halt(ctx, strings.Join(breaches, ", "))
+	}
+	record, err := g.ExchangeClient.PlaceOrder(ctx, order)
+	if err != nil {
+		return nil, err
+	}
+	if g.hasDailyLimits() {
+		if _, err = g.store.Update(g.name, func(state *models.RiskState) {
+			rollover(state)
+			state.Volume += notional
+		}); err != nil {
+			level.Error(g.logger).Log("msg", "failed to save the risk state", "err", err)
+		}
+	}
+	return record, nil
+}
+
+// check describes the limits the order would breach. The daily volume counts the order as if it was filled in full.
+func (g *Guard) check(ctx context.Context, price, notional float64) ([]string, error) {
+	var breaches []string
+	if g.limits.MaxOrderNotional > 0 && notional > g.limits.MaxOrderNotional {
+		breaches = append(breaches, fmt.Sprintf("order notional %f above %f", notional, g.limits.MaxOrderNotional))
+	}
+	if g.hasDailyLimits() {
+		state, err := g.syncFills(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if volume := max(state.Volume, state.FilledVolume) + notional; g.limits.MaxDailyVolume > 0 && volume > g.limits.MaxDailyVolume {
+			breaches = append(breaches, fmt.Sprintf("daily volume %f above %f", volume, g.limits.MaxDailyVolume))
+		}
+		if g.limits.MaxDailyFees > 0 && state.Fees > g.limits.MaxDailyFees {
+			breaches = append(breaches, fmt.Sprintf("daily fees %f above %f", state.Fees, g.limits.MaxDailyFees))
+		}
+	}
+	if g.limits.MaxInventoryDeviation > 0 {
+		ratio, err := g.getInventoryRatio(ctx, price)
+		if err != nil {
+			return nil, err
+		}
+		if deviation := math.Abs(ratio - g.limits.TargetRatio); deviation > g.limits.MaxInventoryDeviation {
+			breaches = append(breaches, fmt.Sprintf("inventory deviation %f above %f", deviation, g.limits.MaxInventoryDeviation))
+		}
+	}
+	if g.limits.MaxOracleDeviation > 0 {
+		ticker, err := g.priceOracleClient.GetLastTicker(ctx, g.oracleSymbol)
+		if err != nil {
+			return nil, err
+		}
+		oraclePrice, err := ticker.Price()
+		if err != nil {
+			return nil, err
+		}
+		if deviation := math.Abs(price-oraclePrice) / oraclePrice; oraclePrice > 0 && deviation > g.limits.MaxOracleDeviation {
+			breaches = append(breaches, fmt.Sprintf("oracle deviation %f above %f", deviation, g.limits.MaxOracleDeviation))
+		}
+	}
+	return breaches, nil
+}
+
+// halt cancels the open orders of the pair and saves it as halted, a failed cancel doesn't prevent the halt.
+func (g *Guard) halt(ctx context.Context, reason string) error {
+	level.Error(g.logger).Log("msg", "risk limit breached, halting trading", "reason", reason)
+	if err := g.ExchangeClient.CancelAllOrders(ctx, g.symbol); err != nil {
+		level.Error(g.logger).Log("msg", "failed to cancel the open orders of the halted pair", "err", err)
+	}
+	now := time.Now().UTC()
+	if _, err := g.store.Update(g.name, func(state *models.RiskState) {
+		state.Halted, state.Reason, state.HaltedAt = true, reason, &now
+	}); err != nil {
+		level.Error(g.logger).Log("msg", "failed to save the risk state", "err", err)
+	}
+	return fmt.Errorf("%w: %s", ErrRiskLimitBreached, reason)
+}
+
+func (g *Guard) hasDailyLimits() bool {
+	return g.limits.MaxDailyVolume > 0 || g.limits.MaxDailyFees > 0
+}
+
+// syncFills adds the fills since the last counted one to the daily totals, so that every fill of the day is fetched once
+// however many fills the exchange returns per request. The volume placed by the guard is counted when the orders are
+// placed, the fills reconcile it with the orders placed by others or whose placement failed after reaching the exchange.
+// Fees are converted into quote currency, fees paid in base currency are valued at the fill price and fees paid in
+// other assets aren't counted.
+func (g *Guard) syncFills(ctx context.Context) (models.RiskState, error) {
+	state := g.store.Get(g.name)
+	rollover(&state)
+	since := time.Now().UTC().Truncate(24 * time.Hour)
+	if state.FillsSyncedAt != nil && state.FillsSyncedAt.After(since) {
+		since = *state.FillsSyncedAt
+	}
+	fills, err := g.ExchangeClient.GetFills(ctx, g.symbol, since)
+	if err != nil {
+		return state, err
+	}
+	syncedAt, tradeIDs := since, state.SyncedTradeIDs
+	var volume, fees float64
+	var counted int
+	for _, fill := range fills {
+		if fill.Time.Before(since) || (fill.Time.Equal(since) && slices.Contains(state.SyncedTradeIDs, fill.TradeID)) {
+			continue
+		}
+		price, err := utils.ParseFloat(fill.Price)
+		if err != nil {
+			return state, fmt.Errorf("failed parse fill price: %s", fill.Price)
+		}
+		qty, err := utils.ParseFloat(fill.Qty)
+		if err != nil {
+			return state, fmt.Errorf("failed parse fill qty: %s", fill.Qty)
+		}
+		fee, err := g.feeInQuote(fill, price)
+		if err != nil {
+			return state, err
+		}
+		volume, fees, counted = volume+price*qty, fees+fee, counted+1
+		switch {
+		case fill.Time.After(syncedAt):
+			syncedAt, tradeIDs = fill.Time, []string{fill.TradeID}
+		case fill.Time.Equal(syncedAt):
+			tradeIDs = append(slices.Clone(tradeIDs), fill.TradeID)
+		}
+	}
+	if counted == 0 {
+		return state, nil
+	}
+	state, err = g.store.Update(g.name, func(state *models.RiskState) {
+		rollover(state)
+		state.FilledVolume += volume
+		state.Fees += fees
+		state.FillsSyncedAt, state.SyncedTradeIDs = &syncedAt, tradeIDs
+	})
+	if err != nil {
+		level.Error(g.logger).Log("msg", "failed to save the risk state", "err", err)
+	}
+	return state, nil
+}
+
+func (g *Guard) feeInQuote(fill *models.Fill, price float64) (float64, error) {
+	if fill.Fee == "" {
+		return 0, nil
+	}
+	fee, err := utils.ParseFloat(fill.Fee)
+	if err != nil {
+		return 0, fmt.Errorf("failed parse fill fee: %s", fill.Fee)
+	}
+	switch {
+	case strings.EqualFold(fill.FeeAsset, g.pair.Quote):
+		return fee, nil
+	case strings.EqualFold(fill.FeeAsset, g.pair.Base):
+		return fee * price, nil
+	}
+	return 0, nil
+}
+
+// getInventoryRatio values the base and quote holdings, including the balances locked by open orders, at the price
+// and returns the base share of their value.
+func (g *Guard) getInventoryRatio(ctx context.Context, price float64) (float64, error) {
+	balances, err := g.ExchangeClient.GetBalances(ctx)
+	if err != nil {
+		return 0, err
+	}
+	base, err := models.FindBalance(balances, g.pair.Base).TotalAmount()
+	if err != nil {
+		return 0, err
+	}
+	quote, err := models.FindBalance(balances, g.pair.Quote).TotalAmount()
+	if err != nil {
+		return 0, err
+	}
+	value := base*price + quote
+	if value <= 0 {
+		return g.limits.TargetRatio, nil
+	}
+	return base * price / value, nil
+}
+
+// rollover resets the daily totals of a previous day.
+func rollover(state *models.RiskState) {
+	if today := time.Now().UTC().Format(dayLayout); state.Day != today {
+		state.Day, state.Volume, state.FilledVolume, state.Fees = today, 0, 0, 0
+		state.FillsSyncedAt, state.SyncedTradeIDs = nil, nil
+	}
+}
@@ -0,0 +1,215 @@
+package risk_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/imbonda/vmm-bot/cmd/interfaces/mocks"
+	"github.com/imbonda/vmm-bot/internal/risk"
+	"github.com/imbonda/vmm-bot/pkg/models"
+)
+
+const symbol = "BTCUSDT"
+
+func newGuard(t *testing.T, client *mocks.ExchangeClient, store *risk.Store, limits risk.Limits) *risk.Guard {
+	t.Helper()
+	guard, err := risk.NewGuard(context.Background(), &risk.NewGuardInput{
+		ExchangeClient:    client,
+		PriceOracleClient: client,
+		Name:              "default",
+		Pair:              models.Pair{Base: "BTC", Quote: "USDT"},
+		Symbol:            symbol,
+		OracleSymbol:      symbol,
+		Limits:            limits,
+		Store:             store,
+		Logger:            log.NewNopLogger(),
+	})
+	require.NoError(t, err)
+	return guard
+}
+
+func newStore(t *testing.T, path string) *risk.Store {
+	t.Helper()
+	store, err := risk.NewStore(path)
+	require.NoError(t, err)
+	return store
+}
+
+func order(price, qty string) *models.Order {
+	return &models.Order{Symbol: symbol, Price: price, Qty: qty, Action: models.Buy}
+}
+
+func fill(tradeID string, at time.Time, price, qty, fee, feeAsset string) *models.Fill {
+	return &models.Fill{Symbol: symbol, TradeID: tradeID, Price: price, Qty: qty, Fee: fee, FeeAsset: feeAsset, Time: at}
+}
+
+func TestPlaceOrderLimits(t *testing.T) {
+	now := time.Now().UTC()
+	tests := []struct {
+		name     string
+		limits   risk.Limits
+		setup    func(client *mocks.ExchangeClient)
+		breached bool
+	}{
+		{
+			name:   "within the limits",
+			limits: risk.Limits{MaxOrderNotional: 200, MaxDailyVolume: 1000, MaxDailyFees: 10},
+			setup: func(client *mocks.ExchangeClient) {
+				client.On("GetFills", mock.Anything, symbol, mock.Anything).Return(
+					[]*models.Fill{fill("1", now, "100", "1", "0.1", "USDT")}, nil)
+			},
+		},
+		{
+			name:     "order notional",
+			limits:   risk.Limits{MaxOrderNotional: 50},
+			breached: true,
+		},
+		{
+			name:   "daily volume of the fills",
+			limits: risk.Limits{MaxDailyVolume: 250},
+			setup: func(client *mocks.ExchangeClient) {
+				client.On("GetFills", mock.Anything, symbol, mock.Anything).Return([]*models.Fill{
+					fill("1", now, "100", "1", "", ""),
+					fill("2", now, "100", "1", "", ""),
+				}, nil)
+			},
+			breached: true,
+		},
+		{
+			name:   "daily fees in base and quote currency",
+			limits: risk.Limits{MaxDailyFees: 2.5},
+			setup: func(client *mocks.ExchangeClient) {
+				client.On("GetFills", mock.Anything, symbol, mock.Anything).Return([]*models.Fill{
+					fill("1", now, "100", "1", "2", "USDT"),
+					fill("2", now, "100", "1", "0.01", "BTC"),
+					fill("3", now, "100", "1", "5", "BNB"),
+				}, nil)
+			},
+			breached: true,
+		},
+		{
+			name:   "inventory deviation",
+			limits: risk.Limits{MaxInventoryDeviation: 0.3, TargetRatio: 0.5},
+			setup: func(client *mocks.ExchangeClient) {
+				client.On("GetBalances", mock.Anything).Return([]*models.Balance{
+					{Asset: "BTC", Free: "9", Locked: "1"},
+					{Asset: "USDT", Free: "100", Locked: "0"},
+				}, nil)
+			},
+			breached: true,
+		},
+		{
+			name:   "oracle deviation",
+			limits: risk.Limits{MaxOracleDeviation: 0.05},
+			setup: func(client *mocks.ExchangeClient) {
+				client.On("GetLastTicker", mock.Anything, symbol).Return(&models.Ticker{Symbol: symbol, LastPrice: "110"}, nil)
+			},
+			breached: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := mocks.NewExchangeClient(t)
+			if test.setup != nil {
+				test.setup(client)
+			}
+			if test.breached {
+				client.On("CancelAllOrders", mock.Anything, symbol).Return(nil).Once()
+			} else {
+				client.On("PlaceOrder", mock.Anything, mock.Anything).Return(&models.OrderRecord{OrderID: "1"}, nil).Once()
+			}
+			guard := newGuard(t, client, newStore(t, filepath.Join(t.TempDir(), "risk.json")), test.limits)
+
+			_, err := guard.PlaceOrder(context.Background(), order("100", "1"))
+			if test.breached {
+				assert.ErrorIs(t, err, risk.ErrRiskLimitBreached)
+				assert.True(t, guard.State().Halted)
+				return
+			}
+			assert.NoError(t, err)
+			assert.False(t, guard.State().Halted)
+		})
+	}
+}
+
+func TestDailyVolumeCountsPlacedOrders(t *testing.T) {
+	client := mocks.NewExchangeClient(t)
+	client.On("GetFills", mock.Anything, symbol, mock.Anything).Return(nil, nil)
+	client.On("PlaceOrder", mock.Anything, mock.Anything).Return(&models.OrderRecord{OrderID: "1"}, nil).Once()
+	client.On("CancelAllOrders", mock.Anything, symbol).Return(nil).Once()
+	path := filepath.Join(t.TempDir(), "risk.json")
+	guard := newGuard(t, client, newStore(t, path), risk.Limits{MaxDailyVolume: 150})
+
+	_, err := guard.PlaceOrder(context.Background(), order("100", "1"))
+	require.NoError(t, err)
+	assert.Equal(t, 100.0, guard.State().Volume)
+
+	// The volume of the day is kept across restarts, and the next order is refused before it's placed.
+	guard = newGuard(t, client, newStore(t, path), risk.Limits{MaxDailyVolume: 150})
+	_, err = guard.PlaceOrder(context.Background(), order("100", "1"))
+	assert.ErrorIs(t, err, risk.ErrRiskLimitBreached)
+}
+
+func TestFillsAreCountedOnce(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	first := []*models.Fill{
+		fill("1", now, "100", "1", "1", "USDT"),
+		fill("2", now, "100", "1", "1", "USDT"),
+	}
+	// The exchange returns the fills of the time the last sync reached again.
+	second := []*models.Fill{
+		fill("2", now, "100", "1", "1", "USDT"),
+		fill("3", now, "100", "1", "1", "USDT"),
+	}
+	client := mocks.NewExchangeClient(t)
+	client.On("GetFills", mock.Anything, symbol, mock.Anything).Return(first, nil).Once()
+	client.On("GetFills", mock.Anything, symbol, now).Return(second, nil).Once()
+	client.On("PlaceOrder", mock.Anything, mock.Anything).Return(&models.OrderRecord{OrderID: "1"}, nil).Twice()
+	guard := newGuard(t, client, newStore(t, filepath.Join(t.TempDir(), "risk.json")), risk.Limits{MaxDailyFees: 10})
+
+	for range 2 {
+		_, err := guard.PlaceOrder(context.Background(), order("1", "1"))
+		require.NoError(t, err)
+	}
+	state := guard.State()
+	assert.Equal(t, 3.0, state.Fees)
+	assert.Equal(t, 300.0, state.FilledVolume)
+	assert.ElementsMatch(t, []string{"1", "2", "3"}, state.SyncedTradeIDs)
+}
+
+func TestHaltSurvivesRestartsUntilResumed(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "risk.json")
+	client := mocks.NewExchangeClient(t)
+	client.On("CancelAllOrders", mock.Anything, symbol).Return(nil).Once()
+	guard := newGuard(t, client, newStore(t, path), risk.Limits{MaxOrderNotional: 50})
+
+	_, err := guard.PlaceOrder(ctx, order("100", "1"))
+	require.ErrorIs(t, err, risk.ErrRiskLimitBreached)
+
+	// A restarted bot loads the halt from the state file.
+	guard = newGuard(t, client, newStore(t, path), risk.Limits{MaxOrderNotional: 500})
+	state := guard.State()
+	assert.True(t, state.Halted)
+	assert.Contains(t, state.Reason, "order notional")
+	_, err = guard.PlaceOrder(ctx, order("100", "1"))
+	assert.ErrorIs(t, err, risk.ErrTradingHalted)
+
+	state, err = guard.Resume(ctx)
+	require.NoError(t, err)
+	assert.False(t, state.Halted)
+	_, err = guard.Resume(ctx)
+	assert.ErrorIs(t, err, risk.ErrNotHalted)
+
+	client.On("PlaceOrder", mock.Anything, mock.Anything).Return(&models.OrderRecord{OrderID: "1"}, nil).Once()
+	_, err = guard.PlaceOrder(ctx, order("100", "1"))
+	assert.NoError(t, err)
+	assert.False(t, newStore(t, path).Get("default").Halted)
+}
@@ -0,0 +1,86 @@
+package risk
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/imbonda/vmm-bot/pkg/models"
+)
+
+// Store keeps the risk state of every pair in a JSON file, so that a halted pair stays halted across restarts.
+type Store struct {
+	mu     sync.Mutex
+	path   string
+	states map[string]*models.RiskState
+}
+
+// NewStore loads the states saved at the path, a missing file has no states yet.
+func NewStore(path string) (*Store, error) {
+	store := &Store{
+		path:   path,
+		states: make(map[string]*models.RiskState),
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(data, &store.states); err != nil {
+		return nil, fmt.Errorf("failed to parse risk state file %s: %w", path, err)
+	}
+	return store, nil
+}
+
+// Get returns a copy of the state of the pair.
+func (s *Store) Get(pair string) models.RiskState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if state, found := s.states[pair]; found {
+		return *state
+	}
+	return models.RiskState{}
+}
+
+// Update applies the change to the state of the pair and saves the states.
+func (s *Store) Update(pair string, change func(state *models.RiskState)) (models.RiskState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, found := s.states[pair]
+	if !found {
+		state = &models.RiskState{}
+		s.states[pair] = state
+	}
+	change(state)
+	return *state, s.save()
+}
+
+// save replaces the file through a temporary one, so that a crash doesn't leave it truncated. The directory of the
+// file is created on the first save.
+func (s *Store) save() error {
+	data, err := json.MarshalIndent(s.states, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
@@ -0,0 +1,107 @@
+package trader
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/go-kit/log/level"
+
+	exchangeErrors "github.com/imbonda/vmm-bot/pkg/exchanges/errors"
+	"github.com/imbonda/vmm-bot/pkg/models"
+	"github.com/imbonda/vmm-bot/pkg/utils"
+)
+
+// quoteFundingBuffer is kept free on top of the buy notional, to cover fees and the rounding of the exchanges.
+const quoteFundingBuffer = 0.01
+
+var ErrInsufficientBalance = exchangeErrors.ErrInsufficientBalance
+
+// fundTrade checks that the sell order can be funded in base and the buy order in quote currency.
+// Both sides are shrunk by the same factor as long as the unskewed qty doesn't go below the min trade amount,
+// otherwise the trade is skipped.
+func (t *Trader) fundTrade(params *tradeParams) (*models.FundingCheck, error) {
+	freeBase, err := models.FindBalance(params.balances, t.pair.Base).FreeAmount()
+	if err != nil {
+		return nil, err
+	}
+	freeQuote, err := models.FindBalance(params.balances, t.pair.Quote).FreeAmount()
+	if err != nil {
+		return nil, err
+	}
+	price, err := utils.ParseFloat(params.price)
+	if err != nil {
+		return nil, fmt.Errorf("failed parse trade price: %s", params.price)
+	}
+	sellQty, err := parseSideQty(params.sellQty)
+	if err != nil {
+		return nil, err
+	}
+	buyQty, err := parseSideQty(params.buyQty)
+	if err != nil {
+		return nil, err
+	}
+	check := &models.FundingCheck{
+		Base:             t.pair.Base,
+		Quote:            t.pair.Quote,
+		FreeBase:         freeBase,
+		FreeQuote:        freeQuote,
+		RequestedSellQty: sellQty,
+		RequestedBuyQty:  buyQty,
+		FundedSellQty:    sellQty,
+		FundedBuyQty:     buyQty,
+	}
+	scale := 1.0
+	if sellQty > 0 {
+		scale = math.Min(scale, freeBase/sellQty)
+	}
+	if buyQty > 0 && price > 0 {
+		scale = math.Min(scale, freeQuote/(buyQty*price*(1+quoteFundingBuffer)))
+	}
+	scale = math.Max(scale, 0)
+	if scale >= 1 {
+		return check, nil
+	}
+	if params.qty*scale < params.qtyMin || scale == 0 {
+		return check, fmt.Errorf(
+			"%w. free %s: %f, free %s: %f, sell qty: %f, buy qty: %f, funded share: %f, min qty: %f, price: %f",
+			ErrInsufficientBalance,
+			t.pair.Base,
+			freeBase,
+			t.pair.Quote,
+			freeQuote,
+			sellQty,
+			buyQty,
+			scale,
+			params.qtyMin,
+			price,
+		)
+	}
+	check.Shrunk = true
+	check.FundedSellQty = utils.FloorToStep(sellQty*scale, params.rules.lotStep)
+	check.FundedBuyQty = utils.FloorToStep(buyQty*scale, params.rules.lotStep)
+	params.sellQty = t.formatSideQty(params.rules, check.FundedSellQty, price, false)
+	params.buyQty = t.formatSideQty(params.rules, check.FundedBuyQty, price, false)
+	level.Warn(t.logger).Log(
+		"msg", "shrunk trade qty to the free balances",
+		"symbol", t.symbol,
+		"sellQty", sellQty,
+		"buyQty", buyQty,
+		"fundedSellQty", check.FundedSellQty,
+		"fundedBuyQty", check.FundedBuyQty,
+		"freeBase", freeBase,
+		"freeQuote", freeQuote,
+	)
+	return check, nil
+}
+
+// parseSideQty parses the qty of a side, skipped sides have none.
+func parseSideQty(qty string) (float64, error) {
+	if qty == "" {
+		return 0, nil
+	}
+	value, err := utils.ParseFloat(qty)
+	if err != nil {
+		return 0, fmt.Errorf("failed parse trade qty: %s", qty)
+	}
+	return value, nil
+}
@@ -0,0 +1,63 @@
+package trader
+
+import (
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/imbonda/vmm-bot/pkg/models"
+)
+
+func TestFundTrade(t *testing.T) {
+	tests := []struct {
+		name      string
+		freeBase  string
+		freeQuote string
+		sellQty   string
+		buyQty    string
+		fundedQty float64
+		shrunk    bool
+		failed    bool
+	}{
+		{name: "funded", freeBase: "10", freeQuote: "1000", sellQty: "2", buyQty: "2", fundedQty: 2},
+		{name: "short base", freeBase: "1.5", freeQuote: "1000", sellQty: "2", buyQty: "2", fundedQty: 1.5, shrunk: true},
+		{name: "short quote with the buffer", freeBase: "10", freeQuote: "100", sellQty: "2", buyQty: "2", fundedQty: 1.5, shrunk: true},
+		{name: "skipped sell side", freeBase: "0", freeQuote: "1000", buyQty: "2", fundedQty: 2},
+		{name: "below the min trade amount", freeBase: "0.5", freeQuote: "1000", sellQty: "2", buyQty: "2", failed: true},
+		{name: "no funds", freeBase: "0", freeQuote: "0", sellQty: "2", buyQty: "2", failed: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			trader := &Trader{pair: pair, symbol: symbol, logger: log.NewNopLogger()}
+			params := &tradeParams{
+				price:   "50",
+				qty:     2,
+				qtyMin:  1,
+				sellQty: test.sellQty,
+				buyQty:  test.buyQty,
+				balances: []*models.Balance{
+					{Asset: "BTC", Free: test.freeBase, Locked: "0"},
+					{Asset: "USDT", Free: test.freeQuote, Locked: "0"},
+				},
+				rules: &symbolRules{tickSize: 0.01, lotStep: 0.5, priceDecimals: 2, amountDecimals: 1},
+			}
+			check, err := trader.fundTrade(params)
+			if test.failed {
+				assert.ErrorIs(t, err, ErrInsufficientBalance)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.shrunk, check.Shrunk)
+			if test.sellQty != "" {
+				assert.InDelta(t, test.fundedQty, check.FundedSellQty, 1e-9)
+			}
+			assert.InDelta(t, test.fundedQty, check.FundedBuyQty, 1e-9)
+			// The funded qty of the sides is what's placed.
+			buyQty, err := parseSideQty(params.buyQty)
+			require.NoError(t, err)
+			assert.InDelta(t, test.fundedQty, buyQty, 1e-9)
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package trader
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/imbonda/vmm-bot/pkg/models"
+)
+
+type InventoryConfig struct {
+	// TargetRatio is the share of the base currency value in the inventory value to steer toward.
+	TargetRatio float64
+	// MinRatio and MaxRatio are hard bounds, the side which would push the ratio past them is stopped.
+	MinRatio float64
+	MaxRatio float64
+	// PriceSkew is the share of the price range cut off at a full deviation from the target ratio.
+	PriceSkew float64
+	// QtySkew is the relative change of each side's qty at a full deviation from the target ratio.
+	QtySkew float64
+}
+
+// inventoryManager skews the quotes so that fills against outside flow steer the inventory back to the target ratio.
+// Holding too much base lowers the price and grows the sell side, holding too little base does the opposite.
+type inventoryManager struct {
+	cfg InventoryConfig
+}
+
+func newInventoryManager(cfg *InventoryConfig) (*inventoryManager, error) {
+	if cfg.MinRatio < 0 || cfg.MinRatio >= cfg.TargetRatio || cfg.TargetRatio >= cfg.MaxRatio || cfg.MaxRatio > 1 {
+		return nil, fmt.Errorf(
+			"invalid inventory ratios, expected 0 <= min < target < max <= 1. min: %f, target: %f, max: %f",
+			cfg.MinRatio,
+			cfg.TargetRatio,
+			cfg.MaxRatio,
+		)
+	}
+	if cfg.PriceSkew < 0 || cfg.PriceSkew > 1 || cfg.QtySkew < 0 || cfg.QtySkew > 1 {
+		return nil, fmt.Errorf("invalid inventory skew, expected within [0, 1]. price: %f, qty: %f", cfg.PriceSkew, cfg.QtySkew)
+	}
+	return &inventoryManager{cfg: *cfg}, nil
+}
+
+// skew values the base and quote holdings, including the balances locked by open orders, at the given price.
+func (m *inventoryManager) skew(balances []*models.Balance, baseAsset, quoteAsset string, price float64) (*models.InventorySkew, error) {
+	base, err := models.FindBalance(balances, baseAsset).TotalAmount()
+	if err != nil {
+		return nil, err
+	}
+	quote, err := models.FindBalance(balances, quoteAsset).TotalAmount()
+	if err != nil {
+		return nil, err
+	}
+	skew := &models.InventorySkew{
+		Base:          base,
+		Quote:         quote,
+		Ratio:         m.cfg.TargetRatio,
+		TargetRatio:   m.cfg.TargetRatio,
+		SellQtyFactor: 1,
+		BuyQtyFactor:  1,
+	}
+	if value := base*price + quote; value > 0 {
+		skew.Ratio = base * price / value
+	}
+	if skew.Ratio > m.cfg.TargetRatio {
+		skew.Deviation = math.Min((skew.Ratio-m.cfg.TargetRatio)/(m.cfg.MaxRatio-m.cfg.TargetRatio), 1)
+	} else {
+		skew.Deviation = -math.Min((m.cfg.TargetRatio-skew.Ratio)/(m.cfg.TargetRatio-m.cfg.MinRatio), 1)
+	}
+	skew.SellQtyFactor = 1 + m.cfg.QtySkew*skew.Deviation
+	skew.BuyQtyFactor = 1 - m.cfg.QtySkew*skew.Deviation
+	// Buying grows the base share and selling shrinks it.
+	skew.BuyStopped = skew.Ratio >= m.cfg.MaxRatio
+	skew.SellStopped = skew.Ratio <= m.cfg.MinRatio
+	return skew, nil
+}
+
+// skewRange cuts off the top of the price range when holding too much base, and the bottom when holding too little.
+func (m *inventoryManager) skewRange(skew *models.InventorySkew, min, max float64) (float64, float64) {
+	cut := (max - min) * m.cfg.PriceSkew * math.Abs(skew.Deviation)
+	if skew.Deviation > 0 {
+		return min, max - cut
+	}
+	return min + cut, max
+}
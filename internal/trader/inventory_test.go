@@ -0,0 +1,73 @@
+package trader
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/imbonda/vmm-bot/pkg/models"
+)
+
+func TestInventorySkew(t *testing.T) {
+	manager, err := newInventoryManager(&InventoryConfig{TargetRatio: 0.5, MinRatio: 0.2, MaxRatio: 0.8, PriceSkew: 0.5, QtySkew: 0.5})
+	require.NoError(t, err)
+	tests := []struct {
+		name        string
+		base        string
+		quote       string
+		ratio       float64
+		deviation   float64
+		sellFactor  float64
+		buyFactor   float64
+		sellStopped bool
+		buyStopped  bool
+	}{
+		{name: "on target", base: "5", quote: "500", ratio: 0.5, sellFactor: 1, buyFactor: 1},
+		{name: "too much base", base: "6.5", quote: "350", ratio: 0.65, deviation: 0.5, sellFactor: 1.25, buyFactor: 0.75},
+		{name: "too little base", base: "3.5", quote: "650", ratio: 0.35, deviation: -0.5, sellFactor: 0.75, buyFactor: 1.25},
+		{name: "at the max ratio", base: "9", quote: "100", ratio: 0.9, deviation: 1, sellFactor: 1.5, buyFactor: 0.5, buyStopped: true},
+		{name: "at the min ratio", base: "1", quote: "900", ratio: 0.1, deviation: -1, sellFactor: 0.5, buyFactor: 1.5, sellStopped: true},
+		{name: "empty inventory", base: "0", quote: "0", ratio: 0.5, sellFactor: 1, buyFactor: 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Half of the base is locked by open orders, which still counts toward the inventory.
+			skew, err := manager.skew([]*models.Balance{
+				{Asset: "BTC", Free: test.base, Locked: "0"},
+				{Asset: "USDT", Free: "0", Locked: test.quote},
+			}, "BTC", "USDT", 100)
+			require.NoError(t, err)
+			assert.InDelta(t, test.ratio, skew.Ratio, 1e-9)
+			assert.InDelta(t, test.deviation, skew.Deviation, 1e-9)
+			assert.InDelta(t, test.sellFactor, skew.SellQtyFactor, 1e-9)
+			assert.InDelta(t, test.buyFactor, skew.BuyQtyFactor, 1e-9)
+			assert.Equal(t, test.sellStopped, skew.SellStopped)
+			assert.Equal(t, test.buyStopped, skew.BuyStopped)
+		})
+	}
+}
+
+func TestInventorySkewRange(t *testing.T) {
+	manager, err := newInventoryManager(&InventoryConfig{TargetRatio: 0.5, MinRatio: 0.2, MaxRatio: 0.8, PriceSkew: 0.5, QtySkew: 0.5})
+	require.NoError(t, err)
+
+	low, high := manager.skewRange(&models.InventorySkew{Deviation: 1}, 100, 110)
+	assert.InDelta(t, 100, low, 1e-9)
+	assert.InDelta(t, 105, high, 1e-9)
+	low, high = manager.skewRange(&models.InventorySkew{Deviation: -0.5}, 100, 110)
+	assert.InDelta(t, 102.5, low, 1e-9)
+	assert.InDelta(t, 110, high, 1e-9)
+}
+
+func TestNewInventoryManagerRejectsInvalidConfigs(t *testing.T) {
+	for _, cfg := range []InventoryConfig{
+		{TargetRatio: 0.5, MinRatio: 0.5, MaxRatio: 0.8},
+		{TargetRatio: 0.5, MinRatio: 0.2, MaxRatio: 1.2},
+		{TargetRatio: 0.5, MinRatio: 0.2, MaxRatio: 0.8, PriceSkew: 2},
+		{TargetRatio: 0.5, MinRatio: 0.2, MaxRatio: 0.8, QtySkew: -1},
+	} {
+		_, err := newInventoryManager(&cfg)
+		assert.Error(t, err, "%+v", cfg)
+	}
+}
@@ -0,0 +1,83 @@
+package trader
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+
+	"github.com/imbonda/vmm-bot/pkg/models"
+)
+
+var ErrOracleCheckFailed = fmt.Errorf("oracle check failed")
+
+type OracleGuardConfig struct {
+	// MaxDeviation caps the distance of the last price from the oracle price, relative to the oracle price.
+	MaxDeviation float64
+	// MaxTickerAge caps the age of the tickers whose time is reported by the exchange.
+	MaxTickerAge time.Duration
+	// HaltEnabled keeps skipping the iterations after a failed check, until the deviation is back within half of MaxDeviation.
+	HaltEnabled bool
+}
+
+// oracleGuard skips the iterations whose venue price drifted away from the oracle price, or whose tickers are stale,
+// instead of pricing them against the spread alone.
+type oracleGuard struct {
+	cfg    OracleGuardConfig
+	mu     sync.Mutex
+	halted bool
+	logger log.Logger
+}
+
+func newOracleGuard(cfg *OracleGuardConfig, logger log.Logger) (*oracleGuard, error) {
+	if cfg.MaxDeviation < 0 || cfg.MaxTickerAge < 0 {
+		return nil, fmt.Errorf(
+			"invalid oracle guard, expected non negative limits. deviation: %f, ticker age: %s",
+			cfg.MaxDeviation,
+			cfg.MaxTickerAge,
+		)
+	}
+	return &oracleGuard{cfg: *cfg, logger: logger}, nil
+}
+
+func (g *oracleGuard) check(ticker, oracleTicker *models.Ticker, lastPrice, oraclePrice float64) *models.OracleCheck {
+	check := &models.OracleCheck{
+		LastPrice:   lastPrice,
+		OraclePrice: oraclePrice,
+	}
+	if oraclePrice > 0 {
+		check.Deviation = math.Abs(lastPrice-oraclePrice) / oraclePrice
+	}
+	if !ticker.Time.IsZero() {
+		check.TickerAge = models.Duration(time.Since(ticker.Time))
+	}
+	if !oracleTicker.Time.IsZero() {
+		check.OracleTickerAge = models.Duration(time.Since(oracleTicker.Time))
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	switch {
+	case g.cfg.MaxTickerAge > 0 && time.Duration(check.TickerAge) > g.cfg.MaxTickerAge:
+		check.SkipReason = models.OracleSkipStaleTicker
+	case g.cfg.MaxTickerAge > 0 && time.Duration(check.OracleTickerAge) > g.cfg.MaxTickerAge:
+		check.SkipReason = models.OracleSkipStaleOracle
+	case g.cfg.MaxDeviation > 0 && check.Deviation > g.cfg.MaxDeviation:
+		check.SkipReason = models.OracleSkipDeviation
+	case g.halted && g.cfg.MaxDeviation > 0 && check.Deviation > g.cfg.MaxDeviation/2:
+		check.SkipReason = models.OracleSkipNotConverged
+	}
+	switch {
+	case check.SkipReason != "" && g.cfg.HaltEnabled && !g.halted:
+		g.halted = true
+		level.Warn(g.logger).Log("msg", "halted trading until the prices reconverge", "reason", check.SkipReason, "deviation", check.Deviation)
+	case check.SkipReason == "" && g.halted:
+		g.halted = false
+		level.Info(g.logger).Log("msg", "prices reconverged, resumed trading", "deviation", check.Deviation)
+	}
+	check.Halted = g.halted
+	return check
+}
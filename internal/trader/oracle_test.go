@@ -0,0 +1,57 @@
+package trader
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/imbonda/vmm-bot/pkg/models"
+)
+
+func TestOracleGuardCheck(t *testing.T) {
+	fresh := &models.Ticker{Time: time.Now()}
+	stale := &models.Ticker{Time: time.Now().Add(-time.Minute)}
+	tests := []struct {
+		name         string
+		ticker       *models.Ticker
+		oracleTicker *models.Ticker
+		lastPrice    float64
+		reason       models.OracleSkipReason
+	}{
+		{name: "within the limits", ticker: fresh, oracleTicker: fresh, lastPrice: 101},
+		{name: "unreported ticker times", ticker: &models.Ticker{}, oracleTicker: &models.Ticker{}, lastPrice: 100},
+		{name: "deviation", ticker: fresh, oracleTicker: fresh, lastPrice: 103, reason: models.OracleSkipDeviation},
+		{name: "stale ticker", ticker: stale, oracleTicker: fresh, lastPrice: 100, reason: models.OracleSkipStaleTicker},
+		{name: "stale oracle ticker", ticker: fresh, oracleTicker: stale, lastPrice: 100, reason: models.OracleSkipStaleOracle},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			guard, err := newOracleGuard(&OracleGuardConfig{MaxDeviation: 0.02, MaxTickerAge: 10 * time.Second}, log.NewNopLogger())
+			require.NoError(t, err)
+			check := guard.check(test.ticker, test.oracleTicker, test.lastPrice, 100)
+			assert.Equal(t, test.reason, check.SkipReason)
+			assert.InDelta(t, (test.lastPrice-100)/100, check.Deviation, 1e-9)
+			assert.False(t, check.Halted)
+		})
+	}
+}
+
+func TestOracleGuardHaltsUntilThePricesReconverge(t *testing.T) {
+	guard, err := newOracleGuard(&OracleGuardConfig{MaxDeviation: 0.02, HaltEnabled: true}, log.NewNopLogger())
+	require.NoError(t, err)
+	ticker := &models.Ticker{}
+
+	check := guard.check(ticker, ticker, 103, 100)
+	assert.Equal(t, models.OracleSkipDeviation, check.SkipReason)
+	assert.True(t, check.Halted)
+	// Back within the max deviation, but not within half of it.
+	check = guard.check(ticker, ticker, 101.5, 100)
+	assert.Equal(t, models.OracleSkipNotConverged, check.SkipReason)
+	assert.True(t, check.Halted)
+	check = guard.check(ticker, ticker, 100.5, 100)
+	assert.Empty(t, check.SkipReason)
+	assert.False(t, check.Halted)
+}
@@ -0,0 +1,44 @@
+package trader
+
+import (
+	"fmt"
+
+	"github.com/go-kit/log/level"
+
+	"github.com/imbonda/vmm-bot/pkg/models"
+)
+
+var ErrNoPreviousParams = fmt.Errorf("no previous params")
+
+// Params returns the trading parameters of the next iterations.
+func (t *Trader) Params() models.TradeParams {
+	return *t.params.Load()
+}
+
+// UpdateParams validates and replaces the trading parameters, returning the replaced ones which are kept for RollbackParams.
+// An iteration in progress completes with the parameters it started with.
+func (t *Trader) UpdateParams(params models.TradeParams) (models.TradeParams, error) {
+	if err := params.Validate(); err != nil {
+		return models.TradeParams{}, err
+	}
+	t.paramsMu.Lock()
+	defer t.paramsMu.Unlock()
+	previous := t.params.Swap(&params)
+	t.previousParams = previous
+	level.Info(t.logger).Log("msg", "updated trade params", "symbol", t.symbol, "change", models.DiffParams(*previous, params))
+	return *previous, nil
+}
+
+// RollbackParams restores and returns the trading parameters replaced by the last update, rolling back twice restores the update.
+func (t *Trader) RollbackParams() (models.TradeParams, error) {
+	t.paramsMu.Lock()
+	defer t.paramsMu.Unlock()
+	if t.previousParams == nil {
+		return models.TradeParams{}, ErrNoPreviousParams
+	}
+	previous := t.params.Swap(t.previousParams)
+	t.previousParams = previous
+	current := t.params.Load()
+	level.Info(t.logger).Log("msg", "rolled back trade params", "symbol", t.symbol, "change", models.DiffParams(*previous, *current))
+	return *current, nil
+}
@@ -0,0 +1,59 @@
+package trader
+
+import (
+	"context"
+
+	"github.com/go-kit/log/level"
+
+	"github.com/imbonda/vmm-bot/pkg/utils"
+)
+
+// symbolRules are the trading rules of the traded symbol, as reported by the exchange.
+type symbolRules struct {
+	tickSize       float64
+	lotStep        float64
+	minQty         float64
+	minNotional    float64
+	priceDecimals  int
+	amountDecimals int
+	halted         bool
+}
+
+// loadRules fetches the rules of the symbol from the exchange, and applies them from the next trade params.
+func (t *Trader) loadRules(ctx context.Context) (*symbolRules, error) {
+	info, err := t.exchangeClient.GetSymbolInfo(ctx, t.symbol)
+	if err != nil {
+		return nil, err
+	}
+	tickSize, lotStep, minQty, minNotional, err := info.Rules()
+	if err != nil {
+		return nil, err
+	}
+	if tickSize <= 0 {
+		tickSize = t.defaultTickSize
+	}
+	if lotStep <= 0 {
+		lotStep = t.defaultLotStep
+	}
+	rules := &symbolRules{
+		tickSize:       tickSize,
+		lotStep:        lotStep,
+		minQty:         minQty,
+		minNotional:    minNotional,
+		priceDecimals:  utils.StepDecimals(tickSize),
+		amountDecimals: utils.StepDecimals(lotStep),
+		halted:         !info.IsTrading(),
+	}
+	if previous := t.rules.Swap(rules); previous != nil && *previous != *rules {
+		level.Info(t.logger).Log(
+			"msg", "symbol rules changed",
+			"symbol", t.symbol,
+			"status", info.Status,
+			"tickSize", tickSize,
+			"lotStep", lotStep,
+			"minQty", minQty,
+			"minNotional", minNotional,
+		)
+	}
+	return rules, nil
+}
@@ -2,13 +2,21 @@ package trader
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"runtime/debug"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
 
 	"github.com/imbonda/vmm-bot/cmd/interfaces"
+	"github.com/imbonda/vmm-bot/internal/risk"
+	exchangeErrors "github.com/imbonda/vmm-bot/pkg/exchanges/errors"
+	"github.com/imbonda/vmm-bot/pkg/exchanges/resilience"
 	"github.com/imbonda/vmm-bot/pkg/models"
 	"github.com/imbonda/vmm-bot/pkg/utils"
 )
@@ -17,20 +25,32 @@ type Trader struct {
 	exchangeClient    interfaces.ExchangeClient
 	priceOracleClient interfaces.ExchangeClient
 	symbol            string
+	pair              models.Pair
 	oracleSymbol      string
-	candleHeight      float64
-	spreadMarginLower float64
-	spreadMarginUpper float64
-	tradeQtyMin       float64
-	tradeQtyMax       float64
-	priceDecimals     int
-	amountDecimals    int
-	logger            log.Logger
+	// rules are reloaded when the exchange rejects an order by them, defaultTickSize and defaultLotStep stand for
+	// the ones the exchange doesn't report.
+	rules           atomic.Pointer[symbolRules]
+	defaultTickSize float64
+	defaultLotStep  float64
+	orderType       models.OrderType
+	timeInForce     models.TimeInForce
+	inventory       *inventoryManager
+	oracleGuard     *oracleGuard
+	// session and iterations make the client order ids, unique across restarts and iterations.
+	session    string
+	iterations atomic.Int64
+	logger     log.Logger
+	// params are read once per iteration, so an update is applied between iterations.
+	params         atomic.Pointer[models.TradeParams]
+	previousParams *models.TradeParams
+	paramsMu       sync.Mutex
 }
 
 type NewTraderInput struct {
 	ExchangeClient    interfaces.ExchangeClient
 	PriceOracleClient interfaces.ExchangeClient
+	// Pair is the canonical base and quote of the symbol, its balances are looked up by them.
+	Pair              models.Pair
 	Symbol            string
 	OracleSymbol      string
 	CandleHeight      float64
@@ -38,32 +58,100 @@ type NewTraderInput struct {
 	SpreadMarginUpper float64
 	TradeAmountMin    float64
 	TradeAmountMax    float64
-	PriceDecimals     int
-	AmountDecimals    int
-	Logger            log.Logger
+	// PriceDecimals and AmountDecimals are the fallback for a tick size or lot step the exchange doesn't report.
+	PriceDecimals  int
+	AmountDecimals int
+	// OrderType and TimeInForce of the placed orders, a GTC limit order when empty. Market orders aren't supported, as
+	// the orders are placed at the picked price.
+	OrderType   models.OrderType
+	TimeInForce models.TimeInForce
+	// Inventory enables skewing the quotes toward a target inventory ratio when set.
+	Inventory *InventoryConfig
+	// OracleGuard enables skipping the iterations whose venue price diverged from the oracle price when set.
+	OracleGuard *OracleGuardConfig
+	Logger      log.Logger
 }
 
 type tradeParams struct {
 	shouldTrade bool
 	price       string
-	qty         string
+	// qty is the unskewed qty, every side's qty is derived from it and an empty side qty skips the side.
+	qty       float64
+	qtyMin    float64
+	sellQty   string
+	buyQty    string
+	pricing   *models.PriceDecision
+	inventory *models.InventorySkew
+	oracle    *models.OracleCheck
+	balances  []*models.Balance
+	rules     *symbolRules
 }
 
+var (
+	ErrUnexpectedPriceRange = fmt.Errorf("unexpected price range")
+	ErrSymbolHalted         = exchangeErrors.ErrSymbolHalted
+)
+
 func NewTrader(ctx context.Context, input *NewTraderInput) (*Trader, error) {
-	return &Trader{
+	if input.OrderType == models.OrderTypeMarket {
+		return nil, fmt.Errorf("invalid order type: %s, the trader places priced orders", input.OrderType)
+	}
+	orderType, timeInForce := (&models.Order{Type: input.OrderType, TimeInForce: input.TimeInForce}).Kind()
+	if err := models.ValidateOrderKind(orderType, timeInForce); err != nil {
+		return nil, err
+	}
+	var inventory *inventoryManager
+	if input.Inventory != nil {
+		var err error
+		if inventory, err = newInventoryManager(input.Inventory); err != nil {
+			return nil, err
+		}
+	}
+	var guard *oracleGuard
+	if input.OracleGuard != nil {
+		var err error
+		if guard, err = newOracleGuard(input.OracleGuard, input.Logger); err != nil {
+			return nil, err
+		}
+	}
+	t := &Trader{
 		exchangeClient:    input.ExchangeClient,
 		priceOracleClient: input.PriceOracleClient,
 		symbol:            input.Symbol,
+		pair:              input.Pair,
 		oracleSymbol:      input.OracleSymbol,
-		candleHeight:      input.CandleHeight,
-		spreadMarginLower: input.SpreadMarginLower,
-		spreadMarginUpper: input.SpreadMarginUpper,
-		tradeQtyMin:       input.TradeAmountMin,
-		tradeQtyMax:       input.TradeAmountMax,
-		priceDecimals:     input.PriceDecimals,
-		amountDecimals:    input.AmountDecimals,
+		defaultTickSize:   utils.DecimalsStep(input.PriceDecimals),
+		defaultLotStep:    utils.DecimalsStep(input.AmountDecimals),
+		orderType:         orderType,
+		timeInForce:       timeInForce,
+		inventory:         inventory,
+		oracleGuard:       guard,
+		session:           strconv.FormatInt(time.Now().UnixNano(), 36),
 		logger:            input.Logger,
-	}, nil
+	}
+	rules, err := t.loadRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if rules.halted {
+		return nil, fmt.Errorf("%w. symbol: %s", ErrSymbolHalted, input.Symbol)
+	}
+	if input.TradeAmountMin < rules.minQty {
+		level.Warn(input.Logger).Log(
+			"msg", "min trade amount is below the exchange min qty, smaller orders will be skipped",
+			"symbol", input.Symbol,
+			"tradeAmountMin", input.TradeAmountMin,
+			"minQty", rules.minQty,
+		)
+	}
+	t.params.Store(&models.TradeParams{
+		CandleHeight:      input.CandleHeight,
+		SpreadMarginLower: input.SpreadMarginLower,
+		SpreadMarginUpper: input.SpreadMarginUpper,
+		TradeAmountMin:    input.TradeAmountMin,
+		TradeAmountMax:    input.TradeAmountMax,
+	})
+	return t, nil
 }
 
 func (t *Trader) DoIteration(ctx context.Context) error {
@@ -78,54 +166,178 @@ func (t *Trader) DoIteration(ctx context.Context) error {
 }
 
 func (t *Trader) TradeOnce(ctx context.Context) (*models.TradeOnceOutput, error) {
-	err := t.cancelAllOrders(ctx)
+	iteration := t.iterations.Add(1)
+	err := t.CancelAllOrders(ctx)
 	if err != nil {
 		return nil, err
 	}
-	params, err := t.getTradeParams(ctx)
+	// A halted symbol is checked again on every iteration, until it trades again.
+	if t.rules.Load().halted {
+		rules, err := t.loadRules(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if rules.halted {
+			return nil, fmt.Errorf("%w. symbol: %s", ErrSymbolHalted, t.symbol)
+		}
+	}
+	params, err := t.getTradeParams(ctx, t.params.Load())
+	if err != nil {
+		if params != nil {
+			return &models.TradeOnceOutput{Pricing: params.pricing, Inventory: params.inventory, Oracle: params.oracle}, err
+		}
+		return nil, err
+	}
+	output := &models.TradeOnceOutput{Pricing: params.pricing, Inventory: params.inventory, Oracle: params.oracle}
+	output.Funding, err = t.fundTrade(params)
+	if err != nil {
+		return output, err
+	}
+	// A side the exchange can't fund is skipped, while the other side is still placed.
+	var skipErr error
+	if params.sellQty != "" {
+		sellOrder, err := t.placeOrder(ctx, iteration, params.price, params.sellQty, models.Sell)
+		switch {
+		case err == nil:
+			output.Orders = append(output.Orders, sellOrder)
+		case t.skipSide(ctx, err):
+			skipErr = err
+		default:
+			return output, err
+		}
+	}
+	if params.buyQty != "" {
+		buyOrder, err := t.placeOrder(ctx, iteration, params.price, params.buyQty, models.Buy)
+		switch {
+		case err == nil:
+			output.Orders = append(output.Orders, buyOrder)
+		case t.skipSide(ctx, err):
+			skipErr = err
+		default:
+			return output, err
+		}
+	}
+	if len(output.Orders) == 0 && skipErr != nil {
+		return output, skipErr
+	}
+	return output, nil
+}
+
+// skipSide reacts to the class of a failed order, and reports whether the iteration goes on without its side.
+// The iterations fail on every other class, as retrying them within the iteration is left to the exchange clients.
+func (t *Trader) skipSide(ctx context.Context, err error) bool {
+	switch exchangeErrors.Classify(err) {
+	case exchangeErrors.ErrInsufficientBalance:
+		level.Warn(t.logger).Log("msg", "skipping side the exchange can't fund", "symbol", t.symbol, "err", err)
+		return true
+	case exchangeErrors.ErrInvalidPrecision, exchangeErrors.ErrSymbolHalted:
+		// The rules of the symbol changed since they were loaded, the next iterations follow the new ones.
+		if _, reloadErr := t.loadRules(ctx); reloadErr != nil {
+			level.Warn(t.logger).Log("msg", "failed to reload symbol rules", "symbol", t.symbol, "err", reloadErr)
+		}
+	case exchangeErrors.ErrAuth:
+		level.Error(t.logger).Log("msg", "exchange rejected the credentials", "symbol", t.symbol, "err", err)
+	}
+	return false
+}
+
+// GetFills returns the fills of the traded symbol since the given time, with their aggregated volume and fees.
+func (t *Trader) GetFills(ctx context.Context, since time.Time) (*models.FillsOutput, error) {
+	fills, err := t.exchangeClient.GetFills(ctx, t.symbol, since)
 	if err != nil {
 		return nil, err
 	}
-	err = t.placeOrder(ctx, params, models.Sell)
+	summary, err := models.SummarizeFills(fills)
 	if err != nil {
 		return nil, err
 	}
-	err = t.placeOrder(ctx, params, models.Buy)
-	return &models.TradeOnceOutput{}, err
+	return &models.FillsOutput{
+		Since:   since,
+		Fills:   fills,
+		Summary: summary,
+	}, nil
 }
 
-func (t *Trader) cancelAllOrders(ctx context.Context) error {
+// CancelAllOrders cancels the open orders of the traded symbol.
+func (t *Trader) CancelAllOrders(ctx context.Context) error {
 	return t.exchangeClient.CancelAllOrders(ctx, t.symbol)
 }
 
-func (t *Trader) placeOrder(ctx context.Context, orderInput *tradeParams, action models.OrderAction) error {
-	err := t.exchangeClient.PlaceOrder(ctx, &models.Order{
-		Symbol: t.symbol,
-		Price:  orderInput.price,
-		Qty:    orderInput.qty,
-		Action: action,
+// GetOpenOrders returns the open orders of the traded symbol.
+func (t *Trader) GetOpenOrders(ctx context.Context) ([]*models.OrderRecord, error) {
+	return t.exchangeClient.ListOpenOrders(ctx, t.symbol)
+}
+
+func (t *Trader) placeOrder(ctx context.Context, iteration int64, price string, qty string, action models.OrderAction) (*models.OrderRecord, error) {
+	clientOrderID := t.clientOrderID(iteration, action)
+	record, err := t.exchangeClient.PlaceOrder(ctx, &models.Order{
+		Symbol:        t.symbol,
+		Price:         price,
+		Qty:           qty,
+		Action:        action,
+		Type:          t.orderType,
+		TimeInForce:   t.timeInForce,
+		ClientOrderID: clientOrderID,
 	})
 	if err != nil {
+		record = t.recoverOrder(ctx, clientOrderID, err)
+	}
+	if record == nil {
 		level.Warn(t.logger).Log(
 			"msg", "failed order",
 			"symbol", t.symbol,
 			"action", action,
-			"price", orderInput.price,
-			"qty", orderInput.qty,
+			"price", price,
+			"qty", qty,
+			"clientOrderId", clientOrderID,
 		)
-		return err
+		return nil, err
 	}
 	level.Info(t.logger).Log(
 		"msg", "successful order",
 		"symbol", t.symbol,
 		"action", action,
-		"price", orderInput.price,
-		"qty", orderInput.qty,
+		"price", price,
+		"qty", qty,
+		"orderId", record.OrderID,
+		"clientOrderId", clientOrderID,
+		"status", record.Status,
+		"filledQty", record.FilledQty,
 	)
-	return nil
+	return record, nil
+}
+
+// clientOrderID is the same for every attempt of placing the side in the iteration, so that the exchange rejects
+// a retried order which was already placed. It's kept within 36 chars of [a-z0-9-], allowed by all exchanges.
+func (t *Trader) clientOrderID(iteration int64, action models.OrderAction) string {
+	side := "b"
+	if action == models.Sell {
+		side = "s"
+	}
+	return fmt.Sprintf("vmm-%s-%d-%s", t.session, iteration, side)
 }
 
-func (t *Trader) getTradeParams(ctx context.Context) (*tradeParams, error) {
+// recoverOrder looks up an order whose placement failed, in case the exchange placed it anyway, like when the response
+// was lost or a retry was rejected as a duplicate. Only transport failures and unmapped exchange errors are looked up,
+// the local validation and risk errors, the classified rejections and an open circuit never reached the exchange or
+// surely didn't place the order.
+func (t *Trader) recoverOrder(ctx context.Context, clientOrderID string, err error) *models.OrderRecord {
+	if ctx.Err() != nil || errors.Is(err, resilience.ErrCircuitOpen) || errors.Is(err, risk.ErrCheckFailed) {
+		return nil
+	}
+	if class := exchangeErrors.Classify(err); class != exchangeErrors.ErrTransient && class != exchangeErrors.ErrRejected {
+		return nil
+	}
+	record, lookupErr := t.exchangeClient.GetOrderByClientID(ctx, t.symbol, clientOrderID)
+	if lookupErr != nil {
+		level.Debug(t.logger).Log("msg", "failed order wasn't placed", "symbol", t.symbol, "clientOrderId", clientOrderID, "err", lookupErr)
+		return nil
+	}
+	level.Info(t.logger).Log("msg", "recovered order placed by a failed request", "symbol", t.symbol, "clientOrderId", clientOrderID, "orderId", record.OrderID, "err", err)
+	return record
+}
+
+func (t *Trader) getTradeParams(ctx context.Context, cfg *models.TradeParams) (*tradeParams, error) {
 	ticker, err := t.exchangeClient.GetLastTicker(ctx, t.symbol)
 	if err != nil {
 		return nil, err
@@ -146,81 +358,157 @@ func (t *Trader) getTradeParams(ctx context.Context) (*tradeParams, error) {
 	if err != nil {
 		return nil, err
 	}
-	price, err := t.getRandPriceInSpread(ctx, spread, lastPrice, oraclePrice)
+	var oracleCheck *models.OracleCheck
+	if t.oracleGuard != nil {
+		oracleCheck = t.oracleGuard.check(ticker, oracleTicker, lastPrice, oraclePrice)
+		if oracleCheck.SkipReason != "" {
+			return &tradeParams{oracle: oracleCheck}, fmt.Errorf(
+				"%w. reason: %s, deviation: %f, price: %f, oraclePrice: %f, tickerAge: %s, oracleTickerAge: %s",
+				ErrOracleCheckFailed,
+				oracleCheck.SkipReason,
+				oracleCheck.Deviation,
+				lastPrice,
+				oraclePrice,
+				oracleCheck.TickerAge,
+				oracleCheck.OracleTickerAge,
+			)
+		}
+	}
+	balances, err := t.exchangeClient.GetBalances(ctx)
 	if err != nil {
 		return nil, err
 	}
-	qty := t.getRandQty(ctx)
-	return &tradeParams{
+	var inventory *models.InventorySkew
+	if t.inventory != nil {
+		if inventory, err = t.inventory.skew(balances, t.pair.Base, t.pair.Quote, lastPrice); err != nil {
+			return nil, err
+		}
+	}
+	pricing, err := t.getRandPriceInSpread(ctx, cfg, spread, lastPrice, oraclePrice, inventory)
+	if err != nil {
+		return &tradeParams{pricing: pricing, inventory: inventory, oracle: oracleCheck}, err
+	}
+	rules := t.rules.Load()
+	price := utils.RoundToStep(pricing.Price, rules.tickSize)
+	qty := t.getRandQty(ctx, cfg)
+	params := &tradeParams{
 		shouldTrade: true,
-		price:       utils.FormatFloatToString(price, t.priceDecimals),
-		qty:         utils.FormatFloatToString(qty, t.amountDecimals),
-	}, nil
+		price:       utils.FormatFloatToString(price, rules.priceDecimals),
+		qty:         qty,
+		qtyMin:      cfg.TradeAmountMin,
+		pricing:     pricing,
+		inventory:   inventory,
+		oracle:      oracleCheck,
+		balances:    balances,
+		rules:       rules,
+	}
+	sellQty, buyQty := qty, qty
+	var sellStopped, buyStopped bool
+	if inventory != nil {
+		sellQty, sellStopped = qty*inventory.SellQtyFactor, inventory.SellStopped
+		buyQty, buyStopped = qty*inventory.BuyQtyFactor, inventory.BuyStopped
+	}
+	params.sellQty = t.formatSideQty(rules, sellQty, price, sellStopped)
+	params.buyQty = t.formatSideQty(rules, buyQty, price, buyStopped)
+	return params, nil
 }
 
-func (t *Trader) getRandPriceInSpread(_ context.Context, spread *models.Spread, lastPrice float64, oraclePrice float64) (float64, error) {
+// formatSideQty floors the qty to the lot step, returning an empty qty for stopped sides
+// and sides below the min qty or min notional of the exchange.
+func (t *Trader) formatSideQty(rules *symbolRules, qty float64, price float64, stopped bool) string {
+	qty = utils.FloorToStep(qty, rules.lotStep)
+	if stopped || qty <= 0 {
+		return ""
+	}
+	if qty < rules.minQty || qty*price < rules.minNotional {
+		level.Debug(t.logger).Log(
+			"msg", "skipping side below the exchange minimums",
+			"symbol", t.symbol,
+			"qty", qty,
+			"price", price,
+			"minQty", rules.minQty,
+			"minNotional", rules.minNotional,
+		)
+		return ""
+	}
+	return utils.FormatFloatToString(qty, rules.amountDecimals)
+}
+
+func (t *Trader) getRandPriceInSpread(_ context.Context, cfg *models.TradeParams, spread *models.Spread, lastPrice float64, oraclePrice float64, inventory *models.InventorySkew) (*models.PriceDecision, error) {
 	// Oracle candle height range
-	oracleLowerLimit := oraclePrice * (1 - t.candleHeight/2)
-	oracleUpperLimit := oraclePrice * (1 + t.candleHeight/2)
+	oracleLowerLimit := oraclePrice * (1 - cfg.CandleHeight/2)
+	oracleUpperLimit := oraclePrice * (1 + cfg.CandleHeight/2)
 
 	// Candle height range
-	lowerLimit := lastPrice * (1 - t.candleHeight/2)
-	upperLimit := lastPrice * (1 + t.candleHeight/2)
+	lowerLimit := lastPrice * (1 - cfg.CandleHeight/2)
+	upperLimit := lastPrice * (1 + cfg.CandleHeight/2)
+
+	decision := &models.PriceDecision{
+		LastPrice:   lastPrice,
+		OraclePrice: oraclePrice,
+		Ask:         spread.Ask,
+		Bid:         spread.Bid,
+		Crossed:     spread.Diff() < 0,
+	}
 
 	var margin *models.Spread
 	if spread.Diff() < 0 {
 		clone := spread.Clone()
-		clone.Ask = clone.Bid * (1 + t.candleHeight)
-		margin = clone.MarginSpread(t.spreadMarginLower, t.spreadMarginUpper)
+		clone.Ask = clone.Bid * (1 + cfg.CandleHeight)
+		margin = clone.MarginSpread(cfg.SpreadMarginLower, cfg.SpreadMarginUpper)
 	} else {
-		margin = spread.MarginSpread(t.spreadMarginLower, t.spreadMarginUpper)
+		margin = spread.MarginSpread(cfg.SpreadMarginLower, cfg.SpreadMarginUpper)
 	}
 
 	var min, max float64
+	var branch models.PriceBranch
 
 	switch {
 	case margin.Contains(oracleLowerLimit, oracleUpperLimit):
-		min, max = oracleLowerLimit, oracleUpperLimit
+		min, max, branch = oracleLowerLimit, oracleUpperLimit, models.BranchOracleRangeInMargin
 	case margin.Contains(oracleLowerLimit):
-		min, max = oracleLowerLimit, margin.Ask
+		min, max, branch = oracleLowerLimit, margin.Ask, models.BranchOracleLowerInMargin
 	case margin.Contains(oracleUpperLimit):
-		min, max = margin.Bid, oracleUpperLimit
+		min, max, branch = margin.Bid, oracleUpperLimit, models.BranchOracleUpperInMargin
 
 	case margin.Contains(lowerLimit, upperLimit):
-		min, max = lowerLimit, upperLimit
+		min, max, branch = lowerLimit, upperLimit, models.BranchCandleRangeInMargin
 	case margin.Contains(lowerLimit):
-		min, max = lowerLimit, margin.Ask
+		min, max, branch = lowerLimit, margin.Ask, models.BranchCandleLowerInMargin
 	case margin.Contains(upperLimit):
-		min, max = margin.Bid, upperLimit
+		min, max, branch = margin.Bid, upperLimit, models.BranchCandleUpperInMargin
 
 	// In case the margins are too big consider the spread itself ignoring margins.
 
 	case spread.Contains(oracleLowerLimit, oracleUpperLimit):
-		min, max = oracleLowerLimit, oracleUpperLimit
+		min, max, branch = oracleLowerLimit, oracleUpperLimit, models.BranchOracleRangeInSpread
 	case spread.Contains(oracleLowerLimit):
-		min, max = oracleLowerLimit, spread.Ask
+		min, max, branch = oracleLowerLimit, spread.Ask, models.BranchOracleLowerInSpread
 	case spread.Contains(oracleUpperLimit):
-		min, max = spread.Bid, oracleUpperLimit
+		min, max, branch = spread.Bid, oracleUpperLimit, models.BranchOracleUpperInSpread
 
 	case spread.Contains(lowerLimit, upperLimit):
-		min, max = lowerLimit, upperLimit
+		min, max, branch = lowerLimit, upperLimit, models.BranchCandleRangeInSpread
 	case spread.Contains(lowerLimit):
-		min, max = lowerLimit, spread.Ask
+		min, max, branch = lowerLimit, spread.Ask, models.BranchCandleLowerInSpread
 	case spread.Contains(upperLimit):
-		min, max = spread.Bid, upperLimit
+		min, max, branch = spread.Bid, upperLimit, models.BranchCandleUpperInSpread
 
 	case spread.Above(oraclePrice):
-		min, max = spread.Bid, spread.Bid*(1+t.candleHeight)
+		min, max, branch = spread.Bid, spread.Bid*(1+cfg.CandleHeight), models.BranchSpreadAboveOracle
 	case spread.Below(oraclePrice):
-		min, max = spread.Ask*(1-t.candleHeight), spread.Ask
+		min, max, branch = spread.Ask*(1-cfg.CandleHeight), spread.Ask, models.BranchSpreadBelowOracle
 
 	default:
-		min, max = spread.Bid, spread.Ask
+		min, max, branch = spread.Bid, spread.Ask, models.BranchSpread
 	}
 
+	decision.Branch, decision.Min, decision.Max = branch, min, max
+
 	if min > max {
-		return 0, fmt.Errorf(
-			"unexpected price range. min: %f, max: %f, oraclePrice: %f, price: %f, ask: %f, bid: %f",
+		return decision, fmt.Errorf(
+			"%w. min: %f, max: %f, oraclePrice: %f, price: %f, ask: %f, bid: %f",
+			ErrUnexpectedPriceRange,
 			min,
 			max,
 			oraclePrice,
@@ -230,9 +518,13 @@ func (t *Trader) getRandPriceInSpread(_ context.Context, spread *models.Spread,
 		)
 	}
 
-	return utils.RandInRange(min, max), nil
+	if inventory != nil {
+		min, max = t.inventory.skewRange(inventory, min, max)
+	}
+	decision.Price = utils.RandInRange(min, max)
+	return decision, nil
 }
 
-func (t *Trader) getRandQty(_ context.Context) float64 {
-	return utils.RandInRange(t.tradeQtyMin, t.tradeQtyMax)
+func (t *Trader) getRandQty(_ context.Context, cfg *models.TradeParams) float64 {
+	return utils.RandInRange(cfg.TradeAmountMin, cfg.TradeAmountMax)
 }
@@ -0,0 +1,144 @@
+package trader
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/imbonda/vmm-bot/cmd/interfaces/mocks"
+	"github.com/imbonda/vmm-bot/internal/risk"
+	exchangeErrors "github.com/imbonda/vmm-bot/pkg/exchanges/errors"
+	"github.com/imbonda/vmm-bot/pkg/exchanges/resilience"
+	"github.com/imbonda/vmm-bot/pkg/models"
+)
+
+const symbol = "BTCUSDT"
+
+var pair = models.Pair{Base: "BTC", Quote: "USDT"}
+
+// newTestTrader returns a trader of a market quoted at 99.9-100.1, trading within the spread.
+func newTestTrader(t *testing.T, info *models.SymbolInfo) (*Trader, *mocks.ExchangeClient) {
+	t.Helper()
+	client := mocks.NewExchangeClient(t)
+	client.On("GetSymbolInfo", mock.Anything, symbol).Return(info, nil).Maybe()
+	client.On("CancelAllOrders", mock.Anything, symbol).Return(nil).Maybe()
+	client.On("GetLastTicker", mock.Anything, symbol).Return(
+		&models.Ticker{Symbol: symbol, LastPrice: "100", BestAsk: "100.1", BestBid: "99.9"}, nil).Maybe()
+	client.On("GetBalances", mock.Anything).Return([]*models.Balance{
+		{Asset: "BTC", Free: "1000", Locked: "0"},
+		{Asset: "USDT", Free: "100000", Locked: "0"},
+	}, nil).Maybe()
+	trader, err := NewTrader(context.Background(), &NewTraderInput{
+		ExchangeClient:    client,
+		PriceOracleClient: client,
+		Pair:              pair,
+		Symbol:            symbol,
+		OracleSymbol:      symbol,
+		CandleHeight:      0.01,
+		SpreadMarginUpper: 1,
+		TradeAmountMin:    1,
+		TradeAmountMax:    2,
+		PriceDecimals:     2,
+		AmountDecimals:    3,
+		Logger:            log.NewNopLogger(),
+	})
+	require.NoError(t, err)
+	return trader, client
+}
+
+func TestTradeOnceRoundsToTheSymbolRules(t *testing.T) {
+	trader, client := newTestTrader(t, &models.SymbolInfo{
+		Symbol: symbol, Status: models.SymbolStatusTrading, TickSize: "0.05", LotStep: "0.25",
+	})
+	var placed []*models.Order
+	client.On("PlaceOrder", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		placed = append(placed, args.Get(1).(*models.Order))
+	}).Return(&models.OrderRecord{OrderID: "1"}, nil)
+
+	for range 20 {
+		_, err := trader.TradeOnce(context.Background())
+		require.NoError(t, err)
+	}
+	require.NotEmpty(t, placed)
+	for _, order := range placed {
+		assert.True(t, isMultipleOf(t, order.Price, 0.05), order.Price)
+		assert.True(t, isMultipleOf(t, order.Qty, 0.25), order.Qty)
+		assert.NotEmpty(t, order.ClientOrderID)
+	}
+}
+
+func TestTradeOnceReturnsTheOutputOfAFailedSell(t *testing.T) {
+	trader, client := newTestTrader(t, &models.SymbolInfo{Symbol: symbol, Status: models.SymbolStatusTrading})
+	sellErr := fmt.Errorf("%w: trading is halted", risk.ErrTradingHalted)
+	client.On("PlaceOrder", mock.Anything, mock.MatchedBy(func(order *models.Order) bool {
+		return order.Action == models.Sell
+	})).Return(nil, sellErr)
+
+	output, err := trader.TradeOnce(context.Background())
+	assert.ErrorIs(t, err, risk.ErrTradingHalted)
+	require.NotNil(t, output)
+	assert.NotNil(t, output.Pricing)
+	assert.NotNil(t, output.Funding)
+	assert.Empty(t, output.Orders)
+}
+
+func TestTradeOnceSkipsTheSideTheExchangeCantFund(t *testing.T) {
+	trader, client := newTestTrader(t, &models.SymbolInfo{Symbol: symbol, Status: models.SymbolStatusTrading})
+	client.On("PlaceOrder", mock.Anything, mock.MatchedBy(func(order *models.Order) bool {
+		return order.Action == models.Sell
+	})).Return(nil, fmt.Errorf("%w: no base", exchangeErrors.ErrInsufficientBalance))
+	client.On("PlaceOrder", mock.Anything, mock.Anything).Return(&models.OrderRecord{OrderID: "2", Action: models.Buy}, nil)
+
+	output, err := trader.TradeOnce(context.Background())
+	require.NoError(t, err)
+	require.Len(t, output.Orders, 1)
+	assert.Equal(t, models.Buy, output.Orders[0].Action)
+}
+
+func TestRecoverOrder(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		lookedUp bool
+	}{
+		{name: "transport error", err: fmt.Errorf("%w: timeout", exchangeErrors.ErrTransient), lookedUp: true},
+		{name: "unmapped exchange error", err: exchangeErrors.Codes{}.FromCode("test", "placeOrder", 1, "duplicate"), lookedUp: true},
+		{name: "local validation error", err: models.ValidateOrderKind(models.OrderTypeMarket, models.TimeInForceGTC)},
+		{name: "trading halted", err: fmt.Errorf("%w: breach", risk.ErrTradingHalted)},
+		{name: "risk limit breached", err: fmt.Errorf("%w: breach", risk.ErrRiskLimitBreached)},
+		{name: "risk check failed", err: fmt.Errorf("%w: %w", risk.ErrCheckFailed, exchangeErrors.ErrTransient)},
+		{name: "rejection", err: fmt.Errorf("%w: no funds", exchangeErrors.ErrInsufficientBalance)},
+		{name: "rate limited", err: exchangeErrors.ErrRateLimited},
+		{name: "open circuit", err: resilience.ErrCircuitOpen},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Error(t, test.err)
+			trader, client := newTestTrader(t, &models.SymbolInfo{Symbol: symbol, Status: models.SymbolStatusTrading})
+			if test.lookedUp {
+				client.On("GetOrderByClientID", mock.Anything, symbol, "client-1").Return(&models.OrderRecord{OrderID: "1"}, nil).Once()
+			}
+			record := trader.recoverOrder(context.Background(), "client-1", test.err)
+			if test.lookedUp {
+				require.NotNil(t, record)
+				assert.Equal(t, "1", record.OrderID)
+				return
+			}
+			assert.Nil(t, record)
+		})
+	}
+}
+
+func isMultipleOf(t *testing.T, value string, step float64) bool {
+	t.Helper()
+	parsed, err := parseSideQty(value)
+	require.NoError(t, err)
+	ratio := parsed / step
+	return math.Abs(ratio-math.Round(ratio)) < 1e-9
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/go-kit/log"
@@ -12,6 +13,9 @@ import (
 
 	"github.com/imbonda/vmm-bot/pkg/exchanges/biconomy/hooks"
 	biconomyModels "github.com/imbonda/vmm-bot/pkg/exchanges/biconomy/models"
+	exchangeErrors "github.com/imbonda/vmm-bot/pkg/exchanges/errors"
+	"github.com/imbonda/vmm-bot/pkg/exchanges/ratelimit"
+	"github.com/imbonda/vmm-bot/pkg/exchanges/resilience"
 	"github.com/imbonda/vmm-bot/pkg/models"
 	"github.com/imbonda/vmm-bot/pkg/utils"
 )
@@ -37,6 +41,103 @@ func resolveSide(action models.OrderAction) tradingSide {
 	return ASK
 }
 
+func resolveAction(side int) models.OrderAction {
+	if utils.FormatIntToString(side) == BID {
+		return models.Buy
+	}
+	return models.Sell
+}
+
+func resolveAvgPrice(dealMoney, dealStock string) string {
+	money, err := utils.ParseFloat(dealMoney)
+	if err != nil {
+		return "0"
+	}
+	stock, err := utils.ParseFloat(dealStock)
+	if err != nil || stock == 0 {
+		return "0"
+	}
+	return utils.FormatFloatToString(money/stock, -1)
+}
+
+// Biconomy doesn't report an explicit status, it's derived from the executed amount.
+func resolveOrderStatus(amount, dealStock string, finished bool) models.OrderStatus {
+	total, err := utils.ParseFloat(amount)
+	if err != nil {
+		return models.OrderStatusUnknown
+	}
+	// The deal amount is missing for orders which weren't matched yet.
+	filled, _ := utils.ParseFloat(dealStock)
+	switch {
+	case total > 0 && filled >= total:
+		return models.OrderStatusFilled
+	case finished:
+		return models.OrderStatusCancelled
+	case filled > 0:
+		return models.OrderStatusPartiallyFilled
+	default:
+		return models.OrderStatusNew
+	}
+}
+
+func toPendingOrderRecord(order *biconomyModels.RawPendingOrder) *models.OrderRecord {
+	return &models.OrderRecord{
+		Symbol:    order.Symbol,
+		OrderID:   utils.FormatIntToString(order.OrderId),
+		Action:    resolveAction(order.Side),
+		Price:     order.Price,
+		Qty:       order.Amount,
+		FilledQty: order.DealStock,
+		AvgPrice:  resolveAvgPrice(order.DealMoney, order.DealStock),
+		Status:    resolveOrderStatus(order.Amount, order.DealStock, false),
+	}
+}
+
+func toFinishedOrderRecord(order *biconomyModels.RawFinishedOrder) *models.OrderRecord {
+	return &models.OrderRecord{
+		Symbol:    order.Symbol,
+		OrderID:   utils.FormatIntToString(order.OrderId),
+		Action:    resolveAction(order.Side),
+		Price:     order.Price,
+		Qty:       order.Amount,
+		FilledQty: order.DealStock,
+		AvgPrice:  resolveAvgPrice(order.DealMoney, order.DealStock),
+		Status:    resolveOrderStatus(order.Amount, order.DealStock, true),
+	}
+}
+
+func toFill(symbol string, orderID, side int, dealStock, dealMoney, dealFee string, executedAt float64) *models.Fill {
+	filled, _ := utils.ParseFloat(dealStock)
+	if filled <= 0 {
+		return nil
+	}
+	action := resolveAction(side)
+	return &models.Fill{
+		Symbol:   symbol,
+		OrderID:  utils.FormatIntToString(orderID),
+		Action:   action,
+		Price:    resolveAvgPrice(dealMoney, dealStock),
+		Qty:      dealStock,
+		Fee:      dealFee,
+		FeeAsset: resolveFeeAsset(symbol, action),
+		Time:     time.UnixMilli(int64(executedAt * 1000)),
+	}
+}
+
+// Biconomy charges the fee in the received currency, i.e. base for buys and quote for sells.
+func resolveFeeAsset(symbol string, action models.OrderAction) string {
+	base, quote, _ := strings.Cut(symbol, "_")
+	if action == models.Buy {
+		return base
+	}
+	return quote
+}
+
+const (
+	fillsPageLimit = 100
+	maxFillsPages  = 10
+)
+
 type Client struct {
 	v1     *utils.Endpoint
 	v2     *utils.Endpoint
@@ -46,13 +147,22 @@ type Client struct {
 }
 
 type NewClientInput struct {
+	BaseURL    string
 	APIKey     string
 	APISecret  string
 	APITimeout time.Duration
+	// RateLimiter holds every request until its rate limit budget allows it, nil doesn't limit them.
+	RateLimiter *ratelimit.Limiter
+	// Resilience retries the failed requests and breaks the circuit on repeated failures, nil sends every request once.
+	Resilience *resilience.Policy
 	Logger     log.Logger
 }
 
 func NewClient(ctx context.Context, input *NewClientInput) (*Client, error) {
+	baseURL := input.BaseURL
+	if baseURL == "" {
+		baseURL = BaseAPIURL
+	}
 	v1 := utils.NewEndpoint(APIV1)
 	v2 := utils.NewEndpoint(APIV2)
 	creds := &utils.Credentials{
@@ -60,12 +170,20 @@ func NewClient(ctx context.Context, input *NewClientInput) (*Client, error) {
 		APISecret: input.APISecret,
 	}
 	client := resty.New().
-		SetBaseURL(BaseAPIURL).
+		SetBaseURL(baseURL).
 		SetHeader("Content-Type", "application/x-www-form-urlencoded").
 		SetHeader("X-SITE-ID", "127").
 		SetTimeout(input.APITimeout)
 	// Add credentials to every request.
 	client.OnBeforeRequest(hooks.GetSigAuthBeforeRequestHook(client, creds))
+	httpClient := client.GetClient()
+	if input.RateLimiter != nil {
+		httpClient.Transport = input.RateLimiter.Transport(httpClient.Transport)
+	}
+	// Every retry takes its own rate limit budget.
+	if input.Resilience != nil {
+		httpClient.Transport = input.Resilience.Transport(httpClient.Transport)
+	}
 	return &Client{
 		v1:     v1,
 		v2:     v2,
@@ -78,6 +196,7 @@ func NewClient(ctx context.Context, input *NewClientInput) (*Client, error) {
 func (api *Client) GetOrderBook(ctx context.Context, symbol string) (*models.OrderBook, error) {
 	var res biconomyModels.RawOrderBook
 	resp, err := api.client.R().
+		SetContext(ctx).
 		SetResult(&res).
 		SetQueryParam("symbol", symbol).
 		Get(api.v1.Join("depth"))
@@ -85,7 +204,7 @@ func (api *Client) GetOrderBook(ctx context.Context, symbol string) (*models.Ord
 		return nil, err
 	}
 	if resp.IsError() {
-		return nil, fmt.Errorf("biconomy orderbook request failed: %s", resp.Status())
+		return nil, exchangeErrors.FromStatus(exchangeName, "orderbook", resp.StatusCode())
 	}
 	return &models.OrderBook{
 		Symbol: symbol,
@@ -97,13 +216,14 @@ func (api *Client) GetOrderBook(ctx context.Context, symbol string) (*models.Ord
 func (api *Client) GetLastTicker(ctx context.Context, symbol string) (*models.Ticker, error) {
 	var res biconomyModels.RawTickersResult
 	resp, err := api.client.R().
+		SetContext(ctx).
 		SetResult(&res).
 		Get(api.v1.Join("tickers"))
 	if err != nil {
 		return nil, err
 	}
 	if resp.IsError() {
-		return nil, fmt.Errorf("biconomy tickers request failed: %s", resp.Status())
+		return nil, exchangeErrors.FromStatus(exchangeName, "tickers", resp.StatusCode())
 	}
 	ticker, err := res.LastTicker(symbol)
 	if err != nil {
@@ -114,12 +234,53 @@ func (api *Client) GetLastTicker(ctx context.Context, symbol string) (*models.Ti
 		LastPrice: ticker.LastPrice,
 		BestAsk:   ticker.Ask,
 		BestBid:   ticker.Bid,
+		Volume:    ticker.Volume24h,
 	}, nil
 }
 
-func (api *Client) PlaceOrder(ctx context.Context, order *models.Order) error {
+// GetSymbolInfo derives the tick size and lot step from the reported precisions, biconomy doesn't report order minimums.
+func (api *Client) GetSymbolInfo(ctx context.Context, symbol string) (*models.SymbolInfo, error) {
+	var res []biconomyModels.RawSymbolInfo
+	resp, err := api.client.R().
+		SetContext(ctx).
+		SetResult(&res).
+		Get(api.v1.Join("exchangeInfo"))
+	if err != nil {
+		return nil, err
+	}
+	if resp.IsError() {
+		return nil, exchangeErrors.FromStatus(exchangeName, "exchangeInfo", resp.StatusCode())
+	}
+	info, found := lo.Find(res, func(info biconomyModels.RawSymbolInfo) bool {
+		return info.Symbol == symbol
+	})
+	if !found {
+		return nil, fmt.Errorf("biconomy symbol not found: %s", symbol)
+	}
+	status := models.SymbolStatusHalted
+	if strings.EqualFold(info.Status, biconomyModels.SymbolStatusTrading) {
+		status = models.SymbolStatusTrading
+	}
+	return &models.SymbolInfo{
+		Symbol:   info.Symbol,
+		Status:   status,
+		TickSize: utils.FormatFloatToString(utils.DecimalsStep(info.QuoteAssetPrecision), -1),
+		LotStep:  utils.FormatFloatToString(utils.DecimalsStep(info.BaseAssetPrecision), -1),
+	}, nil
+}
+
+func (api *Client) PlaceOrder(ctx context.Context, order *models.Order) (*models.OrderRecord, error) {
 	var res biconomyModels.Response[biconomyModels.RawFulfilledOrder]
 
+	// The limit endpoint takes neither a time in force nor a post only flag.
+	orderType, timeInForce := order.Kind()
+	if err := models.ValidateOrderKind(orderType, timeInForce); err != nil {
+		return nil, err
+	}
+	if orderType != models.OrderTypeLimit || timeInForce != models.TimeInForceGTC {
+		return nil, fmt.Errorf("%w: biconomy %s order with time in force %s", exchangeErrors.ErrUnsupported, orderType, timeInForce)
+	}
+
 	formData := map[string]string{
 		"market": order.Symbol,
 		"amount": order.Qty,
@@ -128,22 +289,75 @@ func (api *Client) PlaceOrder(ctx context.Context, order *models.Order) error {
 	}
 
 	resp, err := api.client.R().
+		SetContext(ctx).
 		SetFormData(formData).
 		SetResult(&res).
 		Post(api.v1.Join("private/trade/limit"))
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if resp.IsError() {
-		return fmt.Errorf("biconomy placeOrder request failed with status: %s", resp.Status())
+		return nil, exchangeErrors.FromStatus(exchangeName, "placeOrder", resp.StatusCode())
 	}
 	if !res.IsSuccessful() {
-		return fmt.Errorf("biconomy placeOrder request failed: %s", res.Message)
+		return nil, errorCodes.FromCode(exchangeName, "placeOrder", res.Code, res.Message)
 	}
 
-	return nil
+	placed := res.Result
+	return &models.OrderRecord{
+		Symbol:    placed.Market,
+		OrderID:   utils.FormatIntToString(placed.OrderID),
+		Action:    resolveAction(placed.Side),
+		Price:     placed.Price,
+		Qty:       placed.Amount,
+		FilledQty: placed.DealStock,
+		AvgPrice:  resolveAvgPrice(placed.DealMoney, placed.DealStock),
+		Status:    resolveOrderStatus(placed.Amount, placed.DealStock, false),
+	}, nil
+}
+
+func (api *Client) GetOrder(ctx context.Context, symbol string, orderID string) (*models.OrderRecord, error) {
+	id, err := utils.ParseInt(orderID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid biconomy order id: %s", orderID)
+	}
+	pending, err := api.queryPendingOrder(ctx, symbol, id)
+	if err != nil {
+		return nil, err
+	}
+	if pending != nil {
+		return toPendingOrderRecord(pending), nil
+	}
+	finished, err := api.queryFinishedOrder(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return toFinishedOrderRecord(finished), nil
+}
+
+// GetOrderByClientID always fails, Biconomy orders carry no client order id.
+func (api *Client) GetOrderByClientID(_ context.Context, _ string, clientOrderID string) (*models.OrderRecord, error) {
+	return nil, fmt.Errorf("%w: biconomy order lookup by client order id %s", exchangeErrors.ErrUnsupported, clientOrderID)
+}
+
+func (api *Client) ListOpenOrders(ctx context.Context, symbol string) ([]*models.OrderRecord, error) {
+	records, err := api.queryUnfilledOrders(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	return lo.Map(records, func(order biconomyModels.RawPendingOrder, _ int) *models.OrderRecord {
+		return toPendingOrderRecord(&order)
+	}), nil
+}
+
+func (api *Client) CancelOrder(ctx context.Context, symbol string, orderID string) error {
+	id, err := utils.ParseInt(orderID)
+	if err != nil {
+		return fmt.Errorf("invalid biconomy order id: %s", orderID)
+	}
+	return api.cancelOrder(ctx, symbol, id)
 }
 
 func (api *Client) CancelAllOrders(ctx context.Context, symbol string) error {
@@ -157,15 +371,113 @@ func (api *Client) CancelAllOrders(ctx context.Context, symbol string) error {
 	return nil
 }
 
-func (api *Client) queryUnfilledOrders(_ context.Context, symbol string) ([]biconomyModels.RawPendingOrder, error) {
+// GetFills reports the executions of the symbol since the given time.
+// Biconomy reports executions per order, so every (partially) filled order maps to a single fill at its average price.
+func (api *Client) GetFills(ctx context.Context, symbol string, since time.Time) ([]*models.Fill, error) {
+	finished, err := api.queryFinishedOrders(ctx, symbol, since)
+	if err != nil {
+		return nil, err
+	}
+	pending, err := api.queryUnfilledOrders(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	var fills []*models.Fill
+	for _, order := range finished {
+		if fill := toFill(symbol, order.OrderId, order.Side, order.DealStock, order.DealMoney, order.DealFee, order.FinishedAt); fill != nil {
+			fills = append(fills, fill)
+		}
+	}
+	for _, order := range pending {
+		if order.ModifiedAt < float64(since.Unix()) {
+			continue
+		}
+		if fill := toFill(symbol, order.OrderId, order.Side, order.DealStock, order.DealMoney, order.DealFee, order.ModifiedAt); fill != nil {
+			fills = append(fills, fill)
+		}
+	}
+	return fills, nil
+}
+
+func (api *Client) GetBalances(ctx context.Context) ([]*models.Balance, error) {
+	var res biconomyModels.Response[biconomyModels.RawAssets]
+
+	// The signing hook adds the api key, the only parameter of the request.
+	resp, err := api.client.R().
+		SetContext(ctx).
+		SetResult(&res).
+		Post(api.v1.Join("private/user"))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.IsError() {
+		return nil, exchangeErrors.FromStatus(exchangeName, "getBalances", resp.StatusCode())
+	}
+	if !res.IsSuccessful() {
+		return nil, errorCodes.FromCode(exchangeName, "getBalances", res.Code, res.Message)
+	}
+
+	balances := make([]*models.Balance, 0, len(res.Result))
+	for asset, balance := range res.Result {
+		balances = append(balances, &models.Balance{
+			Asset:  asset,
+			Free:   balance.Available,
+			Locked: balance.Freeze,
+		})
+	}
+	return balances, nil
+}
+
+func (api *Client) queryFinishedOrders(ctx context.Context, symbol string, since time.Time) ([]biconomyModels.RawFinishedOrder, error) {
+	var records []biconomyModels.RawFinishedOrder
+	for page := range maxFillsPages {
+		var res biconomyModels.Response[biconomyModels.FinishedOrdersResult]
+
+		formData := map[string]string{
+			"market":     symbol,
+			"start_time": utils.FormatIntToString(int(since.Unix())),
+			"end_time":   utils.FormatIntToString(int(time.Now().Unix())),
+			"offset":     utils.FormatIntToString(page * fillsPageLimit),
+			"limit":      utils.FormatIntToString(fillsPageLimit),
+		}
+
+		resp, err := api.client.R().
+			SetContext(ctx).
+			SetFormData(formData).
+			SetResult(&res).
+			Post(api.v1.Join("private/order/finished"))
+
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.IsError() {
+			return nil, exchangeErrors.FromStatus(exchangeName, "queryFinishedOrders", resp.StatusCode())
+		}
+		if !res.IsSuccessful() {
+			return nil, errorCodes.FromCode(exchangeName, "queryFinishedOrders", res.Code, res.Message)
+		}
+
+		records = append(records, res.Result.Records...)
+		if len(res.Result.Records) < fillsPageLimit {
+			break
+		}
+	}
+	return records, nil
+}
+
+func (api *Client) queryUnfilledOrders(ctx context.Context, symbol string) ([]biconomyModels.RawPendingOrder, error) {
 	var res biconomyModels.Response[biconomyModels.PendingOrdersResult]
 
 	formData := map[string]string{
 		"market": symbol,
-		"limit":  "10",
+		"limit":  "100",
 	}
 
 	resp, err := api.client.R().
+		SetContext(ctx).
 		SetFormData(formData).
 		SetResult(&res).
 		Post(api.v1.Join("private/order/pending"))
@@ -175,24 +487,87 @@ func (api *Client) queryUnfilledOrders(_ context.Context, symbol string) ([]bico
 	}
 
 	if resp.IsError() {
-		return nil, fmt.Errorf("biconomy queryUnfilledOrders request failed with status: %s", resp.Status())
+		return nil, exchangeErrors.FromStatus(exchangeName, "queryUnfilledOrders", resp.StatusCode())
 	}
 	if !res.IsSuccessful() {
-		return nil, fmt.Errorf("biconomy queryUnfilledOrders request failed: %s", res.Message)
+		return nil, errorCodes.FromCode(exchangeName, "queryUnfilledOrders", res.Code, res.Message)
 	}
 
 	return res.Result.Records, nil
 }
 
-func (api *Client) cancelOrder(_ context.Context, order *biconomyModels.RawPendingOrder) error {
+func (api *Client) queryPendingOrder(ctx context.Context, symbol string, orderID int) (*biconomyModels.RawPendingOrder, error) {
+	var res biconomyModels.Response[biconomyModels.RawPendingOrder]
+
+	formData := map[string]string{
+		"market":   symbol,
+		"order_id": utils.FormatIntToString(orderID),
+	}
+
+	resp, err := api.client.R().
+		SetContext(ctx).
+		SetFormData(formData).
+		SetResult(&res).
+		Post(api.v1.Join("private/order/pending/detail"))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.IsError() {
+		return nil, exchangeErrors.FromStatus(exchangeName, "queryPendingOrder", resp.StatusCode())
+	}
+	if !res.IsSuccessful() {
+		return nil, errorCodes.FromCode(exchangeName, "queryPendingOrder", res.Code, res.Message)
+	}
+	// An empty result means the order is no longer pending.
+	if res.Result.OrderId == 0 {
+		return nil, nil
+	}
+
+	return &res.Result, nil
+}
+
+func (api *Client) queryFinishedOrder(ctx context.Context, orderID int) (*biconomyModels.RawFinishedOrder, error) {
+	var res biconomyModels.Response[biconomyModels.RawFinishedOrder]
+
+	formData := map[string]string{
+		"order_id": utils.FormatIntToString(orderID),
+	}
+
+	resp, err := api.client.R().
+		SetContext(ctx).
+		SetFormData(formData).
+		SetResult(&res).
+		Post(api.v1.Join("private/order/finished/detail"))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.IsError() {
+		return nil, exchangeErrors.FromStatus(exchangeName, "queryFinishedOrder", resp.StatusCode())
+	}
+	if !res.IsSuccessful() {
+		return nil, errorCodes.FromCode(exchangeName, "queryFinishedOrder", res.Code, res.Message)
+	}
+	if res.Result.OrderId == 0 {
+		return nil, fmt.Errorf("%w: biconomy order %d", exchangeErrors.ErrOrderNotFound, orderID)
+	}
+
+	return &res.Result, nil
+}
+
+func (api *Client) cancelOrder(ctx context.Context, symbol string, orderID int) error {
 	var res biconomyModels.Response[biconomyModels.RawCancelledOrder]
 
 	formData := map[string]string{
-		"market":   order.Symbol,
-		"order_id": utils.FormatIntToString(order.OrderId),
+		"market":   symbol,
+		"order_id": utils.FormatIntToString(orderID),
 	}
 
 	resp, err := api.client.R().
+		SetContext(ctx).
 		SetFormData(formData).
 		SetResult(&res).
 		Post(api.v1.Join("private/trade/cancel"))
@@ -202,16 +577,16 @@ func (api *Client) cancelOrder(_ context.Context, order *biconomyModels.RawPendi
 	}
 
 	if resp.IsError() {
-		return fmt.Errorf("biconomy cancelOrder request failed with status: %s", resp.Status())
+		return exchangeErrors.FromStatus(exchangeName, "cancelOrder", resp.StatusCode())
 	}
 	if !res.IsSuccessful() {
-		return fmt.Errorf("biconomy cancelOrder request failed: %s", res.Message)
+		return errorCodes.FromCode(exchangeName, "cancelOrder", res.Code, res.Message)
 	}
 
 	return nil
 }
 
-func (api *Client) batchCancelOrders(_ context.Context, orders []biconomyModels.RawPendingOrder) error {
+func (api *Client) batchCancelOrders(ctx context.Context, orders []biconomyModels.RawPendingOrder) error {
 	var res biconomyModels.Response[biconomyModels.RawCancelledBatch]
 
 	ordersParams := lo.Map(orders, func(order biconomyModels.RawPendingOrder, _ int) biconomyModels.CancelledOrderParam {
@@ -229,6 +604,7 @@ func (api *Client) batchCancelOrders(_ context.Context, orders []biconomyModels.
 	}
 
 	resp, err := api.client.R().
+		SetContext(ctx).
 		SetFormData(formData).
 		SetResult(&res).
 		Post(api.v1.Join("private/trade/cancel_batch"))
@@ -238,10 +614,10 @@ func (api *Client) batchCancelOrders(_ context.Context, orders []biconomyModels.
 	}
 
 	if resp.IsError() {
-		return fmt.Errorf("biconomy batchCancelOrders request failed with status: %s", resp.Status())
+		return exchangeErrors.FromStatus(exchangeName, "batchCancelOrders", resp.StatusCode())
 	}
 	if !res.IsSuccessful() {
-		return fmt.Errorf("biconomy batchCancelOrders request failed: %s", res.Message)
+		return errorCodes.FromCode(exchangeName, "batchCancelOrders", res.Code, res.Message)
 	}
 
 	return nil
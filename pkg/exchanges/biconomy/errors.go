@@ -0,0 +1,15 @@
+package biconomy
+
+import (
+	exchangeErrors "github.com/imbonda/vmm-bot/pkg/exchanges/errors"
+)
+
+const exchangeName = "biconomy"
+
+// errorCodes map the biconomy error codes into the failure classes. The rest of its rejections, a short balance, a bad
+// precision or a suspended symbol, share the invalid parameter code, the rate limit is told by the HTTP 429 status and
+// the suspended symbols by the symbol status.
+var errorCodes = exchangeErrors.Codes{
+	10007: exchangeErrors.ErrAuth,
+	10012: exchangeErrors.ErrOrderNotFound,
+}
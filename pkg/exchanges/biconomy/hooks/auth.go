@@ -28,11 +28,12 @@ func authenticate(request *resty.Request, creds *utils.Credentials) error {
 
 func sign(request *resty.Request, creds *utils.Credentials) {
 	request.SetFormData(map[string]string{"api_key": creds.APIKey})
-	signature := generateSignature(request.FormData, creds)
+	signature := GenerateSignature(request.FormData, creds)
 	request.SetFormData(map[string]string{"sign": signature})
 }
 
-func generateSignature(formData url.Values, creds *utils.Credentials) string {
+// GenerateSignature signs the given form data the way biconomy expects it.
+func GenerateSignature(formData url.Values, creds *utils.Credentials) string {
 	// Step 1: Sort the form data by key
 	var keys []string
 	for key := range formData {
@@ -0,0 +1,9 @@
+package models
+
+// RawAssets maps every asset of the account to its balance.
+type RawAssets map[string]RawAsset
+
+type RawAsset struct {
+	Available string `json:"available"`
+	Freeze    string `json:"freeze"`
+}
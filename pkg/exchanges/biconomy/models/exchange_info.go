@@ -0,0 +1,12 @@
+package models
+
+const SymbolStatusTrading = "trading"
+
+type RawSymbolInfo struct {
+	Symbol              string `json:"symbol"`
+	Status              string `json:"status"`
+	BaseAsset           string `json:"baseAsset"`
+	BaseAssetPrecision  int    `json:"baseAssetPrecision"`
+	QuoteAsset          string `json:"quoteAsset"`
+	QuoteAssetPrecision int    `json:"quoteAssetPrecision"`
+}
@@ -0,0 +1,24 @@
+package models
+
+type RawFinishedOrder struct {
+	Amount     string  `json:"amount"`
+	CreatedAt  float64 `json:"ctime"`
+	DealFee    string  `json:"deal_fee"`
+	DealMoney  string  `json:"deal_money"`
+	DealStock  string  `json:"deal_stock"`
+	FinishedAt float64 `json:"ftime"`
+	OrderId    int     `json:"id"`
+	MakerFee   string  `json:"maker_fee"`
+	Symbol     string  `json:"market"`
+	Price      string  `json:"price"`
+	Side       int     `json:"side"`
+	TakerFee   string  `json:"taker_fee"`
+	Type       int     `json:"type"`
+	User       int     `json:"user"`
+}
+
+type FinishedOrdersResult struct {
+	Limit   int                `json:"limit"`
+	Offset  int                `json:"offset"`
+	Records []RawFinishedOrder `json:"records"`
+}
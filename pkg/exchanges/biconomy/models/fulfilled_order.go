@@ -6,6 +6,9 @@ type RawFulfilledOrder struct {
 	Market     string  `json:"market"`
 	Price      string  `json:"price"`
 	Side       int     `json:"side"`
+	DealStock  string  `json:"deal_stock"`
+	DealMoney  string  `json:"deal_money"`
+	Left       string  `json:"left"`
 	CreatedAt  float64 `json:"ctime"`
 	ModifiedAt float64 `json:"mtime"`
 }
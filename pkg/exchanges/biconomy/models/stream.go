@@ -0,0 +1,43 @@
+package models
+
+import "encoding/json"
+
+const (
+	StreamMethodPing           = "server.ping"
+	StreamMethodDepthSubscribe = "depth.subscribe"
+	StreamMethodDealsSubscribe = "deals.subscribe"
+	StreamMethodDepthUpdate    = "depth.update"
+	StreamMethodDealsUpdate    = "deals.update"
+)
+
+type RawStreamRequest struct {
+	ID     int    `json:"id"`
+	Method string `json:"method"`
+	Params []any  `json:"params"`
+}
+
+// RawStreamMessage is either a response to a request (id is set) or a subscription update.
+type RawStreamMessage struct {
+	ID     *int              `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+	Error  *RawStreamError   `json:"error"`
+}
+
+type RawStreamError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type RawStreamDepth struct {
+	Asks [][]string `json:"asks"`
+	Bids [][]string `json:"bids"`
+}
+
+type RawStreamDeal struct {
+	ID     int     `json:"id"`
+	Time   float64 `json:"time"`
+	Price  string  `json:"price"`
+	Amount string  `json:"amount"`
+	Type   string  `json:"type"`
+}
@@ -0,0 +1,34 @@
+package biconomy
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/imbonda/vmm-bot/pkg/exchanges/ratelimit"
+)
+
+// RateLimitRules are the rate limits of the biconomy API. The private endpoints are all posts, the trade ones place
+// and cancel orders.
+func RateLimitRules() ratelimit.Rules {
+	return ratelimit.Rules{
+		Budgets: map[ratelimit.Class]ratelimit.Budget{
+			ratelimit.Public: {Limit: 20, Interval: time.Second},
+			ratelimit.Query:  {Limit: 10, Interval: time.Second},
+			ratelimit.Order:  {Limit: 10, Interval: time.Second},
+		},
+		Classify: classifyRequest,
+	}
+}
+
+func classifyRequest(request *http.Request) ratelimit.Endpoint {
+	path := request.URL.Path
+	switch {
+	case strings.Contains(path, "/private/trade/"):
+		return ratelimit.Endpoint{Class: ratelimit.Order, Weight: 1}
+	case strings.Contains(path, "/private/"):
+		return ratelimit.Endpoint{Class: ratelimit.Query, Weight: 1}
+	default:
+		return ratelimit.Endpoint{Class: ratelimit.Public, Weight: 1}
+	}
+}
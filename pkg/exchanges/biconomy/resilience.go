@@ -0,0 +1,18 @@
+package biconomy
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/imbonda/vmm-bot/pkg/exchanges/resilience"
+)
+
+// RetryRules retry the reads, the private queries included, but never the trades, as biconomy orders carry no
+// client order ID to deduplicate them.
+func RetryRules() resilience.Rules {
+	return resilience.Rules{
+		Idempotent: func(request *http.Request, _ []byte) bool {
+			return !strings.Contains(request.URL.Path, "/private/trade/")
+		},
+	}
+}
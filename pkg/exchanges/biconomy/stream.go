@@ -0,0 +1,112 @@
+package biconomy
+
+import (
+	"encoding/json"
+	"fmt"
+
+	biconomyModels "github.com/imbonda/vmm-bot/pkg/exchanges/biconomy/models"
+	"github.com/imbonda/vmm-bot/pkg/exchanges/stream"
+)
+
+const (
+	BaseStreamURL = "wss://bei.biconomy.com/ws"
+	// Depth levels kept locally to resolve the top of book from incremental updates.
+	streamDepthLimit = 20
+)
+
+// StreamDialect speaks the biconomy public websocket, a JSON-RPC protocol pushing incremental depth updates.
+type StreamDialect struct {
+	url string
+}
+
+func NewStreamDialect(url string) *StreamDialect {
+	if url == "" {
+		url = BaseStreamURL
+	}
+	return &StreamDialect{url: url}
+}
+
+func (d *StreamDialect) URL() string {
+	return d.url
+}
+
+func (d *StreamDialect) Subscribe(symbol string) ([][]byte, error) {
+	depth, err := json.Marshal(&biconomyModels.RawStreamRequest{
+		ID:     1,
+		Method: biconomyModels.StreamMethodDepthSubscribe,
+		Params: []any{symbol, streamDepthLimit, "0"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	deals, err := json.Marshal(&biconomyModels.RawStreamRequest{
+		ID:     2,
+		Method: biconomyModels.StreamMethodDealsSubscribe,
+		Params: []any{symbol},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return [][]byte{depth, deals}, nil
+}
+
+func (d *StreamDialect) Ping() []byte {
+	message, _ := json.Marshal(&biconomyModels.RawStreamRequest{
+		ID:     3,
+		Method: biconomyModels.StreamMethodPing,
+		Params: []any{},
+	})
+	return message
+}
+
+func (d *StreamDialect) Handle(message []byte, book *stream.TopOfBook) ([]byte, error) {
+	var raw biconomyModels.RawStreamMessage
+	if err := json.Unmarshal(message, &raw); err != nil {
+		return nil, err
+	}
+	if raw.Error != nil {
+		return nil, fmt.Errorf("biconomy stream request %v failed: %s", raw.ID, raw.Error.Message)
+	}
+	switch raw.Method {
+	case biconomyModels.StreamMethodDepthUpdate:
+		// Params: [clean, depth, market].
+		if len(raw.Params) < 2 {
+			return nil, fmt.Errorf("unexpected biconomy depth update")
+		}
+		var clean bool
+		var depth biconomyModels.RawStreamDepth
+		if err := json.Unmarshal(raw.Params[0], &clean); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(raw.Params[1], &depth); err != nil {
+			return nil, err
+		}
+		book.ApplyLevels(clean, depth.Asks, depth.Bids)
+	case biconomyModels.StreamMethodDealsUpdate:
+		// Params: [market, deals].
+		if len(raw.Params) < 2 {
+			return nil, fmt.Errorf("unexpected biconomy deals update")
+		}
+		var deals []biconomyModels.RawStreamDeal
+		if err := json.Unmarshal(raw.Params[1], &deals); err != nil {
+			return nil, err
+		}
+		if last, found := lastDeal(deals); found {
+			book.SetLastPrice(last.Price)
+		}
+	}
+	return nil, nil
+}
+
+func lastDeal(deals []biconomyModels.RawStreamDeal) (*biconomyModels.RawStreamDeal, bool) {
+	if len(deals) < 1 {
+		return nil, false
+	}
+	last := &deals[0]
+	for i := range deals {
+		if deals[i].Time > last.Time || (deals[i].Time == last.Time && deals[i].ID > last.ID) {
+			last = &deals[i]
+		}
+	}
+	return last, true
+}
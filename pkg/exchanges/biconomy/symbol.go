@@ -0,0 +1,8 @@
+package biconomy
+
+import "github.com/imbonda/vmm-bot/pkg/exchanges"
+
+// Biconomy symbols are written STOP_USDT.
+func init() {
+	exchanges.RegisterSymbolFormat(exchanges.Biconomy, exchanges.SeparatedSymbolFormat("_"))
+}
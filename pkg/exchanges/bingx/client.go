@@ -3,14 +3,20 @@ package bingx
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/go-kit/log"
 	"github.com/go-resty/resty/v2"
+	"github.com/samber/lo"
 
 	"github.com/imbonda/vmm-bot/pkg/exchanges/bingx/hooks"
 	bingxModels "github.com/imbonda/vmm-bot/pkg/exchanges/bingx/models"
+	exchangeErrors "github.com/imbonda/vmm-bot/pkg/exchanges/errors"
+	"github.com/imbonda/vmm-bot/pkg/exchanges/ratelimit"
+	"github.com/imbonda/vmm-bot/pkg/exchanges/resilience"
 	"github.com/imbonda/vmm-bot/pkg/models"
 	"github.com/imbonda/vmm-bot/pkg/utils"
 )
@@ -35,6 +41,93 @@ func resolveSide(action models.OrderAction) tradingSide {
 	return SELL
 }
 
+func resolveAction(side string) models.OrderAction {
+	if side == BUY {
+		return models.Buy
+	}
+	return models.Sell
+}
+
+// resolveOrderKind maps the order type and time in force into their bingx names, a limit maker order is a PostOnly
+// limit order and a market order, which has no time in force, is filled as IOC.
+func resolveOrderKind(order *models.Order) (string, string, error) {
+	orderType, timeInForce := order.Kind()
+	if err := models.ValidateOrderKind(orderType, timeInForce); err != nil {
+		return "", "", err
+	}
+	switch {
+	case orderType == models.OrderTypeLimit:
+		return "LIMIT", strings.ToUpper(string(timeInForce)), nil
+	case orderType == models.OrderTypeLimitMaker:
+		return "LIMIT", "PostOnly", nil
+	case timeInForce == models.TimeInForceIOC:
+		return "MARKET", "", nil
+	default:
+		return "", "", fmt.Errorf("%w: bingx %s order with time in force %s", exchangeErrors.ErrUnsupported, orderType, timeInForce)
+	}
+}
+
+func resolveOrderStatus(status string) models.OrderStatus {
+	switch status {
+	case bingxModels.OrderStatusNew, bingxModels.OrderStatusPending:
+		return models.OrderStatusNew
+	case bingxModels.OrderStatusPartiallyFilled:
+		return models.OrderStatusPartiallyFilled
+	case bingxModels.OrderStatusFilled:
+		return models.OrderStatusFilled
+	case bingxModels.OrderStatusCanceled:
+		return models.OrderStatusCancelled
+	case bingxModels.OrderStatusFailed:
+		return models.OrderStatusRejected
+	default:
+		return models.OrderStatusUnknown
+	}
+}
+
+func toOrderRecord(order *bingxModels.RawPendingOrder) *models.OrderRecord {
+	return &models.OrderRecord{
+		Symbol:        order.Symbol,
+		OrderID:       utils.FormatIntToString(order.OrderID),
+		ClientOrderID: order.ClientOrderID,
+		Action:        resolveAction(order.Side),
+		Price:         order.Price,
+		Qty:           order.OrigQty,
+		FilledQty:     order.ExecQty,
+		AvgPrice:      order.AvgPrice(),
+		Status:        resolveOrderStatus(order.Status),
+	}
+}
+
+func toFill(fill *bingxModels.RawFill) *models.Fill {
+	action := models.Sell
+	if fill.IsBuyer {
+		action = models.Buy
+	}
+	return &models.Fill{
+		Symbol:  fill.Symbol,
+		TradeID: utils.FormatIntToString(fill.ID),
+		OrderID: utils.FormatIntToString(fill.OrderID),
+		Action:  action,
+		Price:   fill.Price,
+		Qty:     fill.Qty,
+		// Commissions are reported as negative amounts.
+		Fee:      strings.TrimPrefix(fill.Commission, "-"),
+		FeeAsset: fill.CommissionAsset,
+		Maker:    fill.IsMaker,
+		Time:     time.UnixMilli(fill.Time),
+	}
+}
+
+// formatRule leaves the rules bingx doesn't set empty.
+func formatRule(value float64) string {
+	if value <= 0 {
+		return ""
+	}
+	return utils.FormatFloatToString(value, -1)
+}
+
+const fillsLimit = 1000
+
 type Client struct {
 	v1     *utils.Endpoint
 	creds  *utils.Credentials
@@ -43,24 +136,41 @@ type Client struct {
 }
 
 type NewClientInput struct {
+	BaseURL    string
 	APIKey     string
 	APISecret  string
 	APITimeout time.Duration
+	// RateLimiter holds every request until its rate limit budget allows it, nil doesn't limit them.
+	RateLimiter *ratelimit.Limiter
+	// Resilience retries the failed requests and breaks the circuit on repeated failures, nil sends every request once.
+	Resilience *resilience.Policy
 	Logger     log.Logger
 }
 
 func NewClient(ctx context.Context, input *NewClientInput) (*Client, error) {
+	baseURL := input.BaseURL
+	if baseURL == "" {
+		baseURL = BaseAPIURL
+	}
 	v1 := utils.NewEndpoint(APIV1)
 	creds := &utils.Credentials{
 		APIKey:    input.APIKey,
 		APISecret: input.APISecret,
 	}
 	client := resty.New().
-		SetBaseURL(BaseAPIURL).
+		SetBaseURL(baseURL).
 		SetHeader("Content-Type", "application/json").
 		SetTimeout(input.APITimeout)
 	// Add credentials to every request.
 	client.OnBeforeRequest(hooks.GetSigAuthBeforeRequestHook(client, creds))
+	httpClient := client.GetClient()
+	if input.RateLimiter != nil {
+		httpClient.Transport = input.RateLimiter.Transport(httpClient.Transport)
+	}
+	// Every retry takes its own rate limit budget.
+	if input.Resilience != nil {
+		httpClient.Transport = input.Resilience.Transport(httpClient.Transport)
+	}
 	return &Client{
 		v1:     v1,
 		creds:  creds,
@@ -117,12 +227,14 @@ func (api *Client) GetLastTicker(ctx context.Context, symbol string) (*models.Ti
 		LastPrice: priceTicker.LastPrice,
 		BestAsk:   bookTicker.AskPrice,
 		BestBid:   bookTicker.BidPrice,
+		Time:      utils.UnixMilliTime(int64(bookTicker.Time)),
 	}, nil
 }
 
 func (api *Client) getOrderBookTicker(ctx context.Context, symbol string) (*bingxModels.BookTicker, error) {
 	var res bingxModels.Response[bingxModels.RawBookTickers]
 	resp, err := api.client.R().
+		SetContext(ctx).
 		SetResult(&res).
 		SetQueryParam("symbol", symbol).
 		Get(api.v1.Join("ticker/bookTicker"))
@@ -130,16 +242,17 @@ func (api *Client) getOrderBookTicker(ctx context.Context, symbol string) (*bing
 		return nil, err
 	}
 	if resp.IsError() {
-		return nil, fmt.Errorf("bingx orderbook request failed with status: %s", resp.Status())
+		return nil, exchangeErrors.FromStatus(exchangeName, "orderbook", resp.StatusCode())
 	}
 	if !res.IsSuccessful() {
-		return nil, fmt.Errorf("bingx orderbook request failed: %s", res.Message)
+		return nil, errorCodes.FromCode(exchangeName, "orderbook", res.Code, res.Message)
 	}
 	bookTicker, err := res.Result.LastTicker()
 	if err != nil {
 		return nil, err
 	}
 	return &bingxModels.BookTicker{
+		Time:      bookTicker.Time,
 		AskPrice:  bookTicker.AskPrice,
 		AskAmount: bookTicker.AskAmount,
 		BidPrice:  bookTicker.BidPrice,
@@ -150,6 +263,7 @@ func (api *Client) getOrderBookTicker(ctx context.Context, symbol string) (*bing
 func (api *Client) getPriceTicker(ctx context.Context, symbol string) (*bingxModels.PriceTicker, error) {
 	var res bingxModels.Response[bingxModels.RawPriceTickers]
 	resp, err := api.client.R().
+		SetContext(ctx).
 		SetResult(&res).
 		SetQueryParam("symbol", symbol).
 		Get(api.v1.Join("ticker/price"))
@@ -157,10 +271,10 @@ func (api *Client) getPriceTicker(ctx context.Context, symbol string) (*bingxMod
 		return nil, err
 	}
 	if resp.IsError() {
-		return nil, fmt.Errorf("bingx orderbook request failed with status: %s", resp.Status())
+		return nil, exchangeErrors.FromStatus(exchangeName, "priceTicker", resp.StatusCode())
 	}
 	if !res.IsSuccessful() {
-		return nil, fmt.Errorf("bingx orderbook request failed: %s", res.Message)
+		return nil, errorCodes.FromCode(exchangeName, "priceTicker", res.Code, res.Message)
 	}
 	priceTicker, err := res.Result.LastTicker()
 	if err != nil {
@@ -175,31 +289,230 @@ func (api *Client) getPriceTicker(ctx context.Context, symbol string) (*bingxMod
 	}, nil
 }
 
-func (api *Client) PlaceOrder(ctx context.Context, order *models.Order) error {
+func (api *Client) GetSymbolInfo(ctx context.Context, symbol string) (*models.SymbolInfo, error) {
+	var res bingxModels.Response[bingxModels.RawSymbols]
+	resp, err := api.client.R().
+		SetContext(ctx).
+		SetResult(&res).
+		SetQueryParam("symbol", symbol).
+		Get(api.v1.Join("common/symbols"))
+	if err != nil {
+		return nil, err
+	}
+	if resp.IsError() {
+		return nil, exchangeErrors.FromStatus(exchangeName, "symbols", resp.StatusCode())
+	}
+	if !res.IsSuccessful() {
+		return nil, errorCodes.FromCode(exchangeName, "symbols", res.Code, res.Message)
+	}
+	info, found := lo.Find(res.Result.Symbols, func(info bingxModels.RawSymbol) bool {
+		return info.Symbol == symbol
+	})
+	if !found {
+		return nil, fmt.Errorf("bingx symbol not found: %s", symbol)
+	}
+	status := models.SymbolStatusHalted
+	if info.Status == bingxModels.SymbolStatusOnline {
+		status = models.SymbolStatusTrading
+	}
+	return &models.SymbolInfo{
+		Symbol:      info.Symbol,
+		Status:      status,
+		TickSize:    formatRule(info.TickSize),
+		LotStep:     formatRule(info.StepSize),
+		MinQty:      formatRule(info.MinQty),
+		MinNotional: formatRule(info.MinNotional),
+	}, nil
+}
+
+func (api *Client) PlaceOrder(ctx context.Context, order *models.Order) (*models.OrderRecord, error) {
 	var res bingxModels.Response[bingxModels.RawPendingOrder]
 
+	orderType, timeInForce, err := resolveOrderKind(order)
+	if err != nil {
+		return nil, err
+	}
 	formData := map[string]string{
-		"type":     "LIMIT",
+		"type":     orderType,
 		"symbol":   order.Symbol,
 		"side":     string(resolveSide(order.Action)),
 		"quantity": order.Qty,
-		"price":    order.Price,
+	}
+	if orderType == "LIMIT" {
+		formData["price"] = order.Price
+		formData["timeInForce"] = timeInForce
+	}
+	if order.ClientOrderID != "" {
+		formData["newClientOrderId"] = order.ClientOrderID
 	}
 
 	resp, err := api.client.R().
+		SetContext(ctx).
 		SetFormData(formData).
 		SetResult(&res).
 		Post(api.v1.Join("trade/order"))
 
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.IsError() {
+		return nil, exchangeErrors.FromStatus(exchangeName, "placeOrder", resp.StatusCode())
+	}
+	if !res.IsSuccessful() {
+		return nil, errorCodes.FromCode(exchangeName, "placeOrder", res.Code, res.Message)
+	}
+
+	record := toOrderRecord(&res.Result)
+	if record.ClientOrderID == "" {
+		record.ClientOrderID = order.ClientOrderID
+	}
+	return record, nil
+}
+
+func (api *Client) GetOrder(ctx context.Context, symbol string, orderID string) (*models.OrderRecord, error) {
+	return api.queryOrder(ctx, "getOrder", map[string]string{
+		"symbol":  symbol,
+		"orderId": orderID,
+	})
+}
+
+func (api *Client) GetOrderByClientID(ctx context.Context, symbol string, clientOrderID string) (*models.OrderRecord, error) {
+	return api.queryOrder(ctx, "getOrderByClientId", map[string]string{
+		"symbol":        symbol,
+		"clientOrderID": clientOrderID,
+	})
+}
+
+func (api *Client) queryOrder(ctx context.Context, operation string, params map[string]string) (*models.OrderRecord, error) {
+	var res bingxModels.Response[bingxModels.RawPendingOrder]
+
+	resp, err := api.client.R().
+		SetContext(ctx).
+		SetResult(&res).
+		SetQueryParams(params).
+		Get(api.v1.Join("trade/query"))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.IsError() {
+		return nil, exchangeErrors.FromStatus(exchangeName, operation, resp.StatusCode())
+	}
+	if !res.IsSuccessful() {
+		return nil, errorCodes.FromCode(exchangeName, operation, res.Code, res.Message)
+	}
+
+	return toOrderRecord(&res.Result), nil
+}
+
+func (api *Client) ListOpenOrders(ctx context.Context, symbol string) ([]*models.OrderRecord, error) {
+	var res bingxModels.Response[bingxModels.RawOpenOrders]
+
+	resp, err := api.client.R().
+		SetContext(ctx).
+		SetResult(&res).
+		SetQueryParam("symbol", symbol).
+		Get(api.v1.Join("trade/openOrders"))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.IsError() {
+		return nil, exchangeErrors.FromStatus(exchangeName, "listOpenOrders", resp.StatusCode())
+	}
+	if !res.IsSuccessful() {
+		return nil, errorCodes.FromCode(exchangeName, "listOpenOrders", res.Code, res.Message)
+	}
+
+	return lo.Map(res.Result.Orders, func(order bingxModels.RawPendingOrder, _ int) *models.OrderRecord {
+		return toOrderRecord(&order)
+	}), nil
+}
+
+// GetFills reports up to fillsLimit executions of the symbol since the given time.
+func (api *Client) GetFills(ctx context.Context, symbol string, since time.Time) ([]*models.Fill, error) {
+	var res bingxModels.Response[bingxModels.RawFills]
+
+	resp, err := api.client.R().
+		SetContext(ctx).
+		SetResult(&res).
+		SetQueryParams(map[string]string{
+			"symbol":    symbol,
+			"startTime": strconv.FormatInt(since.UnixMilli(), 10),
+			"limit":     utils.FormatIntToString(fillsLimit),
+		}).
+		Get(api.v1.Join("trade/myTrades"))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.IsError() {
+		return nil, exchangeErrors.FromStatus(exchangeName, "getFills", resp.StatusCode())
+	}
+	if !res.IsSuccessful() {
+		return nil, errorCodes.FromCode(exchangeName, "getFills", res.Code, res.Message)
+	}
+
+	return lo.Map(res.Result.Fills, func(fill bingxModels.RawFill, _ int) *models.Fill {
+		return toFill(&fill)
+	}), nil
+}
+
+func (api *Client) GetBalances(ctx context.Context) ([]*models.Balance, error) {
+	var res bingxModels.Response[bingxModels.RawBalances]
+
+	resp, err := api.client.R().
+		SetContext(ctx).
+		SetResult(&res).
+		Get(api.v1.Join("account/balance"))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.IsError() {
+		return nil, exchangeErrors.FromStatus(exchangeName, "getBalances", resp.StatusCode())
+	}
+	if !res.IsSuccessful() {
+		return nil, errorCodes.FromCode(exchangeName, "getBalances", res.Code, res.Message)
+	}
+
+	return lo.Map(res.Result.Balances, func(balance bingxModels.RawBalance, _ int) *models.Balance {
+		return &models.Balance{
+			Asset:  balance.Asset,
+			Free:   balance.Free,
+			Locked: balance.Locked,
+		}
+	}), nil
+}
+
+func (api *Client) CancelOrder(ctx context.Context, symbol string, orderID string) error {
+	var res bingxModels.Response[bingxModels.RawCancelledOrder]
+
+	formData := map[string]string{
+		"symbol":  symbol,
+		"orderId": orderID,
+	}
+
+	resp, err := api.client.R().
+		SetContext(ctx).
+		SetFormData(formData).
+		SetResult(&res).
+		Post(api.v1.Join("trade/cancel"))
+
 	if err != nil {
 		return err
 	}
 
 	if resp.IsError() {
-		return fmt.Errorf("bingx placeOrder request failed with status: %s", resp.Status())
+		return exchangeErrors.FromStatus(exchangeName, "cancelOrder", resp.StatusCode())
 	}
 	if !res.IsSuccessful() {
-		return fmt.Errorf("bingx placeOrder request failed: %s", res.Message)
+		return errorCodes.FromCode(exchangeName, "cancelOrder", res.Code, res.Message)
 	}
 
 	return nil
@@ -213,6 +526,7 @@ func (api *Client) CancelAllOrders(ctx context.Context, symbol string) error {
 	}
 
 	resp, err := api.client.R().
+		SetContext(ctx).
 		SetFormData(formData).
 		SetResult(&res).
 		Post(api.v1.Join("trade/cancelOpenOrders"))
@@ -222,10 +536,10 @@ func (api *Client) CancelAllOrders(ctx context.Context, symbol string) error {
 	}
 
 	if resp.IsError() {
-		return fmt.Errorf("bingx cancelAllOrders request failed with status: %s", resp.Status())
+		return exchangeErrors.FromStatus(exchangeName, "cancelAllOrders", resp.StatusCode())
 	}
 	if !res.IsSuccessful() {
-		return fmt.Errorf("bingx cancelAllOrders request failed: %s", res.Message)
+		return errorCodes.FromCode(exchangeName, "cancelAllOrders", res.Code, res.Message)
 	}
 
 	return nil
@@ -0,0 +1,127 @@
+package bingx_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/imbonda/vmm-bot/pkg/exchanges/bingx"
+	exchangeErrors "github.com/imbonda/vmm-bot/pkg/exchanges/errors"
+	"github.com/imbonda/vmm-bot/pkg/exchanges/sim"
+	"github.com/imbonda/vmm-bot/pkg/models"
+	"github.com/imbonda/vmm-bot/pkg/utils"
+)
+
+const (
+	symbol    = "BTC-USDT"
+	apiKey    = "key"
+	apiSecret = "secret"
+)
+
+// newClient returns a client of the sim, which verifies the signatures of the client independently.
+func newClient(t *testing.T, secret string) *bingx.Client {
+	t.Helper()
+	server := httptest.NewServer(sim.NewServer(&sim.NewServerInput{
+		Engine: sim.NewEngine(&sim.NewEngineInput{
+			Markets: map[string]float64{symbol: 100},
+			Spread:  0.01,
+			Depth:   10,
+			Balance: 1000,
+			Rules:   sim.SymbolRules{TickSize: 0.01, LotStep: 0.01},
+		}),
+		Bybit:    &utils.Credentials{},
+		Biconomy: &utils.Credentials{},
+		BingX:    &utils.Credentials{APIKey: apiKey, APISecret: apiSecret},
+		Logger:   log.NewNopLogger(),
+	}).Handler())
+	t.Cleanup(server.Close)
+	client, err := bingx.NewClient(context.Background(), &bingx.NewClientInput{
+		BaseURL:    server.URL,
+		APIKey:     apiKey,
+		APISecret:  secret,
+		APITimeout: 5 * time.Second,
+		Logger:     log.NewNopLogger(),
+	})
+	require.NoError(t, err)
+	return client
+}
+
+func TestMarketData(t *testing.T) {
+	ctx := context.Background()
+	client := newClient(t, apiSecret)
+
+	ticker, err := client.GetLastTicker(ctx, symbol)
+	require.NoError(t, err)
+	assert.Equal(t, symbol, ticker.Symbol)
+	assert.NotEmpty(t, ticker.LastPrice)
+
+	book, err := client.GetOrderBook(ctx, symbol)
+	require.NoError(t, err)
+	ask, err := book.Ask()
+	require.NoError(t, err)
+	bid, err := book.Bid()
+	require.NoError(t, err)
+	assert.Less(t, parseFloat(t, bid), parseFloat(t, ask))
+}
+
+func TestOrderRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	client := newClient(t, apiSecret)
+
+	placed, err := client.PlaceOrder(ctx, &models.Order{
+		Symbol:        symbol,
+		Price:         "90",
+		Qty:           "1",
+		Action:        models.Buy,
+		ClientOrderID: "client-1",
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, placed.OrderID)
+
+	order, err := client.GetOrder(ctx, symbol, placed.OrderID)
+	require.NoError(t, err)
+	assert.Equal(t, models.OrderStatusNew, order.Status)
+	assert.Equal(t, models.Buy, order.Action)
+
+	order, err = client.GetOrderByClientID(ctx, symbol, "client-1")
+	require.NoError(t, err)
+	assert.Equal(t, placed.OrderID, order.OrderID)
+
+	open, err := client.ListOpenOrders(ctx, symbol)
+	require.NoError(t, err)
+	assert.Len(t, open, 1)
+
+	require.NoError(t, client.CancelOrder(ctx, symbol, placed.OrderID))
+	order, err = client.GetOrder(ctx, symbol, placed.OrderID)
+	require.NoError(t, err)
+	assert.Equal(t, models.OrderStatusCancelled, order.Status)
+}
+
+func TestInvalidSignature(t *testing.T) {
+	client := newClient(t, "wrong")
+	_, err := client.ListOpenOrders(context.Background(), symbol)
+	assert.ErrorIs(t, err, exchangeErrors.ErrAuth)
+}
+
+func TestCancelledContext(t *testing.T) {
+	client := newClient(t, apiSecret)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := client.PlaceOrder(ctx, &models.Order{Symbol: symbol, Price: "90", Qty: "1", Action: models.Buy})
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = client.ListOpenOrders(ctx, symbol)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func parseFloat(t *testing.T, value string) float64 {
+	t.Helper()
+	parsed, err := utils.ParseFloat(value)
+	require.NoError(t, err)
+	return parsed
+}
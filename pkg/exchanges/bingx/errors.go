@@ -0,0 +1,21 @@
+package bingx
+
+import (
+	exchangeErrors "github.com/imbonda/vmm-bot/pkg/exchanges/errors"
+)
+
+const exchangeName = "bingx"
+
+// errorCodes map the bingx error codes into the failure classes. Bingx has no code of its own for a bad precision or a
+// suspended symbol, which it reports as the invalid parameter 100400, so the suspended symbols are only told by the symbol
+// status.
+var errorCodes = exchangeErrors.Codes{
+	100001: exchangeErrors.ErrAuth,
+	100413: exchangeErrors.ErrAuth,
+	100419: exchangeErrors.ErrAuth,
+	100202: exchangeErrors.ErrInsufficientBalance,
+	100404: exchangeErrors.ErrOrderNotFound,
+	100410: exchangeErrors.ErrRateLimited,
+	100500: exchangeErrors.ErrTransient,
+	100503: exchangeErrors.ErrTransient,
+}
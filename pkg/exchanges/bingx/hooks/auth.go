@@ -19,23 +19,46 @@ func GetSigAuthBeforeRequestHook(client *resty.Client, creds *utils.Credentials)
 	}
 }
 
+// privatePaths are the path segments of the endpoints which require a signature, market data is public.
+var privatePaths = []string{"/trade/", "/account/"}
+
 func authenticate(request *resty.Request, creds *utils.Credentials) error {
-	if request.Method != http.MethodPost {
-		return nil
+	switch {
+	case request.Method == http.MethodPost:
+		signForm(request, creds)
+	case request.Method == http.MethodGet && isPrivate(request.URL):
+		signQuery(request, creds)
 	}
-	sign(request, creds)
 	return nil
 }
 
-func sign(request *resty.Request, creds *utils.Credentials) {
+func isPrivate(rawURL string) bool {
+	for _, path := range privatePaths {
+		if strings.Contains(rawURL, path) {
+			return true
+		}
+	}
+	return false
+}
+
+func signForm(request *resty.Request, creds *utils.Credentials) {
 	request.Header.Set("X-BX-APIKEY", creds.APIKey)
 	timestamp := time.Now().UnixNano() / 1e6
 	request.SetFormData(map[string]string{"timestamp": fmt.Sprint(timestamp)})
-	signature := generateSignature(request.FormData, creds)
+	signature := GenerateSignature(request.FormData, creds)
 	request.SetFormData(map[string]string{"signature": signature})
 }
 
-func generateSignature(formData url.Values, creds *utils.Credentials) string {
+func signQuery(request *resty.Request, creds *utils.Credentials) {
+	request.Header.Set("X-BX-APIKEY", creds.APIKey)
+	timestamp := time.Now().UnixNano() / 1e6
+	request.SetQueryParam("timestamp", fmt.Sprint(timestamp))
+	signature := GenerateSignature(request.QueryParam, creds)
+	request.SetQueryParam("signature", signature)
+}
+
+// GenerateSignature signs the given parameters the way bingx expects it.
+func GenerateSignature(formData url.Values, creds *utils.Credentials) string {
 	// Step 1: Sort the form data by key
 	var keys []string
 	for key := range formData {
@@ -0,0 +1,11 @@
+package models
+
+type RawBalances struct {
+	Balances []RawBalance `json:"balances"`
+}
+
+type RawBalance struct {
+	Asset  string `json:"asset"`
+	Free   string `json:"free"`
+	Locked string `json:"locked"`
+}
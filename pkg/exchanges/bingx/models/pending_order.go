@@ -1,5 +1,16 @@
 package models
 
+import "github.com/imbonda/vmm-bot/pkg/utils"
+
+const (
+	OrderStatusNew             = "NEW"
+	OrderStatusPending         = "PENDING"
+	OrderStatusPartiallyFilled = "PARTIALLY_FILLED"
+	OrderStatusFilled          = "FILLED"
+	OrderStatusCanceled        = "CANCELED"
+	OrderStatusFailed          = "FAILED"
+)
+
 type RawPendingOrder struct {
 	Symbol        string `json:"symbol"`
 	OrderID       int    `json:"orderId"`
@@ -13,3 +24,20 @@ type RawPendingOrder struct {
 	Type          string `json:"type"`
 	Side          string `json:"side"`
 }
+
+// AvgPrice derives the average fill price from the executed base and quote quantities.
+func (o *RawPendingOrder) AvgPrice() string {
+	execQty, err := utils.ParseFloat(o.ExecQty)
+	if err != nil || execQty == 0 {
+		return "0"
+	}
+	quoteQty, err := utils.ParseFloat(o.CummQuoteQty)
+	if err != nil {
+		return "0"
+	}
+	return utils.FormatFloatToString(quoteQty/execQty, -1)
+}
+
+type RawOpenOrders struct {
+	Orders []RawPendingOrder `json:"orders"`
+}
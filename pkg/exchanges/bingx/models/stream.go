@@ -0,0 +1,49 @@
+package models
+
+import "encoding/json"
+
+const (
+	StreamPing = "Ping"
+	StreamPong = "Pong"
+
+	StreamBookTickerSuffix = "@bookTicker"
+	StreamTradeSuffix      = "@trade"
+)
+
+type RawStreamRequest struct {
+	ID       string `json:"id"`
+	ReqType  string `json:"reqType"`
+	DataType string `json:"dataType"`
+}
+
+// RawStreamMessage is either a subscription response, a push of the data type, or a JSON heartbeat.
+type RawStreamMessage struct {
+	ID       string          `json:"id"`
+	Code     int             `json:"code"`
+	Msg      string          `json:"msg"`
+	DataType string          `json:"dataType"`
+	Data     json.RawMessage `json:"data"`
+	Ping     string          `json:"ping"`
+	Time     string          `json:"time"`
+}
+
+type RawStreamPong struct {
+	Pong string `json:"pong"`
+	Time string `json:"time"`
+}
+
+type RawStreamBookTicker struct {
+	Symbol    string `json:"s"`
+	BidPrice  string `json:"b"`
+	BidAmount string `json:"B"`
+	AskPrice  string `json:"a"`
+	AskAmount string `json:"A"`
+}
+
+type RawStreamTrade struct {
+	Symbol  string `json:"s"`
+	TradeID string `json:"t"`
+	Time    int64  `json:"T"`
+	Price   string `json:"p"`
+	Qty     string `json:"q"`
+}
@@ -0,0 +1,18 @@
+package models
+
+const SymbolStatusOnline = 1
+
+type RawSymbols struct {
+	Symbols []RawSymbol `json:"symbols"`
+}
+
+type RawSymbol struct {
+	Symbol      string  `json:"symbol"`
+	TickSize    float64 `json:"tickSize"`
+	StepSize    float64 `json:"stepSize"`
+	MinQty      float64 `json:"minQty"`
+	MaxQty      float64 `json:"maxQty"`
+	MinNotional float64 `json:"minNotional"`
+	MaxNotional float64 `json:"maxNotional"`
+	Status      int     `json:"status"`
+}
@@ -22,6 +22,7 @@ type RawBookTicker struct {
 }
 
 type BookTicker struct {
+	Time      int
 	AskPrice  string
 	AskAmount string
 	BidPrice  string
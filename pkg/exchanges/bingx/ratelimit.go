@@ -0,0 +1,34 @@
+package bingx
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/imbonda/vmm-bot/pkg/exchanges/ratelimit"
+)
+
+// RateLimitRules are the rate limits of the bingx spot API: market data is limited per IP, trading and account
+// queries per API key.
+func RateLimitRules() ratelimit.Rules {
+	return ratelimit.Rules{
+		Budgets: map[ratelimit.Class]ratelimit.Budget{
+			ratelimit.Public: {Limit: 100, Interval: 10 * time.Second},
+			ratelimit.Query:  {Limit: 10, Interval: time.Second},
+			ratelimit.Order:  {Limit: 5, Interval: time.Second},
+		},
+		Classify: classifyRequest,
+	}
+}
+
+func classifyRequest(request *http.Request) ratelimit.Endpoint {
+	path := strings.TrimPrefix(request.URL.Path, "/"+APIV1+"/")
+	switch {
+	case strings.HasPrefix(path, "ticker/"), strings.HasPrefix(path, "common/"), strings.HasPrefix(path, "market/"):
+		return ratelimit.Endpoint{Class: ratelimit.Public, Weight: 1}
+	case request.Method == http.MethodPost && strings.HasPrefix(path, "trade/"):
+		return ratelimit.Endpoint{Class: ratelimit.Order, Weight: 1}
+	default:
+		return ratelimit.Endpoint{Class: ratelimit.Query, Weight: 1}
+	}
+}
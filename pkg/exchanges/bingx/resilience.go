@@ -0,0 +1,36 @@
+package bingx
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/imbonda/vmm-bot/pkg/exchanges/resilience"
+)
+
+// retryMaxElapsed is half the 5s window bingx accepts the timestamp of a signed request in, leaving the last retry time
+// to reach bingx.
+const retryMaxElapsed = 2500 * time.Millisecond
+
+// RetryRules retry the reads, cancelling all the open orders, and the orders carrying a client order ID, which bingx
+// doesn't place twice.
+func RetryRules() resilience.Rules {
+	return resilience.Rules{
+		MaxElapsed: retryMaxElapsed,
+		Idempotent: func(request *http.Request, body []byte) bool {
+			if request.Method == http.MethodGet {
+				return true
+			}
+			switch strings.TrimPrefix(request.URL.Path, "/"+APIV1+"/") {
+			case "trade/cancelOpenOrders":
+				return true
+			case "trade/order":
+				form, err := url.ParseQuery(string(body))
+				return err == nil && form.Get("newClientOrderId") != ""
+			default:
+				return false
+			}
+		},
+	}
+}
@@ -0,0 +1,79 @@
+package bingx
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	bingxModels "github.com/imbonda/vmm-bot/pkg/exchanges/bingx/models"
+	"github.com/imbonda/vmm-bot/pkg/exchanges/stream"
+)
+
+const BaseStreamURL = "wss://open-api-ws.bingx.com/market"
+
+// StreamDialect speaks the bingx spot market websocket, the server compresses every message and pings the client.
+type StreamDialect struct {
+	url string
+}
+
+func NewStreamDialect(url string) *StreamDialect {
+	if url == "" {
+		url = BaseStreamURL
+	}
+	return &StreamDialect{url: url}
+}
+
+func (d *StreamDialect) URL() string {
+	return d.url
+}
+
+func (d *StreamDialect) Subscribe(symbol string) ([][]byte, error) {
+	var messages [][]byte
+	for _, dataType := range []string{symbol + bingxModels.StreamBookTickerSuffix, symbol + bingxModels.StreamTradeSuffix} {
+		message, err := json.Marshal(&bingxModels.RawStreamRequest{
+			ID:       dataType,
+			ReqType:  "sub",
+			DataType: dataType,
+		})
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, message)
+	}
+	return messages, nil
+}
+
+func (d *StreamDialect) Ping() []byte {
+	return nil
+}
+
+func (d *StreamDialect) Handle(message []byte, book *stream.TopOfBook) ([]byte, error) {
+	if string(message) == bingxModels.StreamPing {
+		return []byte(bingxModels.StreamPong), nil
+	}
+	var raw bingxModels.RawStreamMessage
+	if err := json.Unmarshal(message, &raw); err != nil {
+		return nil, err
+	}
+	if raw.Ping != "" {
+		return json.Marshal(&bingxModels.RawStreamPong{Pong: raw.Ping, Time: raw.Time})
+	}
+	if raw.Code != 0 {
+		return nil, fmt.Errorf("bingx stream %s failed: %s", raw.ID, raw.Msg)
+	}
+	switch {
+	case strings.HasSuffix(raw.DataType, bingxModels.StreamBookTickerSuffix):
+		var bookTicker bingxModels.RawStreamBookTicker
+		if err := json.Unmarshal(raw.Data, &bookTicker); err != nil {
+			return nil, err
+		}
+		book.SetBook(bookTicker.AskPrice, bookTicker.BidPrice)
+	case strings.HasSuffix(raw.DataType, bingxModels.StreamTradeSuffix):
+		var trade bingxModels.RawStreamTrade
+		if err := json.Unmarshal(raw.Data, &trade); err != nil {
+			return nil, err
+		}
+		book.SetLastPrice(trade.Price)
+	}
+	return nil, nil
+}
@@ -0,0 +1,8 @@
+package bingx
+
+import "github.com/imbonda/vmm-bot/pkg/exchanges"
+
+// BingX symbols are written STOP-USDT.
+func init() {
+	exchanges.RegisterSymbolFormat(exchanges.BingX, exchanges.SeparatedSymbolFormat("-"))
+}
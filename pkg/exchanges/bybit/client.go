@@ -3,13 +3,27 @@ package bybit
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	bybit "github.com/bybit-exchange/bybit.go.api"
 	"github.com/go-kit/log"
+	"github.com/samber/lo"
 
 	bybitModels "github.com/imbonda/vmm-bot/pkg/exchanges/bybit/models"
+	exchangeErrors "github.com/imbonda/vmm-bot/pkg/exchanges/errors"
+	"github.com/imbonda/vmm-bot/pkg/exchanges/ratelimit"
+	"github.com/imbonda/vmm-bot/pkg/exchanges/resilience"
 	"github.com/imbonda/vmm-bot/pkg/models"
+	"github.com/imbonda/vmm-bot/pkg/utils"
+)
+
+const (
+	fillsPageLimit = 100
+	maxFillsPages  = 10
 )
 
 type Client struct {
@@ -18,20 +32,37 @@ type Client struct {
 }
 
 type NewClientInput struct {
+	BaseURL    string
 	APIKey     string
 	APISecret  string
 	APITimeout time.Duration
+	// RateLimiter holds every request until its rate limit budget allows it, nil doesn't limit them.
+	RateLimiter *ratelimit.Limiter
+	// Resilience retries the failed requests and breaks the circuit on repeated failures, nil sends every request once.
+	Resilience *resilience.Policy
 	Logger     log.Logger
 }
 
 func NewClient(ctx context.Context, input *NewClientInput) (*Client, error) {
+	baseURL := input.BaseURL
+	if baseURL == "" {
+		baseURL = bybit.MAINNET
+	}
 	return &Client{
 		client: bybit.NewBybitHttpClient(
 			input.APIKey,
 			input.APISecret,
-			bybit.WithBaseURL(bybit.MAINNET),
+			bybit.WithBaseURL(baseURL),
 			func(c *bybit.Client) {
-				c.HTTPClient.Timeout = input.APITimeout
+				// The default client is shared by the whole process, so the timeout and the rate limiter get their own.
+				c.HTTPClient = &http.Client{Timeout: input.APITimeout}
+				if input.RateLimiter != nil {
+					c.HTTPClient.Transport = input.RateLimiter.Transport(nil)
+				}
+				// Every retry takes its own rate limit budget.
+				if input.Resilience != nil {
+					c.HTTPClient.Transport = input.Resilience.Transport(c.HTTPClient.Transport)
+				}
 			},
 		),
 	}, nil
@@ -47,10 +78,9 @@ func (api *Client) GetOrderBook(ctx context.Context, symbol string) (*models.Ord
 		).
 		GetOrderBookInfo(ctx)
 	if err != nil {
-		return nil, err
+		return nil, requestError("getOrderBook", err)
 	}
-	wrappedRes := bybitModels.Response(*res)
-	if err = wrappedRes.Validate(); err != nil {
+	if err = checkResponse("getOrderBook", res); err != nil {
 		return nil, err
 	}
 	data, err := json.Marshal(res.Result)
@@ -72,10 +102,9 @@ func (api *Client) GetLastTicker(ctx context.Context, symbol string) (*models.Ti
 		).
 		GetMarketTickers(ctx)
 	if err != nil {
-		return nil, err
+		return nil, requestError("getLastTicker", err)
 	}
-	wrappedRes := bybitModels.Response(*res)
-	if err = wrappedRes.Validate(); err != nil {
+	if err = checkResponse("getLastTicker", res); err != nil {
 		return nil, err
 	}
 	data, err := json.Marshal(res.Result)
@@ -96,33 +125,138 @@ func (api *Client) GetLastTicker(ctx context.Context, symbol string) (*models.Ti
 		LastPrice: ticker.LastPrice,
 		BestAsk:   ticker.BestAskPrice,
 		BestBid:   ticker.BestBidPrice,
+		Volume:    ticker.Volume24h,
+		Time:      utils.UnixMilliTime(res.Time),
 	}
 	return result, nil
 }
 
-func (api *Client) PlaceOrder(ctx context.Context, order *models.Order) error {
+func (api *Client) PlaceOrder(ctx context.Context, order *models.Order) (*models.OrderRecord, error) {
+	orderType, timeInForce, err := resolveOrderKind(order)
+	if err != nil {
+		return nil, err
+	}
+	params := map[string]any{
+		"category":    "spot",
+		"symbol":      order.Symbol,
+		"side":        order.Action,
+		"positionIdx": 0,
+		"orderType":   orderType,
+		"qty":         order.Qty,
+		"timeInForce": timeInForce,
+	}
+	if orderType == "Market" {
+		// Spot market buys are sized in quote currency unless told otherwise.
+		params["marketUnit"] = "baseCoin"
+	} else {
+		params["price"] = order.Price
+	}
+	if order.ClientOrderID != "" {
+		params["orderLinkId"] = order.ClientOrderID
+	}
+	res, err := api.client.
+		NewUtaBybitServiceWithParams(params).
+		PlaceOrder(ctx)
+	if err != nil {
+		return nil, requestError("placeOrder", err)
+	}
+	placed := &bybitModels.RawPlacedOrder{}
+	if err = decodeResult("placeOrder", res, placed); err != nil {
+		return nil, err
+	}
+	// Bybit only acknowledges the order, the fill state is available through GetOrder.
+	return &models.OrderRecord{
+		Symbol:        order.Symbol,
+		OrderID:       placed.OrderID,
+		ClientOrderID: lo.CoalesceOrEmpty(placed.OrderLinkID, order.ClientOrderID),
+		Action:        order.Action,
+		Price:         order.Price,
+		Qty:           order.Qty,
+		FilledQty:     "0",
+		AvgPrice:      "0",
+		Status:        models.OrderStatusNew,
+	}, nil
+}
+
+func (api *Client) GetOrder(ctx context.Context, symbol string, orderID string) (*models.OrderRecord, error) {
+	return api.queryOrder(ctx, "getOrder", map[string]any{
+		"category": "spot",
+		"symbol":   symbol,
+		"orderId":  orderID,
+	})
+}
+
+func (api *Client) GetOrderByClientID(ctx context.Context, symbol string, clientOrderID string) (*models.OrderRecord, error) {
+	return api.queryOrder(ctx, "getOrderByClientId", map[string]any{
+		"category":    "spot",
+		"symbol":      symbol,
+		"orderLinkId": clientOrderID,
+	})
+}
+
+// queryOrder looks the order up among the open orders, and then among the closed ones.
+func (api *Client) queryOrder(ctx context.Context, operation string, params map[string]any) (*models.OrderRecord, error) {
+	res, err := api.client.NewUtaBybitServiceWithParams(params).GetOpenOrders(ctx)
+	if err != nil {
+		return nil, requestError(operation, err)
+	}
+	rawResult := &bybitModels.RawOrdersResult{}
+	if err = decodeResult(operation, res, rawResult); err != nil {
+		return nil, err
+	}
+	if len(rawResult.List) == 0 {
+		// Closed orders are only reported by the history endpoint.
+		res, err = api.client.NewUtaBybitServiceWithParams(params).GetOrderHistory(ctx)
+		if err != nil {
+			return nil, requestError(operation, err)
+		}
+		if err = decodeResult(operation, res, rawResult); err != nil {
+			return nil, err
+		}
+	}
+	rawOrder, err := rawResult.FirstOrder()
+	if err != nil {
+		return nil, fmt.Errorf("%w: bybit order %v", exchangeErrors.ErrOrderNotFound, lo.CoalesceOrEmpty(params["orderId"], params["orderLinkId"]))
+	}
+	return toOrderRecord(rawOrder), nil
+}
+
+func (api *Client) ListOpenOrders(ctx context.Context, symbol string) ([]*models.OrderRecord, error) {
 	res, err := api.client.
 		NewUtaBybitServiceWithParams(
 			map[string]any{
-				"category":    "spot",
-				"symbol":      order.Symbol,
-				"side":        order.Action,
-				"positionIdx": 0,
-				"orderType":   "Limit",
-				"qty":         order.Qty,
-				"price":       order.Price,
-				"timeInForce": "GTC",
+				"category": "spot",
+				"symbol":   symbol,
+				"openOnly": 0,
 			},
 		).
-		PlaceOrder(context.Background())
+		GetOpenOrders(ctx)
 	if err != nil {
-		return err
+		return nil, requestError("listOpenOrders", err)
 	}
-	wrappedRes := bybitModels.Response(*res)
-	if err = wrappedRes.Validate(); err != nil {
-		return err
+	rawResult := &bybitModels.RawOrdersResult{}
+	if err = decodeResult("listOpenOrders", res, rawResult); err != nil {
+		return nil, err
 	}
-	return nil
+	return lo.Map(rawResult.List, func(order bybitModels.RawOrder, _ int) *models.OrderRecord {
+		return toOrderRecord(&order)
+	}), nil
+}
+
+func (api *Client) CancelOrder(ctx context.Context, symbol string, orderID string) error {
+	res, err := api.client.
+		NewUtaBybitServiceWithParams(
+			map[string]any{
+				"category": "spot",
+				"symbol":   symbol,
+				"orderId":  orderID,
+			},
+		).
+		CancelOrder(ctx)
+	if err != nil {
+		return requestError("cancelOrder", err)
+	}
+	return checkResponse("cancelOrder", res)
 }
 
 func (api *Client) CancelAllOrders(ctx context.Context, symbol string) error {
@@ -133,13 +267,219 @@ func (api *Client) CancelAllOrders(ctx context.Context, symbol string) error {
 				"symbol":   symbol,
 			},
 		).
-		CancelAllOrders(context.Background())
+		CancelAllOrders(ctx)
 	if err != nil {
-		return err
+		return requestError("cancelAllOrders", err)
 	}
-	wrappedRes := bybitModels.Response(*res)
-	if err = wrappedRes.Validate(); err != nil {
+	if err = checkResponse("cancelAllOrders", res); err != nil {
 		return err
 	}
 	return nil
 }
+
+// GetFills pages through the executions of the symbol since the given time, up to maxFillsPages pages.
+func (api *Client) GetFills(ctx context.Context, symbol string, since time.Time) ([]*models.Fill, error) {
+	var fills []*models.Fill
+	cursor := ""
+	for range maxFillsPages {
+		params := map[string]any{
+			"category":  "spot",
+			"symbol":    symbol,
+			"startTime": since.UnixMilli(),
+			"limit":     fillsPageLimit,
+		}
+		if cursor != "" {
+			params["cursor"] = cursor
+		}
+		res, err := api.client.NewUtaBybitServiceWithParams(params).GetTradeHistory(ctx)
+		if err != nil {
+			return nil, requestError("getFills", err)
+		}
+		rawResult := &bybitModels.RawExecutionsResult{}
+		if err = decodeResult("getFills", res, rawResult); err != nil {
+			return nil, err
+		}
+		for _, execution := range rawResult.List {
+			fill, err := toFill(&execution)
+			if err != nil {
+				return nil, err
+			}
+			fills = append(fills, fill)
+		}
+		cursor = rawResult.NextPageCursor
+		if cursor == "" || len(rawResult.List) < fillsPageLimit {
+			break
+		}
+	}
+	return fills, nil
+}
+
+func (api *Client) GetSymbolInfo(ctx context.Context, symbol string) (*models.SymbolInfo, error) {
+	res, err := api.client.NewUtaBybitServiceWithParams(map[string]any{
+		"category": "spot",
+		"symbol":   symbol,
+	}).GetInstrumentInfo(ctx)
+	if err != nil {
+		return nil, requestError("getSymbolInfo", err)
+	}
+	rawResult := &bybitModels.RawInstrumentsResult{}
+	if err = decodeResult("getSymbolInfo", res, rawResult); err != nil {
+		return nil, err
+	}
+	instrument, found := lo.Find(rawResult.List, func(instrument bybitModels.RawInstrument) bool {
+		return instrument.Symbol == symbol
+	})
+	if !found {
+		return nil, fmt.Errorf("bybit symbol not found: %s", symbol)
+	}
+	status := models.SymbolStatusHalted
+	if instrument.Status == bybitModels.InstrumentStatusTrading {
+		status = models.SymbolStatusTrading
+	}
+	return &models.SymbolInfo{
+		Symbol:      instrument.Symbol,
+		Status:      status,
+		TickSize:    instrument.PriceFilter.TickSize,
+		LotStep:     instrument.LotSizeFilter.BasePrecision,
+		MinQty:      instrument.LotSizeFilter.MinOrderQty,
+		MinNotional: instrument.LotSizeFilter.MinOrderAmt,
+	}, nil
+}
+
+// GetBalances reports the coins of the unified trading account, the free amount excludes the balance locked by open orders.
+func (api *Client) GetBalances(ctx context.Context) ([]*models.Balance, error) {
+	res, err := api.client.NewUtaBybitServiceWithParams(map[string]any{
+		"accountType": "UNIFIED",
+	}).GetAccountWallet(ctx)
+	if err != nil {
+		return nil, requestError("getBalances", err)
+	}
+	rawResult := &bybitModels.RawWalletBalanceResult{}
+	if err = decodeResult("getBalances", res, rawResult); err != nil {
+		return nil, err
+	}
+	var balances []*models.Balance
+	for _, account := range rawResult.List {
+		for _, coin := range account.Coin {
+			balance, err := toBalance(&coin)
+			if err != nil {
+				return nil, err
+			}
+			balances = append(balances, balance)
+		}
+	}
+	return balances, nil
+}
+
+func decodeResult(operation string, res *bybit.ServerResponse, result any) error {
+	if err := checkResponse(operation, res); err != nil {
+		return err
+	}
+	data, err := json.Marshal(res.Result)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, result)
+}
+
+func toOrderRecord(order *bybitModels.RawOrder) *models.OrderRecord {
+	return &models.OrderRecord{
+		Symbol:        order.Symbol,
+		OrderID:       order.OrderID,
+		ClientOrderID: order.OrderLinkID,
+		Action:        resolveAction(order.Side),
+		Price:         order.Price,
+		Qty:           order.Qty,
+		FilledQty:     order.CumExecQty,
+		AvgPrice:      order.AvgPrice,
+		Status:        resolveOrderStatus(order.OrderStatus),
+	}
+}
+
+func toFill(execution *bybitModels.RawExecution) (*models.Fill, error) {
+	execTime, err := strconv.ParseInt(execution.ExecTime, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bybit execution time: %s", execution.ExecTime)
+	}
+	return &models.Fill{
+		Symbol:   execution.Symbol,
+		TradeID:  execution.ExecID,
+		OrderID:  execution.OrderID,
+		Action:   resolveAction(execution.Side),
+		Price:    execution.ExecPrice,
+		Qty:      execution.ExecQty,
+		Fee:      execution.ExecFee,
+		FeeAsset: execution.FeeCurrency,
+		Maker:    execution.IsMaker,
+		Time:     time.UnixMilli(execTime),
+	}, nil
+}
+
+func toBalance(coin *bybitModels.RawWalletCoin) (*models.Balance, error) {
+	total, err := parseAmount(coin.WalletBalance)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bybit %s wallet balance: %s", coin.Coin, coin.WalletBalance)
+	}
+	locked, err := parseAmount(coin.Locked)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bybit %s locked balance: %s", coin.Coin, coin.Locked)
+	}
+	return &models.Balance{
+		Asset:  coin.Coin,
+		Free:   strconv.FormatFloat(max(total-locked, 0), 'f', -1, 64),
+		Locked: strconv.FormatFloat(locked, 'f', -1, 64),
+	}, nil
+}
+
+// parseAmount treats the empty amounts bybit reports for unused fields as zero.
+func parseAmount(amount string) (float64, error) {
+	if amount == "" {
+		return 0, nil
+	}
+	return strconv.ParseFloat(amount, 64)
+}
+
+// resolveOrderKind maps the order type and time in force into their bybit names, a limit maker order is a PostOnly
+// limit order and a market order is always IOC.
+func resolveOrderKind(order *models.Order) (string, string, error) {
+	orderType, timeInForce := order.Kind()
+	if err := models.ValidateOrderKind(orderType, timeInForce); err != nil {
+		return "", "", err
+	}
+	switch {
+	case orderType == models.OrderTypeLimit:
+		return "Limit", strings.ToUpper(string(timeInForce)), nil
+	case orderType == models.OrderTypeLimitMaker:
+		return "Limit", "PostOnly", nil
+	case timeInForce == models.TimeInForceIOC:
+		return "Market", "IOC", nil
+	default:
+		return "", "", fmt.Errorf("%w: bybit %s order with time in force %s", exchangeErrors.ErrUnsupported, orderType, timeInForce)
+	}
+}
+
+func resolveAction(side string) models.OrderAction {
+	if strings.EqualFold(side, string(models.Buy)) {
+		return models.Buy
+	}
+	return models.Sell
+}
+
+func resolveOrderStatus(status string) models.OrderStatus {
+	switch status {
+	case bybitModels.OrderStatusNew:
+		return models.OrderStatusNew
+	case bybitModels.OrderStatusPartiallyFilled:
+		return models.OrderStatusPartiallyFilled
+	case bybitModels.OrderStatusFilled:
+		return models.OrderStatusFilled
+	case bybitModels.OrderStatusCancelled,
+		bybitModels.OrderStatusPartiallyFilledCanceled,
+		bybitModels.OrderStatusDeactivated:
+		return models.OrderStatusCancelled
+	case bybitModels.OrderStatusRejected:
+		return models.OrderStatusRejected
+	default:
+		return models.OrderStatusUnknown
+	}
+}
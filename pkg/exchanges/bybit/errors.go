@@ -0,0 +1,64 @@
+package bybit
+
+import (
+	"errors"
+	"fmt"
+
+	bybit "github.com/bybit-exchange/bybit.go.api"
+	"github.com/bybit-exchange/bybit.go.api/handlers"
+
+	bybitModels "github.com/imbonda/vmm-bot/pkg/exchanges/bybit/models"
+	exchangeErrors "github.com/imbonda/vmm-bot/pkg/exchanges/errors"
+)
+
+const exchangeName = "bybit"
+
+// errorCodes map the bybit v5 return codes into the failure classes.
+var errorCodes = exchangeErrors.Codes{
+	10003:  exchangeErrors.ErrAuth,
+	10004:  exchangeErrors.ErrAuth,
+	10005:  exchangeErrors.ErrAuth,
+	10010:  exchangeErrors.ErrAuth,
+	33004:  exchangeErrors.ErrAuth,
+	10006:  exchangeErrors.ErrRateLimited,
+	10018:  exchangeErrors.ErrRateLimited,
+	170005: exchangeErrors.ErrRateLimited,
+	170222: exchangeErrors.ErrRateLimited,
+	10016:  exchangeErrors.ErrTransient,
+	110004: exchangeErrors.ErrInsufficientBalance,
+	110007: exchangeErrors.ErrInsufficientBalance,
+	170131: exchangeErrors.ErrInsufficientBalance,
+	170133: exchangeErrors.ErrInvalidPrecision,
+	170134: exchangeErrors.ErrInvalidPrecision,
+	170135: exchangeErrors.ErrInvalidPrecision,
+	170136: exchangeErrors.ErrInvalidPrecision,
+	170137: exchangeErrors.ErrInvalidPrecision,
+	170140: exchangeErrors.ErrInvalidPrecision,
+	170148: exchangeErrors.ErrInvalidPrecision,
+	170151: exchangeErrors.ErrSymbolHalted,
+	170157: exchangeErrors.ErrSymbolHalted,
+	110001: exchangeErrors.ErrOrderNotFound,
+	170213: exchangeErrors.ErrOrderNotFound,
+}
+
+// checkResponse classifies a response which failed with a return code.
+func checkResponse(operation string, res *bybit.ServerResponse) error {
+	wrappedRes := bybitModels.Response(*res)
+	if !wrappedRes.IsSuccessful() {
+		return errorCodes.FromCode(exchangeName, operation, wrappedRes.RetCode, wrappedRes.RetMsg)
+	}
+	return nil
+}
+
+// requestError classifies the errors of the SDK, which fails the responses with an HTTP error status without telling
+// the status, along with their return code when they have one.
+func requestError(operation string, err error) error {
+	var apiErr *handlers.APIError
+	if !errors.As(err, &apiErr) {
+		return err
+	}
+	if apiErr.Code != 0 {
+		return errorCodes.FromCode(exchangeName, operation, int(apiErr.Code), apiErr.Message)
+	}
+	return fmt.Errorf("%w: bybit %s request failed with an HTTP error status", exchangeErrors.ErrTransient, operation)
+}
@@ -0,0 +1,22 @@
+package models
+
+type RawExecutionsResult struct {
+	Category       string         `json:"category"`
+	NextPageCursor string         `json:"nextPageCursor"`
+	List           []RawExecution `json:"list"`
+}
+
+type RawExecution struct {
+	Symbol      string `json:"symbol"`
+	OrderID     string `json:"orderId"`
+	OrderLinkID string `json:"orderLinkId"`
+	Side        string `json:"side"`
+	ExecID      string `json:"execId"`
+	ExecPrice   string `json:"execPrice"`
+	ExecQty     string `json:"execQty"`
+	ExecFee     string `json:"execFee"`
+	FeeCurrency string `json:"feeCurrency"`
+	ExecType    string `json:"execType"`
+	ExecTime    string `json:"execTime"`
+	IsMaker     bool   `json:"isMaker"`
+}
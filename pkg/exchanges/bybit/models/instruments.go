@@ -0,0 +1,25 @@
+package models
+
+const InstrumentStatusTrading = "Trading"
+
+type RawInstrumentsResult struct {
+	Category string          `json:"category"`
+	List     []RawInstrument `json:"list"`
+}
+
+type RawInstrument struct {
+	Symbol        string           `json:"symbol"`
+	Status        string           `json:"status"`
+	LotSizeFilter RawLotSizeFilter `json:"lotSizeFilter"`
+	PriceFilter   RawPriceFilter   `json:"priceFilter"`
+}
+
+type RawLotSizeFilter struct {
+	BasePrecision string `json:"basePrecision"`
+	MinOrderQty   string `json:"minOrderQty"`
+	MinOrderAmt   string `json:"minOrderAmt"`
+}
+
+type RawPriceFilter struct {
+	TickSize string `json:"tickSize"`
+}
@@ -0,0 +1,48 @@
+package models
+
+import "fmt"
+
+const (
+	OrderStatusNew                     = "New"
+	OrderStatusPartiallyFilled         = "PartiallyFilled"
+	OrderStatusFilled                  = "Filled"
+	OrderStatusCancelled               = "Cancelled"
+	OrderStatusPartiallyFilledCanceled = "PartiallyFilledCanceled"
+	OrderStatusRejected                = "Rejected"
+	OrderStatusDeactivated             = "Deactivated"
+)
+
+type RawPlacedOrder struct {
+	OrderID     string `json:"orderId"`
+	OrderLinkID string `json:"orderLinkId"`
+}
+
+type RawOrdersResult struct {
+	Category       string     `json:"category"`
+	NextPageCursor string     `json:"nextPageCursor"`
+	List           []RawOrder `json:"list"`
+}
+
+type RawOrder struct {
+	OrderID     string `json:"orderId"`
+	OrderLinkID string `json:"orderLinkId"`
+	Symbol      string `json:"symbol"`
+	Price       string `json:"price"`
+	Qty         string `json:"qty"`
+	Side        string `json:"side"`
+	OrderStatus string `json:"orderStatus"`
+	OrderType   string `json:"orderType"`
+	TimeInForce string `json:"timeInForce"`
+	AvgPrice    string `json:"avgPrice"`
+	CumExecQty  string `json:"cumExecQty"`
+	CumExecFee  string `json:"cumExecFee"`
+	CreatedTime string `json:"createdTime"`
+	UpdatedTime string `json:"updatedTime"`
+}
+
+func (r *RawOrdersResult) FirstOrder() (*RawOrder, error) {
+	if len(r.List) < 1 {
+		return nil, fmt.Errorf("no orders found")
+	}
+	return &r.List[0], nil
+}
@@ -1,8 +1,6 @@
 package models
 
 import (
-	"fmt"
-
 	bybit "github.com/bybit-exchange/bybit.go.api"
 )
 
@@ -12,14 +10,6 @@ const (
 
 type Response bybit.ServerResponse
 
-func (r *Response) Validate() error {
-	if !r.IsSuccessful() {
-		err := fmt.Errorf("bybit request failed: %v", r.RetMsg)
-		return err
-	}
-	return nil
-}
-
 func (r *Response) IsSuccessful() bool {
 	return r.RetCode == successCode
 }
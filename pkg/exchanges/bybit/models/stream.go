@@ -0,0 +1,37 @@
+package models
+
+import "encoding/json"
+
+const (
+	StreamOpSubscribe = "subscribe"
+	StreamOpPing      = "ping"
+)
+
+type RawStreamRequest struct {
+	Op   string   `json:"op"`
+	Args []string `json:"args,omitempty"`
+}
+
+// RawStreamMessage is either an operation response (op is set) or a topic push.
+type RawStreamMessage struct {
+	Op      string          `json:"op"`
+	Success *bool           `json:"success"`
+	RetMsg  string          `json:"ret_msg"`
+	Topic   string          `json:"topic"`
+	Type    string          `json:"type"`
+	Data    json.RawMessage `json:"data"`
+}
+
+type RawStreamOrderBook struct {
+	Symbol string     `json:"s"`
+	Bids   [][]string `json:"b"`
+	Asks   [][]string `json:"a"`
+}
+
+type RawStreamTrade struct {
+	Time   int64  `json:"T"`
+	Symbol string `json:"s"`
+	Side   string `json:"S"`
+	Qty    string `json:"v"`
+	Price  string `json:"p"`
+}
@@ -0,0 +1,16 @@
+package models
+
+type RawWalletBalanceResult struct {
+	List []RawWalletAccount `json:"list"`
+}
+
+type RawWalletAccount struct {
+	AccountType string          `json:"accountType"`
+	Coin        []RawWalletCoin `json:"coin"`
+}
+
+type RawWalletCoin struct {
+	Coin          string `json:"coin"`
+	WalletBalance string `json:"walletBalance"`
+	Locked        string `json:"locked"`
+}
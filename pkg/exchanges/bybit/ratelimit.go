@@ -0,0 +1,34 @@
+package bybit
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/imbonda/vmm-bot/pkg/exchanges/ratelimit"
+)
+
+// RateLimitRules are the rate limits of the bybit v5 API: market data is limited per IP, the spot order and account
+// endpoints per account.
+func RateLimitRules() ratelimit.Rules {
+	return ratelimit.Rules{
+		Budgets: map[ratelimit.Class]ratelimit.Budget{
+			ratelimit.Public: {Limit: 600, Interval: 5 * time.Second},
+			ratelimit.Query:  {Limit: 50, Interval: time.Second},
+			ratelimit.Order:  {Limit: 20, Interval: time.Second},
+		},
+		Classify: classifyRequest,
+	}
+}
+
+func classifyRequest(request *http.Request) ratelimit.Endpoint {
+	path := request.URL.Path
+	switch {
+	case strings.HasPrefix(path, "/v5/market/"):
+		return ratelimit.Endpoint{Class: ratelimit.Public, Weight: 1}
+	case request.Method == http.MethodPost && strings.HasPrefix(path, "/v5/order/"):
+		return ratelimit.Endpoint{Class: ratelimit.Order, Weight: 1}
+	default:
+		return ratelimit.Endpoint{Class: ratelimit.Query, Weight: 1}
+	}
+}
@@ -0,0 +1,36 @@
+package bybit
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/imbonda/vmm-bot/pkg/exchanges/resilience"
+)
+
+// retryMaxElapsed is half the 5s recv_window of the signed requests, leaving the last retry time to reach bybit.
+const retryMaxElapsed = 2500 * time.Millisecond
+
+// RetryRules retry the reads, cancelling all the open orders, and the orders carrying an order link ID, which bybit
+// doesn't place twice.
+func RetryRules() resilience.Rules {
+	return resilience.Rules{
+		MaxElapsed: retryMaxElapsed,
+		Idempotent: func(request *http.Request, body []byte) bool {
+			if request.Method == http.MethodGet {
+				return true
+			}
+			switch request.URL.Path {
+			case "/v5/order/cancel-all":
+				return true
+			case "/v5/order/create":
+				var order struct {
+					OrderLinkID string `json:"orderLinkId"`
+				}
+				return json.Unmarshal(body, &order) == nil && order.OrderLinkID != ""
+			default:
+				return false
+			}
+		},
+	}
+}
@@ -0,0 +1,90 @@
+package bybit
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	bybit "github.com/bybit-exchange/bybit.go.api"
+
+	bybitModels "github.com/imbonda/vmm-bot/pkg/exchanges/bybit/models"
+	"github.com/imbonda/vmm-bot/pkg/exchanges/stream"
+)
+
+const (
+	orderBookTopicPrefix = "orderbook.1."
+	tradeTopicPrefix     = "publicTrade."
+)
+
+// StreamDialect speaks the bybit v5 public spot websocket.
+type StreamDialect struct {
+	url string
+}
+
+func NewStreamDialect(url string) *StreamDialect {
+	if url == "" {
+		url = bybit.SPOT_MAINNET
+	}
+	return &StreamDialect{url: url}
+}
+
+func (d *StreamDialect) URL() string {
+	return d.url
+}
+
+func (d *StreamDialect) Subscribe(symbol string) ([][]byte, error) {
+	message, err := json.Marshal(&bybitModels.RawStreamRequest{
+		Op:   bybitModels.StreamOpSubscribe,
+		Args: []string{orderBookTopicPrefix + symbol, tradeTopicPrefix + symbol},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return [][]byte{message}, nil
+}
+
+func (d *StreamDialect) Ping() []byte {
+	message, _ := json.Marshal(&bybitModels.RawStreamRequest{Op: bybitModels.StreamOpPing})
+	return message
+}
+
+func (d *StreamDialect) Handle(message []byte, book *stream.TopOfBook) ([]byte, error) {
+	var raw bybitModels.RawStreamMessage
+	if err := json.Unmarshal(message, &raw); err != nil {
+		return nil, err
+	}
+	if raw.Success != nil && !*raw.Success {
+		return nil, fmt.Errorf("bybit stream %s failed: %s", raw.Op, raw.RetMsg)
+	}
+	switch {
+	case strings.HasPrefix(raw.Topic, orderBookTopicPrefix):
+		var orderBook bybitModels.RawStreamOrderBook
+		if err := json.Unmarshal(raw.Data, &orderBook); err != nil {
+			return nil, err
+		}
+		// The single level book is always pushed as a snapshot.
+		book.ApplyLevels(true, orderBook.Asks, orderBook.Bids)
+	case strings.HasPrefix(raw.Topic, tradeTopicPrefix):
+		var trades []bybitModels.RawStreamTrade
+		if err := json.Unmarshal(raw.Data, &trades); err != nil {
+			return nil, err
+		}
+		if last, found := lastTrade(trades); found {
+			book.SetLastPrice(last.Price)
+		}
+	}
+	return nil, nil
+}
+
+func lastTrade(trades []bybitModels.RawStreamTrade) (*bybitModels.RawStreamTrade, bool) {
+	if len(trades) < 1 {
+		return nil, false
+	}
+	last := &trades[0]
+	for i := range trades {
+		if trades[i].Time >= last.Time {
+			last = &trades[i]
+		}
+	}
+	return last, true
+}
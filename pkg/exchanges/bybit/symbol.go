@@ -0,0 +1,8 @@
+package bybit
+
+import "github.com/imbonda/vmm-bot/pkg/exchanges"
+
+// Bybit symbols are written STOPUSDT.
+func init() {
+	exchanges.RegisterSymbolFormat(exchanges.Bybit, exchanges.ConcatenatedSymbolFormat)
+}
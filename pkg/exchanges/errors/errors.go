@@ -0,0 +1,154 @@
+// Package errors classifies the failures of the exchange clients. Every adapter maps the native codes of its exchange
+// into the classes, so that a failure is told apart by its class rather than by its message.
+package errors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// The classes of the exchange failures, matched with errors.Is.
+var (
+	ErrInsufficientBalance = fmt.Errorf("insufficient balance")
+	// ErrInvalidPrecision is an order whose price or qty breaks the tick size, lot step or minimums of the symbol.
+	ErrInvalidPrecision = fmt.Errorf("invalid price or qty precision")
+	ErrRateLimited      = fmt.Errorf("rate limit exceeded")
+	ErrAuth             = fmt.Errorf("authentication failed")
+	ErrSymbolHalted     = fmt.Errorf("symbol is halted")
+	ErrOrderNotFound    = fmt.Errorf("order not found")
+	// ErrTransient is an outage of the exchange or the network, the same request may succeed later.
+	ErrTransient = fmt.Errorf("exchange unavailable")
+	// ErrRejected is a request the exchange refused for a reason its adapter doesn't map.
+	ErrRejected = fmt.Errorf("request rejected")
+	// ErrUnsupported is a request the exchange has no API for, failed by its adapter without sending it.
+	ErrUnsupported = fmt.Errorf("not supported by the exchange")
+)
+
+// classes are matched in order, and labeled in logs and metrics by their names.
+var classes = []error{
+	ErrInsufficientBalance,
+	ErrInvalidPrecision,
+	ErrRateLimited,
+	ErrAuth,
+	ErrSymbolHalted,
+	ErrOrderNotFound,
+	ErrTransient,
+	ErrRejected,
+	ErrUnsupported,
+}
+
+var classNames = map[error]string{
+	ErrInsufficientBalance: "insufficient_balance",
+	ErrInvalidPrecision:    "invalid_precision",
+	ErrRateLimited:         "rate_limited",
+	ErrAuth:                "auth",
+	ErrSymbolHalted:        "symbol_halted",
+	ErrOrderNotFound:       "order_not_found",
+	ErrTransient:           "transient",
+	ErrRejected:            "rejected",
+	ErrUnsupported:         "unsupported",
+}
+
+// ExchangeError is a request the exchange failed, with its native code and message.
+type ExchangeError struct {
+	Exchange  string
+	Operation string
+	// Code is the native code of the exchange, zero when the request failed with an HTTP status only.
+	Code int
+	// Status is the HTTP status of the response.
+	Status int
+	// Message is the native message of the exchange, along with its code.
+	Message string
+	class   error
+}
+
+func (e *ExchangeError) Error() string {
+	if e.Code == 0 {
+		return fmt.Sprintf("%s %s request failed with status: %d. %s", e.Exchange, e.Operation, e.Status, e.class)
+	}
+	return fmt.Sprintf("%s %s request failed with code: %d. %s: %s", e.Exchange, e.Operation, e.Code, e.class, e.Message)
+}
+
+// Unwrap returns the class of the failure.
+func (e *ExchangeError) Unwrap() error {
+	return e.class
+}
+
+// Codes map the native error codes of an exchange into the classes, unmapped codes are ErrRejected.
+type Codes map[int]error
+
+// FromCode returns the failure of a request the exchange answered with an error code.
+func (c Codes) FromCode(exchange string, operation string, code int, message string) error {
+	class, found := c[code]
+	if !found {
+		class = ErrRejected
+	}
+	return &ExchangeError{
+		Exchange:  exchange,
+		Operation: operation,
+		Code:      code,
+		Status:    http.StatusOK,
+		Message:   message,
+		class:     class,
+	}
+}
+
+// FromStatus returns the failure of a request the exchange answered with an HTTP error status.
+func FromStatus(exchange string, operation string, status int) error {
+	class := ErrRejected
+	switch {
+	case status == http.StatusTooManyRequests:
+		class = ErrRateLimited
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		class = ErrAuth
+	case status >= http.StatusInternalServerError:
+		class = ErrTransient
+	}
+	return &ExchangeError{
+		Exchange:  exchange,
+		Operation: operation,
+		Status:    status,
+		class:     class,
+	}
+}
+
+// Classify returns the class of a failure. Timeouts and network failures are ErrTransient, and errors which aren't
+// exchange failures, like an unparsable response, have no class.
+func Classify(err error) error {
+	if err == nil {
+		return nil
+	}
+	for _, class := range classes {
+		if errors.Is(err, class) {
+			return class
+		}
+	}
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || errors.As(err, &netErr) {
+		return ErrTransient
+	}
+	return nil
+}
+
+// ClassName returns the label of the class of a failure, "unknown" when it has none.
+func ClassName(err error) string {
+	if name, found := classNames[Classify(err)]; found {
+		return name
+	}
+	return "unknown"
+}
+
+// IsRejection reports whether the exchange refused the request itself, while the client and the exchange are both
+// working: the order couldn't be funded, broke the symbol rules, targeted a missing order or a halted symbol, or used an
+// API the exchange doesn't have.
+func IsRejection(err error) bool {
+	switch Classify(err) {
+	case ErrInsufficientBalance, ErrInvalidPrecision, ErrSymbolHalted, ErrOrderNotFound, ErrUnsupported:
+		return true
+	default:
+		return false
+	}
+}
@@ -6,4 +6,5 @@ const (
 	Bybit    Exchange = "bybit"
 	Biconomy Exchange = "biconomy"
 	BingX    Exchange = "bingx"
+	Paper    Exchange = "paper"
 )
@@ -0,0 +1,690 @@
+package paper
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/samber/lo"
+
+	exchangeErrors "github.com/imbonda/vmm-bot/pkg/exchanges/errors"
+	"github.com/imbonda/vmm-bot/pkg/models"
+	"github.com/imbonda/vmm-bot/pkg/utils"
+)
+
+const (
+	maxFillsHistory = 1000
+	// maxOrdersHistory is how many orders are kept for lookups, the oldest closed orders are pruned beyond it.
+	maxOrdersHistory = 1000
+)
+
+// marketDataClient is the part of an exchange client the paper exchange reads prices from.
+type marketDataClient interface {
+	GetOrderBook(ctx context.Context, symbol string) (*models.OrderBook, error)
+	GetLastTicker(ctx context.Context, symbol string) (*models.Ticker, error)
+	GetSymbolInfo(ctx context.Context, symbol string) (*models.SymbolInfo, error)
+}
+
+// Client simulates order placement, fills, balances and fees locally against a real market data feed.
+type Client struct {
+	mu           sync.Mutex
+	markets      map[string]Market
+	initialBase  float64
+	initialQuote float64
+	makerFee     float64
+	takerFee     float64
+	accounts     map[string]*account
+	orders       map[string]*order
+	orderIDs     []string
+	clientIDs    map[clientOrderKey]string
+	openOrders   []*order
+	fills        []models.PaperFill
+	nextOrderID  int
+	nextTradeID  int
+	logger       log.Logger
+}
+
+// Market is the market data the orders of a traded symbol are filled against.
+type Market struct {
+	Client marketDataClient
+	// Symbol is the traded symbol in the symbol format of the market data client.
+	Symbol string
+}
+
+type NewClientInput struct {
+	// Markets maps the traded symbols, written BASE/QUOTE, to their market data, each may come from another exchange.
+	Markets      map[string]Market
+	BaseBalance  float64
+	QuoteBalance float64
+	MakerFee     float64
+	TakerFee     float64
+	Logger       log.Logger
+}
+
+type account struct {
+	base         float64
+	quote        float64
+	lockedBase   float64
+	lockedQuote  float64
+	feesPaid     float64
+	fills        int
+	filledVolume float64
+}
+
+type order struct {
+	id          string
+	clientID    string
+	symbol      string
+	action      models.OrderAction
+	price       float64
+	qty         float64
+	filled      float64
+	filledQuote float64
+	status      models.OrderStatus
+}
+
+func (o *order) left() float64 {
+	return o.qty - o.filled
+}
+
+func (o *order) isOpen() bool {
+	return o.status == models.OrderStatusNew || o.status == models.OrderStatusPartiallyFilled
+}
+
+// clientOrderKey identifies an order by its client order id, which is unique per symbol.
+type clientOrderKey struct {
+	symbol   string
+	clientID string
+}
+
+func NewClient(ctx context.Context, input *NewClientInput) (*Client, error) {
+	for symbol, market := range input.Markets {
+		if market.Client == nil {
+			return nil, fmt.Errorf("paper exchange requires a market data client of symbol: %s", symbol)
+		}
+	}
+	api := &Client{
+		markets:      input.Markets,
+		initialBase:  input.BaseBalance,
+		initialQuote: input.QuoteBalance,
+		makerFee:     input.MakerFee,
+		takerFee:     input.TakerFee,
+		accounts:     make(map[string]*account),
+		orders:       make(map[string]*order),
+		clientIDs:    make(map[clientOrderKey]string),
+		logger:       input.Logger,
+	}
+	// The accounts of the traded symbols are funded upfront, so their balances are known before the first order.
+	for symbol := range input.Markets {
+		api.getAccount(symbol)
+	}
+	return api, nil
+}
+
+func (api *Client) GetOrderBook(ctx context.Context, symbol string) (*models.OrderBook, error) {
+	market, err := api.market(symbol)
+	if err != nil {
+		return nil, err
+	}
+	book, err := market.Client.GetOrderBook(ctx, market.Symbol)
+	if err != nil {
+		return nil, err
+	}
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	if err = api.matchResting(symbol, book); err != nil {
+		return nil, err
+	}
+	return &models.OrderBook{
+		Symbol: symbol,
+		Asks:   book.Asks,
+		Bids:   book.Bids,
+	}, nil
+}
+
+func (api *Client) GetLastTicker(ctx context.Context, symbol string) (*models.Ticker, error) {
+	market, err := api.market(symbol)
+	if err != nil {
+		return nil, err
+	}
+	ticker, err := market.Client.GetLastTicker(ctx, market.Symbol)
+	if err != nil {
+		return nil, err
+	}
+	return &models.Ticker{
+		Symbol:    symbol,
+		LastPrice: ticker.LastPrice,
+		BestAsk:   ticker.BestAsk,
+		BestBid:   ticker.BestBid,
+		Volume:    ticker.Volume,
+		Time:      ticker.Time,
+	}, nil
+}
+
+// PlaceOrder fills the order against the resting orders of the account and the top of the book. A market order takes
+// the price of the top of the book, and the qty an IOC or FOK order can't fill right away is cancelled.
+func (api *Client) PlaceOrder(ctx context.Context, input *models.Order) (*models.OrderRecord, error) {
+	orderType, timeInForce := input.Kind()
+	if err := models.ValidateOrderKind(orderType, timeInForce); err != nil {
+		return nil, err
+	}
+	price := 0.0
+	if orderType != models.OrderTypeMarket {
+		var err error
+		if price, err = utils.ParseFloat(input.Price); err != nil || price <= 0 {
+			return nil, fmt.Errorf("paper placeOrder invalid price: %s", input.Price)
+		}
+	}
+	qty, err := utils.ParseFloat(input.Qty)
+	if err != nil || qty <= 0 {
+		return nil, fmt.Errorf("paper placeOrder invalid qty: %s", input.Qty)
+	}
+	market, err := api.market(input.Symbol)
+	if err != nil {
+		return nil, err
+	}
+	book, err := market.Client.GetOrderBook(ctx, market.Symbol)
+	if err != nil {
+		return nil, err
+	}
+	if orderType == models.OrderTypeMarket {
+		topPrice, _, err := topOfBook(book, input.Action)
+		if err != nil {
+			return nil, err
+		}
+		if topPrice <= 0 {
+			return nil, fmt.Errorf("%w: paper placeOrder no liquidity for a market order", exchangeErrors.ErrRejected)
+		}
+		price = topPrice
+	}
+
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	if err = api.matchResting(input.Symbol, book); err != nil {
+		return nil, err
+	}
+	if input.ClientOrderID != "" {
+		if _, found := api.findByClientID(input.Symbol, input.ClientOrderID); found {
+			return nil, fmt.Errorf("%w: paper placeOrder duplicate client order id: %s", exchangeErrors.ErrRejected, input.ClientOrderID)
+		}
+	}
+	acc := api.getAccount(input.Symbol)
+	o := &order{
+		id:       utils.FormatIntToString(api.nextOrderID + 1),
+		clientID: input.ClientOrderID,
+		symbol:   input.Symbol,
+		action:   input.Action,
+		price:    price,
+		qty:      qty,
+		status:   models.OrderStatusNew,
+	}
+	available, err := api.available(o, book)
+	if err != nil {
+		return nil, err
+	}
+	// A limit maker order which would take liquidity, and a FOK order which can't be filled in full, are cancelled
+	// without any fill.
+	expired := (orderType == models.OrderTypeLimitMaker && available > 0) || (timeInForce == models.TimeInForceFOK && available < qty)
+	if !expired {
+		if err = api.lock(acc, input.Action, price, qty); err != nil {
+			return nil, err
+		}
+	}
+	api.nextOrderID++
+	api.addOrder(o)
+	if expired {
+		o.status = models.OrderStatusCancelled
+		return toOrderRecord(o), nil
+	}
+
+	// Orders of the account itself are matched first, just like on a real exchange.
+	api.matchOwnOrders(acc, o)
+	if o.left() > 0 {
+		if err = api.matchBook(acc, o, book, false); err != nil {
+			return nil, err
+		}
+	}
+	switch {
+	case o.left() <= 0:
+	case timeInForce == models.TimeInForceGTC:
+		api.openOrders = append(api.openOrders, o)
+	default:
+		api.cancel(o)
+	}
+	return toOrderRecord(o), nil
+}
+
+func (api *Client) GetOrder(ctx context.Context, symbol string, orderID string) (*models.OrderRecord, error) {
+	if err := api.refresh(ctx, symbol); err != nil {
+		return nil, err
+	}
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	o, found := api.orders[orderID]
+	if !found || o.symbol != symbol {
+		return nil, fmt.Errorf("%w: paper order %s", exchangeErrors.ErrOrderNotFound, orderID)
+	}
+	return toOrderRecord(o), nil
+}
+
+func (api *Client) GetOrderByClientID(ctx context.Context, symbol string, clientOrderID string) (*models.OrderRecord, error) {
+	if err := api.refresh(ctx, symbol); err != nil {
+		return nil, err
+	}
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	o, found := api.findByClientID(symbol, clientOrderID)
+	if !found {
+		return nil, fmt.Errorf("%w: paper order with client order id %s", exchangeErrors.ErrOrderNotFound, clientOrderID)
+	}
+	return toOrderRecord(o), nil
+}
+
+// findByClientID looks up an order of the symbol by its client order id, the caller holds the lock.
+func (api *Client) findByClientID(symbol string, clientOrderID string) (*order, bool) {
+	orderID, found := api.clientIDs[clientOrderKey{symbol: symbol, clientID: clientOrderID}]
+	if !found {
+		return nil, false
+	}
+	o, found := api.orders[orderID]
+	return o, found
+}
+
+// addOrder records the order for lookups, and prunes the oldest closed orders beyond maxOrdersHistory.
+func (api *Client) addOrder(o *order) {
+	api.orders[o.id] = o
+	api.orderIDs = append(api.orderIDs, o.id)
+	if o.clientID != "" {
+		api.clientIDs[clientOrderKey{symbol: o.symbol, clientID: o.clientID}] = o.id
+	}
+	excess := len(api.orders) - maxOrdersHistory
+	if excess <= 0 {
+		return
+	}
+	api.orderIDs = lo.Filter(api.orderIDs, func(orderID string, _ int) bool {
+		old := api.orders[orderID]
+		if excess <= 0 || old.isOpen() || old == o {
+			return true
+		}
+		excess--
+		delete(api.orders, orderID)
+		if old.clientID != "" {
+			delete(api.clientIDs, clientOrderKey{symbol: old.symbol, clientID: old.clientID})
+		}
+		return false
+	})
+}
+
+func (api *Client) ListOpenOrders(ctx context.Context, symbol string) ([]*models.OrderRecord, error) {
+	if err := api.refresh(ctx, symbol); err != nil {
+		return nil, err
+	}
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	return lo.FilterMap(api.openOrders, func(o *order, _ int) (*models.OrderRecord, bool) {
+		return toOrderRecord(o), o.symbol == symbol
+	}), nil
+}
+
+// GetFills reports the paper fills of the symbol since the given time, fees are charged in quote currency.
+func (api *Client) GetFills(ctx context.Context, symbol string, since time.Time) ([]*models.Fill, error) {
+	if err := api.refresh(ctx, symbol); err != nil {
+		return nil, err
+	}
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	pair, _ := models.ParsePair(symbol)
+	return lo.FilterMap(api.fills, func(fill models.PaperFill, i int) (*models.Fill, bool) {
+		return &models.Fill{
+			Symbol:   fill.Symbol,
+			TradeID:  fill.TradeID,
+			OrderID:  fill.OrderID,
+			Action:   fill.Action,
+			Price:    utils.FormatFloatToString(fill.Price, -1),
+			Qty:      utils.FormatFloatToString(fill.Qty, -1),
+			Fee:      utils.FormatFloatToString(fill.Fee, -1),
+			FeeAsset: pair.Quote,
+			Maker:    fill.Maker,
+			Time:     fill.Time,
+		}, fill.Symbol == symbol && !fill.Time.Before(since)
+	}), nil
+}
+
+func (api *Client) CancelOrder(ctx context.Context, symbol string, orderID string) error {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	o, found := api.orders[orderID]
+	if !found || o.symbol != symbol || !toOrderRecord(o).IsOpen() {
+		return fmt.Errorf("%w: paper cancelOrder failed, order not open: %s", exchangeErrors.ErrOrderNotFound, orderID)
+	}
+	api.cancel(o)
+	return nil
+}
+
+func (api *Client) CancelAllOrders(ctx context.Context, symbol string) error {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	for _, o := range api.openOrders {
+		if o.symbol == symbol {
+			api.cancel(o)
+		}
+	}
+	return nil
+}
+
+// GetSymbolInfo reports the trading rules of the market data symbol, the orders are filled against its book.
+func (api *Client) GetSymbolInfo(ctx context.Context, symbol string) (*models.SymbolInfo, error) {
+	market, err := api.market(symbol)
+	if err != nil {
+		return nil, err
+	}
+	info, err := market.Client.GetSymbolInfo(ctx, market.Symbol)
+	if err != nil {
+		return nil, err
+	}
+	paperInfo := *info
+	paperInfo.Symbol = symbol
+	return &paperInfo, nil
+}
+
+// GetBalances sums the balances of the paper accounts per asset, the assets are resolved from the account symbols.
+func (api *Client) GetBalances(_ context.Context) ([]*models.Balance, error) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	free := make(map[string]float64)
+	locked := make(map[string]float64)
+	for symbol, acc := range api.accounts {
+		pair, err := models.ParsePair(symbol)
+		if err != nil {
+			return nil, fmt.Errorf("paper getBalances failed resolving the assets: %w", err)
+		}
+		free[pair.Base] += acc.base - acc.lockedBase
+		locked[pair.Base] += acc.lockedBase
+		free[pair.Quote] += acc.quote - acc.lockedQuote
+		locked[pair.Quote] += acc.lockedQuote
+	}
+	balances := make([]*models.Balance, 0, len(free))
+	for _, asset := range lo.Keys(free) {
+		balances = append(balances, &models.Balance{
+			Asset:  asset,
+			Free:   utils.FormatFloatToString(free[asset], -1),
+			Locked: utils.FormatFloatToString(locked[asset], -1),
+		})
+	}
+	return balances, nil
+}
+
+// GetState returns a snapshot of the paper accounts, open orders and the latest fills.
+func (api *Client) GetState(_ context.Context) (*models.PaperState, error) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	state := &models.PaperState{
+		Accounts:   make([]models.PaperAccount, 0, len(api.accounts)),
+		OpenOrders: lo.Map(api.openOrders, func(o *order, _ int) *models.OrderRecord { return toOrderRecord(o) }),
+		Fills:      append([]models.PaperFill{}, api.fills...),
+	}
+	for symbol, acc := range api.accounts {
+		state.Accounts = append(state.Accounts, models.PaperAccount{
+			Symbol:       symbol,
+			Base:         acc.base,
+			Quote:        acc.quote,
+			LockedBase:   acc.lockedBase,
+			LockedQuote:  acc.lockedQuote,
+			FeesPaid:     acc.feesPaid,
+			Fills:        acc.fills,
+			FilledVolume: acc.filledVolume,
+		})
+	}
+	return state, nil
+}
+
+func (api *Client) market(symbol string) (Market, error) {
+	market, found := api.markets[symbol]
+	if !found {
+		return Market{}, fmt.Errorf("%w: paper has no market data of symbol: %s", exchangeErrors.ErrUnsupported, symbol)
+	}
+	return market, nil
+}
+
+func (api *Client) refresh(ctx context.Context, symbol string) error {
+	market, err := api.market(symbol)
+	if err != nil {
+		return err
+	}
+	book, err := market.Client.GetOrderBook(ctx, market.Symbol)
+	if err != nil {
+		return err
+	}
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	return api.matchResting(symbol, book)
+}
+
+func (api *Client) getAccount(symbol string) *account {
+	acc, found := api.accounts[symbol]
+	if !found {
+		acc = &account{
+			base:  api.initialBase,
+			quote: api.initialQuote,
+		}
+		api.accounts[symbol] = acc
+	}
+	return acc
+}
+
+func (api *Client) lock(acc *account, action models.OrderAction, price, qty float64) error {
+	if action == models.Buy {
+		required := price * qty * (1 + api.takerFee)
+		if acc.quote-acc.lockedQuote < required {
+			return fmt.Errorf("%w: paper placeOrder rejected, quote required %f, available %f", exchangeErrors.ErrInsufficientBalance, required, acc.quote-acc.lockedQuote)
+		}
+		acc.lockedQuote += required
+		return nil
+	}
+	if acc.base-acc.lockedBase < qty {
+		return fmt.Errorf("%w: paper placeOrder rejected, base required %f, available %f", exchangeErrors.ErrInsufficientBalance, qty, acc.base-acc.lockedBase)
+	}
+	acc.lockedBase += qty
+	return nil
+}
+
+func (api *Client) unlock(acc *account, o *order, qty float64) {
+	if o.action == models.Buy {
+		acc.lockedQuote = math.Max(acc.lockedQuote-o.price*qty*(1+api.takerFee), 0)
+	} else {
+		acc.lockedBase = math.Max(acc.lockedBase-qty, 0)
+	}
+}
+
+// matchOwnOrders fills the order against the account's resting orders, which act as makers.
+func (api *Client) matchOwnOrders(acc *account, taker *order) {
+	for _, maker := range api.openOrders {
+		if taker.left() <= 0 {
+			break
+		}
+		if maker.symbol != taker.symbol || maker.action == taker.action || !crosses(taker, maker.price) {
+			continue
+		}
+		qty := math.Min(taker.left(), maker.left())
+		api.fill(acc, maker, maker.price, qty, true)
+		api.fill(acc, taker, maker.price, qty, false)
+	}
+	api.removeClosed()
+}
+
+// available returns the qty the order would fill right away, against the resting orders of the account and the top
+// of the book.
+func (api *Client) available(o *order, book *models.OrderBook) (float64, error) {
+	qty := 0.0
+	for _, maker := range api.openOrders {
+		if maker.symbol == o.symbol && maker.action != o.action && crosses(o, maker.price) {
+			qty += maker.left()
+		}
+	}
+	topPrice, topQty, err := topOfBook(book, o.action)
+	if err != nil {
+		return 0, err
+	}
+	if topQty > 0 && crosses(o, topPrice) {
+		qty += topQty
+	}
+	return qty, nil
+}
+
+// matchBook fills the order against the top of the real order book.
+func (api *Client) matchBook(acc *account, o *order, book *models.OrderBook, maker bool) error {
+	topPrice, topQty, err := topOfBook(book, o.action)
+	if err != nil {
+		return err
+	}
+	if topQty <= 0 || !crosses(o, topPrice) {
+		return nil
+	}
+	levels := book.Asks
+	if o.action == models.Sell {
+		levels = book.Bids
+	}
+	qty := math.Min(o.left(), topQty)
+	// Resting orders are filled at their own price, incoming orders at the book's price.
+	fillPrice := topPrice
+	if maker {
+		fillPrice = o.price
+	}
+	api.fill(acc, o, fillPrice, qty, maker)
+	// Consume the liquidity, so several resting orders don't fill against the same quantity.
+	levels[0][1] = utils.FormatFloatToString(topQty-qty, -1)
+	return nil
+}
+
+// matchResting fills the resting orders which the market has crossed since they were placed.
+func (api *Client) matchResting(symbol string, book *models.OrderBook) error {
+	acc := api.getAccount(symbol)
+	for _, o := range api.openOrders {
+		if o.symbol != symbol {
+			continue
+		}
+		if err := api.matchBook(acc, o, book, true); err != nil {
+			return err
+		}
+	}
+	api.removeClosed()
+	return nil
+}
+
+func (api *Client) fill(acc *account, o *order, price, qty float64, maker bool) {
+	feeRate := api.takerFee
+	if maker {
+		feeRate = api.makerFee
+	}
+	notional := price * qty
+	fee := notional * feeRate
+	api.unlock(acc, o, qty)
+	if o.action == models.Buy {
+		acc.base += qty
+		acc.quote -= notional + fee
+	} else {
+		acc.base -= qty
+		acc.quote += notional - fee
+	}
+	acc.feesPaid += fee
+	acc.fills++
+	acc.filledVolume += qty
+
+	o.filled += qty
+	o.filledQuote += notional
+	if o.left() <= 0 {
+		o.status = models.OrderStatusFilled
+	} else {
+		o.status = models.OrderStatusPartiallyFilled
+	}
+
+	api.nextTradeID++
+	api.fills = append(api.fills, models.PaperFill{
+		TradeID: utils.FormatIntToString(api.nextTradeID),
+		OrderID: o.id,
+		Symbol:  o.symbol,
+		Action:  o.action,
+		Price:   price,
+		Qty:     qty,
+		Fee:     fee,
+		Maker:   maker,
+		Time:    time.Now(),
+	})
+	if len(api.fills) > maxFillsHistory {
+		api.fills = api.fills[len(api.fills)-maxFillsHistory:]
+	}
+	level.Debug(api.logger).Log(
+		"msg", "paper fill",
+		"orderId", o.id,
+		"symbol", o.symbol,
+		"action", o.action,
+		"price", price,
+		"qty", qty,
+		"fee", fee,
+		"maker", maker,
+	)
+}
+
+func (api *Client) cancel(o *order) {
+	api.unlock(api.getAccount(o.symbol), o, o.left())
+	o.status = models.OrderStatusCancelled
+	api.removeClosed()
+}
+
+func (api *Client) removeClosed() {
+	api.openOrders = lo.Filter(api.openOrders, func(o *order, _ int) bool {
+		return o.isOpen()
+	})
+}
+
+// topOfBook returns the top level of the book side an order of the action is filled against, zero when it's empty.
+func topOfBook(book *models.OrderBook, action models.OrderAction) (float64, float64, error) {
+	levels := book.Asks
+	if action == models.Sell {
+		levels = book.Bids
+	}
+	if len(levels) == 0 {
+		return 0, 0, nil
+	}
+	if len(levels[0]) < 2 {
+		return 0, 0, fmt.Errorf("invalid order book")
+	}
+	topPrice, err := utils.ParseFloat(levels[0][0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed parse top of book price: %s", levels[0][0])
+	}
+	topQty, err := utils.ParseFloat(levels[0][1])
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed parse top of book qty: %s", levels[0][1])
+	}
+	return topPrice, topQty, nil
+}
+
+func crosses(o *order, price float64) bool {
+	if o.action == models.Buy {
+		return price <= o.price
+	}
+	return price >= o.price
+}
+
+func toOrderRecord(o *order) *models.OrderRecord {
+	avgPrice := 0.0
+	if o.filled > 0 {
+		avgPrice = o.filledQuote / o.filled
+	}
+	return &models.OrderRecord{
+		Symbol:        o.symbol,
+		OrderID:       o.id,
+		ClientOrderID: o.clientID,
+		Action:        o.action,
+		Price:         utils.FormatFloatToString(o.price, -1),
+		Qty:           utils.FormatFloatToString(o.qty, -1),
+		FilledQty:     utils.FormatFloatToString(o.filled, -1),
+		AvgPrice:      utils.FormatFloatToString(avgPrice, -1),
+		Status:        o.status,
+	}
+}
@@ -0,0 +1,14 @@
+package paper
+
+import (
+	"github.com/imbonda/vmm-bot/pkg/exchanges"
+	"github.com/imbonda/vmm-bot/pkg/models"
+)
+
+// Paper symbols are the canonical BASE/QUOTE pairs, the market data symbols are mapped by the client input.
+func init() {
+	exchanges.RegisterSymbolFormat(exchanges.Paper, exchanges.SymbolFormat{
+		Format: models.Pair.String,
+		Parse:  models.ParsePair,
+	})
+}
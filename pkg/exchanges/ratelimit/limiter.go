@@ -0,0 +1,231 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+
+	exchangeErrors "github.com/imbonda/vmm-bot/pkg/exchanges/errors"
+)
+
+// ErrRateLimited is a request the limiter failed without sending it, it's an exchangeErrors.ErrRateLimited.
+var ErrRateLimited = fmt.Errorf("%w client side", exchangeErrors.ErrRateLimited)
+
+// Class groups the endpoints of an exchange which share a rate limit.
+type Class string
+
+const (
+	// Public endpoints serve market data, and are usually limited per IP.
+	Public Class = "public"
+	// Query endpoints read the account, its orders, fills and balances.
+	Query Class = "query"
+	// Order endpoints place and cancel orders.
+	Order Class = "order"
+)
+
+// Budget allows Limit request weights per Interval.
+type Budget struct {
+	Limit    float64
+	Interval time.Duration
+}
+
+// Endpoint is the class of a request, and the weight it takes from the budget of its class.
+type Endpoint struct {
+	Class  Class
+	Weight float64
+}
+
+// Rules are the rate limits of an exchange, every adapter provides its own.
+type Rules struct {
+	Budgets map[Class]Budget
+	// Classify resolves the endpoint of a request by its method and path.
+	Classify func(request *http.Request) Endpoint
+}
+
+// Limiter keeps a token bucket per endpoint class of an exchange. A request waits for its weight up to the max wait,
+// or fails with ErrRateLimited without reaching the exchange.
+type Limiter struct {
+	exchange string
+	classify func(request *http.Request) Endpoint
+	buckets  map[Class]*bucket
+	maxWait  time.Duration
+	logger   log.Logger
+}
+
+type NewLimiterInput struct {
+	Exchange string
+	Rules    Rules
+	// Ratio is the share of the budgets the limiter allows, leaving the rest to other clients of the same key or IP.
+	Ratio float64
+	// MaxWait is how long a request may wait for its budget, zero fails it right away.
+	MaxWait time.Duration
+	Logger  log.Logger
+}
+
+func NewLimiter(input *NewLimiterInput) (*Limiter, error) {
+	if input.Ratio <= 0 || input.Ratio > 1 {
+		return nil, fmt.Errorf("invalid rate limit ratio: %f, expected above 0 and up to 1", input.Ratio)
+	}
+	if input.MaxWait < 0 {
+		return nil, fmt.Errorf("invalid rate limit max wait: %s", input.MaxWait)
+	}
+	buckets := make(map[Class]*bucket, len(input.Rules.Budgets))
+	for class, budget := range input.Rules.Budgets {
+		if budget.Limit <= 0 || budget.Interval <= 0 {
+			return nil, fmt.Errorf("invalid %s rate limit budget of %s: %v", class, input.Exchange, budget)
+		}
+		capacity := budget.Limit * input.Ratio
+		buckets[class] = &bucket{
+			capacity: capacity,
+			rate:     capacity / budget.Interval.Seconds(),
+			tokens:   capacity,
+			updated:  time.Now(),
+		}
+	}
+	return &Limiter{
+		exchange: input.Exchange,
+		classify: input.Rules.Classify,
+		buckets:  buckets,
+		maxWait:  input.MaxWait,
+		logger:   input.Logger,
+	}, nil
+}
+
+// Exchange returns the name of the exchange the limiter guards.
+func (l *Limiter) Exchange() string {
+	return l.exchange
+}
+
+// Classes returns the endpoint classes which have a budget.
+func (l *Limiter) Classes() []Class {
+	classes := make([]Class, 0, len(l.buckets))
+	for class := range l.buckets {
+		classes = append(classes, class)
+	}
+	return classes
+}
+
+// Remaining returns the weight the class may still take without waiting.
+func (l *Limiter) Remaining(class Class) float64 {
+	if b, found := l.buckets[class]; found {
+		return b.remaining()
+	}
+	return 0
+}
+
+// Throttled counts the requests of the class which waited for their budget.
+func (l *Limiter) Throttled(class Class) int64 {
+	if b, found := l.buckets[class]; found {
+		return b.throttled.Load()
+	}
+	return 0
+}
+
+// Rejected counts the requests of the class which failed with ErrRateLimited.
+func (l *Limiter) Rejected(class Class) int64 {
+	if b, found := l.buckets[class]; found {
+		return b.rejected.Load()
+	}
+	return 0
+}
+
+// Wait takes the weight of the request from the budget of its class, waiting for it when needed.
+func (l *Limiter) Wait(ctx context.Context, request *http.Request) error {
+	endpoint := l.classify(request)
+	b, found := l.buckets[endpoint.Class]
+	if !found {
+		return nil
+	}
+	delay, remaining, ok := b.reserve(endpoint.Weight, l.maxWait)
+	if !ok {
+		b.rejected.Add(1)
+		level.Warn(l.logger).Log("msg", "rate limit exceeded", "exchange", l.exchange, "class", endpoint.Class, "path", request.URL.Path, "remaining", remaining)
+		return fmt.Errorf("%w: %s %s budget of %s, remaining %.2f", ErrRateLimited, l.exchange, endpoint.Class, request.URL.Path, remaining)
+	}
+	if delay <= 0 {
+		return nil
+	}
+	b.throttled.Add(1)
+	level.Debug(l.logger).Log("msg", "waiting for rate limit budget", "exchange", l.exchange, "class", endpoint.Class, "path", request.URL.Path, "delay", delay, "remaining", remaining)
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		b.release(endpoint.Weight)
+		return ctx.Err()
+	}
+}
+
+// Transport waits for the budget of every request before sending it through the next transport,
+// http.DefaultTransport when nil.
+func (l *Limiter) Transport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &transport{limiter: l, next: next}
+}
+
+type transport struct {
+	limiter *Limiter
+	next    http.RoundTripper
+}
+
+func (t *transport) RoundTrip(request *http.Request) (*http.Response, error) {
+	if err := t.limiter.Wait(request.Context(), request); err != nil {
+		return nil, err
+	}
+	return t.next.RoundTrip(request)
+}
+
+// bucket refills its capacity over the interval of its budget. Reservations may take it below zero, and the requests
+// which made them wait until it's refilled back.
+type bucket struct {
+	mu        sync.Mutex
+	capacity  float64
+	rate      float64
+	tokens    float64
+	updated   time.Time
+	throttled atomic.Int64
+	rejected  atomic.Int64
+}
+
+func (b *bucket) refill(now time.Time) {
+	b.tokens = min(b.capacity, b.tokens+now.Sub(b.updated).Seconds()*b.rate)
+	b.updated = now
+}
+
+// reserve takes the weight and returns how long to wait for it, unless that's longer than the max wait.
+func (b *bucket) reserve(weight float64, maxWait time.Duration) (time.Duration, float64, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refill(time.Now())
+	delay := time.Duration((weight - b.tokens) / b.rate * float64(time.Second))
+	if delay > maxWait {
+		return 0, b.tokens, false
+	}
+	b.tokens -= weight
+	return delay, b.tokens, true
+}
+
+// release gives back the weight of a reservation which was cancelled while waiting.
+func (b *bucket) release(weight float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refill(time.Now())
+	b.tokens = min(b.capacity, b.tokens+weight)
+}
+
+func (b *bucket) remaining() float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refill(time.Now())
+	return max(0, b.tokens)
+}
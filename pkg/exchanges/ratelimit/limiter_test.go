@@ -0,0 +1,54 @@
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCancelledWaitReturnsPromptly(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	limiter, err := NewLimiter(&NewLimiterInput{
+		Exchange: "test",
+		Rules: Rules{
+			Budgets:  map[Class]Budget{Order: {Limit: 1, Interval: 10 * time.Second}},
+			Classify: func(*http.Request) Endpoint { return Endpoint{Class: Order, Weight: 1} },
+		},
+		Ratio:   1,
+		MaxWait: 10 * time.Second,
+		Logger:  log.NewNopLogger(),
+	})
+	require.NoError(t, err)
+	client := &http.Client{Transport: limiter.Transport(nil)}
+
+	send := func(ctx context.Context) error {
+		request, err := http.NewRequestWithContext(ctx, http.MethodPost, server.URL, nil)
+		require.NoError(t, err)
+		response, err := client.Do(request)
+		if err == nil {
+			response.Body.Close()
+		}
+		return err
+	}
+	require.NoError(t, send(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err = send(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, int64(1), limiter.Throttled(Order))
+	// The weight of the cancelled request is given back to the budget.
+	assert.InDelta(t, 0, limiter.Remaining(Order), 0.05)
+}
@@ -0,0 +1,128 @@
+package resilience
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+
+	exchangeErrors "github.com/imbonda/vmm-bot/pkg/exchanges/errors"
+	"github.com/imbonda/vmm-bot/pkg/models"
+)
+
+// ErrCircuitOpen is a request the breaker failed without sending it, it's an exchangeErrors.ErrTransient.
+var ErrCircuitOpen = fmt.Errorf("%w, circuit breaker is open", exchangeErrors.ErrTransient)
+
+// Breaker opens after a number of failed requests in a row, and fails the requests of its exchange without sending them.
+// Once the cooldown passes it lets a single trial request through, which closes it on success and opens it again on failure.
+type Breaker struct {
+	exchange  string
+	threshold int
+	cooldown  time.Duration
+	logger    log.Logger
+	mu        sync.Mutex
+	state     models.CircuitState
+	failures  int
+	openedAt  time.Time
+	opened    int64
+}
+
+type NewBreakerInput struct {
+	Exchange string
+	// Threshold is how many failed requests in a row open the circuit.
+	Threshold int
+	// Cooldown is how long the circuit stays open before a trial request.
+	Cooldown time.Duration
+	Logger   log.Logger
+}
+
+func NewBreaker(input *NewBreakerInput) (*Breaker, error) {
+	if input.Threshold <= 0 || input.Cooldown <= 0 {
+		return nil, fmt.Errorf(
+			"invalid circuit breaker, expected positive threshold and cooldown. threshold: %d, cooldown: %s",
+			input.Threshold,
+			input.Cooldown,
+		)
+	}
+	return &Breaker{
+		exchange:  input.Exchange,
+		threshold: input.Threshold,
+		cooldown:  input.Cooldown,
+		logger:    input.Logger,
+		state:     models.CircuitClosed,
+	}, nil
+}
+
+// Allow fails with ErrCircuitOpen while the circuit is open, or while the trial request of a half open circuit is sent.
+func (b *Breaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case models.CircuitOpen:
+		if time.Since(b.openedAt) < b.cooldown {
+			return fmt.Errorf("%w: %s, retrying after %s", ErrCircuitOpen, b.exchange, b.openedAt.Add(b.cooldown).Format(time.RFC3339))
+		}
+		b.state = models.CircuitHalfOpen
+		level.Info(b.logger).Log("msg", "circuit breaker half open, sending a trial request", "exchange", b.exchange)
+		return nil
+	case models.CircuitHalfOpen:
+		return fmt.Errorf("%w: %s, waiting for the trial request", ErrCircuitOpen, b.exchange)
+	default:
+		return nil
+	}
+}
+
+// Record counts the outcome of an allowed request.
+func (b *Breaker) Record(success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if success {
+		if b.state != models.CircuitClosed {
+			level.Info(b.logger).Log("msg", "circuit breaker closed", "exchange", b.exchange)
+		}
+		b.state, b.failures = models.CircuitClosed, 0
+		return
+	}
+	b.failures++
+	if b.state == models.CircuitHalfOpen || b.failures >= b.threshold {
+		if b.state == models.CircuitClosed {
+			b.opened++
+		}
+		b.state, b.openedAt = models.CircuitOpen, time.Now()
+		level.Warn(b.logger).Log("msg", "circuit breaker open", "exchange", b.exchange, "failures", b.failures, "cooldown", b.cooldown)
+	}
+}
+
+// abort gives the trial of a half open circuit to the next request, when the trial was cancelled before its outcome.
+func (b *Breaker) abort() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == models.CircuitHalfOpen {
+		b.state = models.CircuitOpen
+	}
+}
+
+// Status returns the state of the circuit.
+func (b *Breaker) Status() models.CircuitStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	status := models.CircuitStatus{
+		Exchange:            b.exchange,
+		State:               b.state,
+		ConsecutiveFailures: b.failures,
+	}
+	if b.state != models.CircuitClosed {
+		openedAt := b.openedAt
+		status.OpenedAt = &openedAt
+	}
+	return status
+}
+
+// Opened counts how many times the circuit opened after being closed.
+func (b *Breaker) Opened() int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.opened
+}
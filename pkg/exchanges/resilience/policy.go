@@ -0,0 +1,229 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+
+	"github.com/imbonda/vmm-bot/pkg/exchanges/ratelimit"
+)
+
+// Rules tell which requests of an exchange may be sent again, every adapter provides its own.
+type Rules struct {
+	// Idempotent reports whether the request may be sent again after a failure: reads, and writes the exchange
+	// deduplicates, like orders carrying a client order ID.
+	Idempotent func(request *http.Request, body []byte) bool
+	// MaxElapsed bounds the time from the first attempt of a request to its last retry. The retries resend the
+	// timestamp and signature of the first attempt, which the exchange rejects once they fall out of its receive window.
+	// Zero doesn't bound it.
+	MaxElapsed time.Duration
+}
+
+// Policy retries the idempotent requests of an exchange which failed on the network or the server side, with an
+// exponential backoff and jitter, and fails every request without sending it while its circuit breaker is open.
+type Policy struct {
+	exchange   string
+	idempotent func(request *http.Request, body []byte) bool
+	maxElapsed time.Duration
+	maxRetries int
+	backoffMin time.Duration
+	backoffMax time.Duration
+	breaker    *Breaker
+	logger     log.Logger
+	retries    atomic.Int64
+}
+
+type NewPolicyInput struct {
+	Exchange string
+	Rules    Rules
+	// MaxRetries is how many times a failed idempotent request is sent again, zero disables the retries.
+	MaxRetries int
+	// BackoffMin is the delay before the first retry, doubled on every retry up to BackoffMax.
+	BackoffMin time.Duration
+	BackoffMax time.Duration
+	// Breaker is the circuit breaker of the exchange, nil disables it.
+	Breaker *Breaker
+	Logger  log.Logger
+}
+
+func NewPolicy(input *NewPolicyInput) (*Policy, error) {
+	if input.MaxRetries < 0 || input.BackoffMin < 0 || input.BackoffMax < input.BackoffMin {
+		return nil, fmt.Errorf(
+			"invalid retry policy, expected non negative retries and backoff, min not above max. retries: %d, backoff: %s - %s",
+			input.MaxRetries,
+			input.BackoffMin,
+			input.BackoffMax,
+		)
+	}
+	return &Policy{
+		exchange:   input.Exchange,
+		idempotent: input.Rules.Idempotent,
+		maxElapsed: input.Rules.MaxElapsed,
+		maxRetries: input.MaxRetries,
+		backoffMin: input.BackoffMin,
+		backoffMax: input.BackoffMax,
+		breaker:    input.Breaker,
+		logger:     input.Logger,
+	}, nil
+}
+
+// Exchange returns the name of the exchange of the policy.
+func (p *Policy) Exchange() string {
+	return p.exchange
+}
+
+// Breaker returns the circuit breaker of the exchange, nil when it's disabled or there's no policy.
+func (p *Policy) Breaker() *Breaker {
+	if p == nil {
+		return nil
+	}
+	return p.breaker
+}
+
+// Retries counts the requests sent again after a failure.
+func (p *Policy) Retries() int64 {
+	return p.retries.Load()
+}
+
+// Transport applies the policy to every request before sending it through the next transport,
+// http.DefaultTransport when nil.
+func (p *Policy) Transport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &transport{policy: p, next: next}
+}
+
+type transport struct {
+	policy *Policy
+	next   http.RoundTripper
+}
+
+func (t *transport) RoundTrip(request *http.Request) (*http.Response, error) {
+	p := t.policy
+	if p.breaker != nil {
+		if err := p.breaker.Allow(); err != nil {
+			return nil, err
+		}
+	}
+	retries := 0
+	if p.maxRetries > 0 && p.isIdempotent(request) {
+		retries = p.maxRetries
+	}
+	start := time.Now()
+	for attempt := 0; ; attempt++ {
+		response, err := t.next.RoundTrip(request)
+		failed := isFailure(response, err)
+		delay := p.backoff(attempt)
+		if !failed || attempt >= retries || !canRetry(request, err) || !p.withinMaxElapsed(start, delay) {
+			p.record(request, response, err, failed)
+			return response, err
+		}
+		level.Debug(p.logger).Log("msg", "retrying exchange request", "exchange", p.exchange, "path", request.URL.Path, "attempt", attempt+1, "delay", delay, "err", failureReason(response, err))
+		if response != nil {
+			_, _ = io.Copy(io.Discard, response.Body)
+			response.Body.Close()
+		}
+		if request, err = rewind(request); err != nil {
+			p.record(request, nil, err, true)
+			return nil, err
+		}
+		select {
+		case <-time.After(delay):
+		case <-request.Context().Done():
+			p.record(request, nil, request.Context().Err(), true)
+			return nil, request.Context().Err()
+		}
+		p.retries.Add(1)
+	}
+}
+
+// record counts the outcome of the request in the circuit breaker, cancelled requests and rate limited ones, which
+// never reached the exchange, don't count.
+func (p *Policy) record(request *http.Request, response *http.Response, err error, failed bool) {
+	if p.breaker == nil {
+		return
+	}
+	if failed && (errors.Is(err, context.Canceled) || errors.Is(err, ratelimit.ErrRateLimited)) {
+		p.breaker.abort()
+		return
+	}
+	if failed {
+		level.Debug(p.logger).Log("msg", "exchange request failed", "exchange", p.exchange, "path", request.URL.Path, "err", failureReason(response, err))
+	}
+	p.breaker.Record(!failed)
+}
+
+// withinMaxElapsed tells whether a retry after the delay would still be sent within the max elapsed time.
+func (p *Policy) withinMaxElapsed(start time.Time, delay time.Duration) bool {
+	return p.maxElapsed <= 0 || time.Since(start)+delay <= p.maxElapsed
+}
+
+func (p *Policy) isIdempotent(request *http.Request) bool {
+	var body []byte
+	if request.Body != nil && request.Body != http.NoBody {
+		if request.GetBody == nil {
+			return false
+		}
+		reader, err := request.GetBody()
+		if err != nil {
+			return false
+		}
+		defer reader.Close()
+		if body, err = io.ReadAll(reader); err != nil {
+			return false
+		}
+	}
+	return p.idempotent(request, body)
+}
+
+// backoff doubles the delay on every attempt up to the max, and picks a random delay in its upper half.
+func (p *Policy) backoff(attempt int) time.Duration {
+	delay := p.backoffMin << attempt
+	if delay > p.backoffMax || delay <= 0 {
+		delay = p.backoffMax
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// isFailure tells whether the request failed on the network or the server side, the exchange rejections are responses.
+func isFailure(response *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return response.StatusCode >= http.StatusInternalServerError || response.StatusCode == http.StatusTooManyRequests
+}
+
+func canRetry(request *http.Request, err error) bool {
+	return request.Context().Err() == nil && !errors.Is(err, ratelimit.ErrRateLimited)
+}
+
+func failureReason(response *http.Response, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return response.Status
+}
+
+// rewind clones the request with a fresh body to send it again.
+func rewind(request *http.Request) (*http.Request, error) {
+	clone := request.Clone(request.Context())
+	if request.GetBody != nil {
+		body, err := request.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		clone.Body = body
+	} else if request.Body != nil && request.Body != http.NoBody {
+		return nil, fmt.Errorf("request body of %s can't be sent again", request.URL.Path)
+	}
+	return clone, nil
+}
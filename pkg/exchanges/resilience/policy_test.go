@@ -0,0 +1,47 @@
+package resilience
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetriesStopAtMaxElapsed(t *testing.T) {
+	var attempts atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	policy, err := NewPolicy(&NewPolicyInput{
+		Exchange: "test",
+		Rules: Rules{
+			Idempotent: func(*http.Request, []byte) bool { return true },
+			MaxElapsed: 250 * time.Millisecond,
+		},
+		MaxRetries: 10,
+		BackoffMin: 100 * time.Millisecond,
+		BackoffMax: 100 * time.Millisecond,
+		Logger:     log.NewNopLogger(),
+	})
+	require.NoError(t, err)
+	client := &http.Client{Transport: policy.Transport(nil)}
+
+	start := time.Now()
+	response, err := client.Get(server.URL)
+	require.NoError(t, err)
+	response.Body.Close()
+
+	assert.Equal(t, http.StatusServiceUnavailable, response.StatusCode)
+	assert.Less(t, time.Since(start), 250*time.Millisecond+50*time.Millisecond)
+	assert.GreaterOrEqual(t, attempts.Load(), int64(2))
+	assert.Less(t, attempts.Load(), int64(11))
+	assert.Equal(t, attempts.Load()-1, policy.Retries())
+}
@@ -0,0 +1,109 @@
+package sim
+
+import (
+	"fmt"
+	"maps"
+
+	exchangeErrors "github.com/imbonda/vmm-bot/pkg/exchanges/errors"
+	"github.com/imbonda/vmm-bot/pkg/models"
+)
+
+var ErrInsufficientBalance = exchangeErrors.ErrInsufficientBalance
+
+type Balance struct {
+	Asset  string
+	Free   float64
+	Locked float64
+}
+
+// account holds the balances of an owner, open orders lock the quote they may spend or the base they may sell.
+type account struct {
+	free   map[string]float64
+	locked map[string]float64
+}
+
+// getAccount funds the accounts on first use, every asset of the markets gets the initial balance unless overridden.
+func (e *Engine) getAccount(owner string) *account {
+	acc, found := e.accounts[owner]
+	if !found {
+		acc = &account{
+			free:   make(map[string]float64),
+			locked: make(map[string]float64),
+		}
+		for symbol := range e.books {
+			pair, _ := parsePair(symbol)
+			acc.free[pair.Base], acc.free[pair.Quote] = e.balance, e.balance
+		}
+		maps.Copy(acc.free, e.balances)
+		e.accounts[owner] = acc
+	}
+	return acc
+}
+
+// lock reserves the funds of a new order, the house liquidity is unlimited.
+func (e *Engine) lock(order *Order) error {
+	if order.Owner == houseOwner {
+		return nil
+	}
+	asset, amount := lockedFunds(order, order.Qty)
+	acc := e.getAccount(order.Owner)
+	if acc.free[asset] < amount {
+		return fmt.Errorf("%w: required %s %f, available %f", ErrInsufficientBalance, asset, amount, acc.free[asset])
+	}
+	acc.free[asset] -= amount
+	acc.locked[asset] += amount
+	return nil
+}
+
+// unlock releases the funds of the order qty which is left unfilled.
+func (e *Engine) unlock(order *Order, qty float64) {
+	if order.Owner == houseOwner {
+		return
+	}
+	asset, amount := lockedFunds(order, qty)
+	acc := e.getAccount(order.Owner)
+	amount = min(amount, acc.locked[asset])
+	acc.locked[asset] -= amount
+	acc.free[asset] += amount
+}
+
+// settle moves the funds of a fill, the fee is deducted from the received asset.
+func (e *Engine) settle(order *Order, fill Fill) {
+	if order.Owner == houseOwner {
+		return
+	}
+	pair, _ := parsePair(order.Symbol)
+	acc := e.getAccount(order.Owner)
+	spentAsset, spent := lockedFunds(order, fill.Qty)
+	acc.locked[spentAsset] = max(acc.locked[spentAsset]-spent, 0)
+	if order.Action == models.Buy {
+		// Buys lock their limit price, the improvement over the fill price is released.
+		acc.free[pair.Quote] += spent - fill.Price*fill.Qty
+		acc.free[pair.Base] += fill.Qty - fill.Fee
+	} else {
+		acc.free[pair.Quote] += fill.Price*fill.Qty - fill.Fee
+	}
+}
+
+func lockedFunds(order *Order, qty float64) (string, float64) {
+	pair, _ := parsePair(order.Symbol)
+	if order.Action == models.Buy {
+		return pair.Quote, order.Price * qty
+	}
+	return pair.Base, qty
+}
+
+func (e *Engine) Balances(owner string) []Balance {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	acc := e.getAccount(owner)
+	balances := make([]Balance, 0, len(acc.free))
+	for asset, free := range acc.free {
+		balances = append(balances, Balance{
+			Asset:  asset,
+			Free:   free,
+			Locked: acc.locked[asset],
+		})
+	}
+	return balances
+}
@@ -0,0 +1,341 @@
+package sim
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/samber/lo"
+
+	biconomyModels "github.com/imbonda/vmm-bot/pkg/exchanges/biconomy/models"
+	"github.com/imbonda/vmm-bot/pkg/models"
+	"github.com/imbonda/vmm-bot/pkg/utils"
+)
+
+const (
+	biconomyOwnerPrefix = "biconomy:"
+
+	biconomySuccessCode       = 0
+	biconomyParamsErrorCode   = 10001
+	biconomyAuthErrorCode     = 10007
+	biconomyOrderNotFoundCode = 10012
+
+	biconomyAsk = 1
+	biconomyBid = 2
+)
+
+func (s *Server) registerBiconomyRoutes(router *gin.Engine) {
+	router.GET("/ws", s.handleBiconomyStream)
+	v1 := router.Group("/api/v1")
+	{
+		v1.GET("/depth", s.handleBiconomyOrderBook)
+		v1.GET("/tickers", s.handleBiconomyTickers)
+		v1.GET("/exchangeInfo", s.handleBiconomyExchangeInfo)
+	}
+	private := v1.Group("/private", s.biconomyAuth)
+	{
+		private.POST("/trade/limit", s.handleBiconomyPlaceOrder)
+		private.POST("/trade/cancel", s.handleBiconomyCancelOrder)
+		private.POST("/trade/cancel_batch", s.handleBiconomyBatchCancelOrders)
+		private.POST("/order/pending", s.handleBiconomyPendingOrders)
+		private.POST("/order/pending/detail", s.handleBiconomyPendingOrder)
+		private.POST("/user", s.handleBiconomyAssets)
+		private.POST("/order/finished", s.handleBiconomyFinishedOrders)
+		private.POST("/order/finished/detail", s.handleBiconomyFinishedOrder)
+	}
+}
+
+// biconomyAuth validates the upper-cased MD5 signature of the sorted form data and the secret key.
+func (s *Server) biconomyAuth(c *gin.Context) {
+	values, err := readForm(c)
+	if err != nil {
+		biconomyRespond(c, biconomyParamsErrorCode, err.Error(), nil)
+		c.Abort()
+		return
+	}
+	if values.Get("api_key") != s.biconomy.APIKey {
+		biconomyRespond(c, biconomyAuthErrorCode, "invalid api key", nil)
+		c.Abort()
+		return
+	}
+	if values.Get("sign") != biconomySignature(withoutParam(values, "sign"), s.biconomy.APISecret) {
+		biconomyRespond(c, biconomyAuthErrorCode, "invalid signature", nil)
+		c.Abort()
+		return
+	}
+	c.Set(paramsKey, values)
+	c.Next()
+}
+
+func biconomyRespond(c *gin.Context, code int, msg string, result any) {
+	c.JSON(http.StatusOK, gin.H{
+		"code":    code,
+		"message": msg,
+		"result":  result,
+	})
+}
+
+func biconomyOwner(c *gin.Context) string {
+	return biconomyOwnerPrefix + params(c).Get("api_key")
+}
+
+func (s *Server) handleBiconomyOrderBook(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("size", "100"))
+	bids, asks, err := s.engine.Depth(c.Query("symbol"), limit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, &biconomyModels.RawOrderBook{
+		Asks: formatLevels(asks),
+		Bids: formatLevels(bids),
+	})
+}
+
+func (s *Server) handleBiconomyTickers(c *gin.Context) {
+	tickers := lo.Map(s.engine.Tickers(), func(ticker Ticker, _ int) biconomyModels.RawTicker {
+		return biconomyModels.RawTicker{
+			Symbol:    toBiconomySymbol(ticker.Symbol),
+			LastPrice: formatFloat(ticker.LastPrice),
+			Ask:       formatFloat(ticker.Ask),
+			Bid:       formatFloat(ticker.Bid),
+		}
+	})
+	c.JSON(http.StatusOK, &biconomyModels.RawTickersResult{
+		Tickers: tickers,
+	})
+}
+
+// handleBiconomyExchangeInfo reports the tick size and lot step as precisions, biconomy doesn't report order minimums.
+func (s *Server) handleBiconomyExchangeInfo(c *gin.Context) {
+	infos := lo.Map(s.engine.AllRules(), func(rules SymbolRules, _ int) biconomyModels.RawSymbolInfo {
+		pair, _ := parsePair(rules.Symbol)
+		status := biconomyModels.SymbolStatusTrading
+		if rules.Halted {
+			status = "halt"
+		}
+		return biconomyModels.RawSymbolInfo{
+			Symbol:              toBiconomySymbol(rules.Symbol),
+			Status:              status,
+			BaseAsset:           pair.Base,
+			BaseAssetPrecision:  utils.StepDecimals(rules.LotStep),
+			QuoteAsset:          pair.Quote,
+			QuoteAssetPrecision: utils.StepDecimals(rules.TickSize),
+		}
+	})
+	c.JSON(http.StatusOK, infos)
+}
+
+func (s *Server) handleBiconomyPlaceOrder(c *gin.Context) {
+	values := params(c)
+	price, err := strconv.ParseFloat(values.Get("price"), 64)
+	if err != nil {
+		biconomyRespond(c, biconomyParamsErrorCode, "invalid price", nil)
+		return
+	}
+	qty, err := strconv.ParseFloat(values.Get("amount"), 64)
+	if err != nil {
+		biconomyRespond(c, biconomyParamsErrorCode, "invalid amount", nil)
+		return
+	}
+	side, _ := strconv.Atoi(values.Get("side"))
+	order, err := s.engine.PlaceOrder(biconomyOwner(c), values.Get("market"), fromBiconomySide(side), price, qty, OrderOptions{})
+	if err != nil {
+		biconomyRespond(c, biconomyParamsErrorCode, err.Error(), nil)
+		return
+	}
+	biconomyRespond(c, biconomySuccessCode, "Success", &biconomyModels.RawFulfilledOrder{
+		Amount:     formatFloat(order.Qty),
+		OrderID:    order.ID,
+		Market:     values.Get("market"),
+		Price:      formatFloat(order.Price),
+		Side:       toBiconomySide(order.Action),
+		DealStock:  formatFloat(order.Filled),
+		DealMoney:  formatFloat(order.FilledQuote),
+		Left:       formatFloat(order.Left()),
+		CreatedAt:  toBiconomyTime(order.CreatedAt.UnixMilli()),
+		ModifiedAt: toBiconomyTime(order.UpdatedAt.UnixMilli()),
+	})
+}
+
+func (s *Server) handleBiconomyCancelOrder(c *gin.Context) {
+	values := params(c)
+	orderID, _ := strconv.Atoi(values.Get("order_id"))
+	order, err := s.engine.Cancel(biconomyOwner(c), values.Get("market"), orderID)
+	if err != nil {
+		biconomyRespond(c, biconomyOrderNotFoundCode, "order not found", nil)
+		return
+	}
+	biconomyRespond(c, biconomySuccessCode, "Success", toBiconomyPendingOrder(&order, values.Get("market")))
+}
+
+func (s *Server) handleBiconomyBatchCancelOrders(c *gin.Context) {
+	var orderParams []biconomyModels.CancelledOrderParam
+	if err := json.Unmarshal([]byte(params(c).Get("orders_json")), &orderParams); err != nil {
+		biconomyRespond(c, biconomyParamsErrorCode, "invalid orders_json", nil)
+		return
+	}
+	batch := lo.Map(orderParams, func(param biconomyModels.CancelledOrderParam, _ int) biconomyModels.RawCancelledOrderInBatch {
+		_, err := s.engine.Cancel(biconomyOwner(c), param.Symbol, param.OrderId)
+		return biconomyModels.RawCancelledOrderInBatch{
+			Symbol:     param.Symbol,
+			OrderId:    param.OrderId,
+			Successful: strconv.FormatBool(err == nil),
+		}
+	})
+	biconomyRespond(c, biconomySuccessCode, "Success", biconomyModels.RawCancelledBatch(batch))
+}
+
+func (s *Server) handleBiconomyAssets(c *gin.Context) {
+	assets := biconomyModels.RawAssets{}
+	for _, balance := range s.engine.Balances(biconomyOwner(c)) {
+		assets[balance.Asset] = biconomyModels.RawAsset{
+			Available: formatFloat(balance.Free),
+			Freeze:    formatFloat(balance.Locked),
+		}
+	}
+	biconomyRespond(c, biconomySuccessCode, "Success", assets)
+}
+
+func (s *Server) handleBiconomyPendingOrders(c *gin.Context) {
+	values := params(c)
+	orders, err := s.engine.OpenOrders(biconomyOwner(c), values.Get("market"))
+	if err != nil {
+		biconomyRespond(c, biconomyParamsErrorCode, err.Error(), nil)
+		return
+	}
+	limit, err := strconv.Atoi(values.Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = 100
+	}
+	offset, _ := strconv.Atoi(values.Get("offset"))
+	page := lo.Subset(orders, offset, uint(limit))
+	biconomyRespond(c, biconomySuccessCode, "Success", &biconomyModels.PendingOrdersResult{
+		Limit:  limit,
+		Offset: offset,
+		Records: lo.Map(page, func(order Order, _ int) biconomyModels.RawPendingOrder {
+			return *toBiconomyPendingOrder(&order, values.Get("market"))
+		}),
+	})
+}
+
+func (s *Server) handleBiconomyPendingOrder(c *gin.Context) {
+	values := params(c)
+	orderID, _ := strconv.Atoi(values.Get("order_id"))
+	order, err := s.engine.GetOrder(biconomyOwner(c), orderID)
+	if err != nil || !isOpen(&order) {
+		// Biconomy answers with an empty record for orders which are not pending.
+		biconomyRespond(c, biconomySuccessCode, "Success", gin.H{})
+		return
+	}
+	biconomyRespond(c, biconomySuccessCode, "Success", toBiconomyPendingOrder(&order, values.Get("market")))
+}
+
+func (s *Server) handleBiconomyFinishedOrder(c *gin.Context) {
+	orderID, _ := strconv.Atoi(params(c).Get("order_id"))
+	order, err := s.engine.GetOrder(biconomyOwner(c), orderID)
+	if err != nil || isOpen(&order) {
+		biconomyRespond(c, biconomySuccessCode, "Success", gin.H{})
+		return
+	}
+	biconomyRespond(c, biconomySuccessCode, "Success", toBiconomyFinishedOrder(&order))
+}
+
+// handleBiconomyFinishedOrders pages through the finished orders, the time range is in epoch seconds.
+func (s *Server) handleBiconomyFinishedOrders(c *gin.Context) {
+	values := params(c)
+	startTime, _ := strconv.ParseInt(values.Get("start_time"), 10, 64)
+	endTime, err := strconv.ParseInt(values.Get("end_time"), 10, 64)
+	if err != nil {
+		endTime = time.Now().Unix()
+	}
+	// The range is inclusive of the whole end second.
+	orders, err := s.engine.FinishedOrders(biconomyOwner(c), values.Get("market"), time.Unix(startTime, 0), time.Unix(endTime+1, 0))
+	if err != nil {
+		biconomyRespond(c, biconomyParamsErrorCode, err.Error(), nil)
+		return
+	}
+	limit, err := strconv.Atoi(values.Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = 100
+	}
+	offset, _ := strconv.Atoi(values.Get("offset"))
+	page := lo.Subset(orders, offset, uint(limit))
+	biconomyRespond(c, biconomySuccessCode, "Success", &biconomyModels.FinishedOrdersResult{
+		Limit:  limit,
+		Offset: offset,
+		Records: lo.Map(page, func(order Order, _ int) biconomyModels.RawFinishedOrder {
+			return *toBiconomyFinishedOrder(&order)
+		}),
+	})
+}
+
+func toBiconomyFinishedOrder(order *Order) *biconomyModels.RawFinishedOrder {
+	return &biconomyModels.RawFinishedOrder{
+		Amount:     formatFloat(order.Qty),
+		CreatedAt:  toBiconomyTime(order.CreatedAt.UnixMilli()),
+		DealFee:    formatFloat(order.Fee),
+		DealMoney:  formatFloat(order.FilledQuote),
+		DealStock:  formatFloat(order.Filled),
+		FinishedAt: toBiconomyTime(order.UpdatedAt.UnixMilli()),
+		OrderId:    order.ID,
+		MakerFee:   "0",
+		Symbol:     toBiconomySymbol(order.Symbol),
+		Price:      formatFloat(order.Price),
+		Side:       toBiconomySide(order.Action),
+		TakerFee:   "0",
+		Type:       1,
+	}
+}
+
+func toBiconomyPendingOrder(order *Order, market string) *biconomyModels.RawPendingOrder {
+	if market == "" {
+		market = toBiconomySymbol(order.Symbol)
+	}
+	return &biconomyModels.RawPendingOrder{
+		Amount:     formatFloat(order.Qty),
+		CreatedAt:  toBiconomyTime(order.CreatedAt.UnixMilli()),
+		DealFee:    formatFloat(order.Fee),
+		DealMoney:  formatFloat(order.FilledQuote),
+		DealStock:  formatFloat(order.Filled),
+		OrderId:    order.ID,
+		Left:       formatFloat(order.Left()),
+		MakerFee:   "0",
+		Symbol:     market,
+		ModifiedAt: toBiconomyTime(order.UpdatedAt.UnixMilli()),
+		Price:      formatFloat(order.Price),
+		Side:       toBiconomySide(order.Action),
+		Source:     "api",
+		TakerFee:   "0",
+		Type:       1,
+	}
+}
+
+func toBiconomySide(action models.OrderAction) int {
+	if action == models.Buy {
+		return biconomyBid
+	}
+	return biconomyAsk
+}
+
+func fromBiconomySide(side int) models.OrderAction {
+	switch side {
+	case biconomyBid:
+		return models.Buy
+	case biconomyAsk:
+		return models.Sell
+	default:
+		return ""
+	}
+}
+
+// toBiconomyTime converts epoch milliseconds into the fractional epoch seconds biconomy reports.
+func toBiconomyTime(millis int64) float64 {
+	return float64(millis) / 1000
+}
+
+func toBiconomySymbol(symbol string) string {
+	return splitSymbol(symbol, "_")
+}
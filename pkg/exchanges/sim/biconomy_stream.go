@@ -0,0 +1,93 @@
+package sim
+
+import (
+	"encoding/json"
+
+	"github.com/gin-gonic/gin"
+
+	biconomyModels "github.com/imbonda/vmm-bot/pkg/exchanges/biconomy/models"
+	"github.com/imbonda/vmm-bot/pkg/models"
+)
+
+func (s *Server) handleBiconomyStream(c *gin.Context) {
+	s.serveStream(c, biconomyStream{})
+}
+
+// biconomyStream speaks the JSON-RPC public websocket, pushing depth and deals updates.
+type biconomyStream struct{}
+
+func (biconomyStream) handle(message []byte) ([]byte, string) {
+	var request struct {
+		ID     int               `json:"id"`
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+	}
+	if err := json.Unmarshal(message, &request); err != nil {
+		return nil, ""
+	}
+	var symbol string
+	var result any = gin.H{"status": "success"}
+	switch request.Method {
+	case biconomyModels.StreamMethodPing:
+		result = "pong"
+	case biconomyModels.StreamMethodDepthSubscribe, biconomyModels.StreamMethodDealsSubscribe:
+		if len(request.Params) > 0 {
+			json.Unmarshal(request.Params[0], &symbol)
+		}
+	}
+	reply, _ := json.Marshal(gin.H{
+		"id":     request.ID,
+		"error":  nil,
+		"result": result,
+	})
+	return reply, symbol
+}
+
+func (biconomyStream) book(symbol string, ticker Ticker) []byte {
+	// Every push is a clean snapshot of the top level.
+	message, _ := json.Marshal(gin.H{
+		"id":     nil,
+		"method": biconomyModels.StreamMethodDepthUpdate,
+		"params": []any{
+			true,
+			biconomyModels.RawStreamDepth{
+				Asks: [][]string{{formatFloat(ticker.Ask), formatFloat(ticker.AskQty)}},
+				Bids: [][]string{{formatFloat(ticker.Bid), formatFloat(ticker.BidQty)}},
+			},
+			symbol,
+		},
+	})
+	return message
+}
+
+func (biconomyStream) trade(symbol string, trade Trade) []byte {
+	side := "sell"
+	if trade.Taker == models.Buy {
+		side = "buy"
+	}
+	message, _ := json.Marshal(gin.H{
+		"id":     nil,
+		"method": biconomyModels.StreamMethodDealsUpdate,
+		"params": []any{
+			symbol,
+			[]biconomyModels.RawStreamDeal{
+				{
+					ID:     trade.ID,
+					Time:   float64(trade.Time.UnixMilli()) / 1000,
+					Price:  formatFloat(trade.Price),
+					Amount: formatFloat(trade.Qty),
+					Type:   side,
+				},
+			},
+		},
+	})
+	return message
+}
+
+func (biconomyStream) ping() []byte {
+	return nil
+}
+
+func (biconomyStream) compressed() bool {
+	return false
+}
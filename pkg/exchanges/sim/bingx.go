@@ -0,0 +1,340 @@
+package sim
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/samber/lo"
+
+	bingxModels "github.com/imbonda/vmm-bot/pkg/exchanges/bingx/models"
+	"github.com/imbonda/vmm-bot/pkg/models"
+)
+
+const (
+	bingxOwnerPrefix = "bingx:"
+
+	bingxSuccessCode       = 0
+	bingxSignErrorCode     = 100001
+	bingxParamsErrorCode   = 100400
+	bingxOrderNotFoundCode = 100404
+	bingxBalanceErrorCode  = 100202
+)
+
+func (s *Server) registerBingXRoutes(router *gin.Engine) {
+	router.GET("/market", s.handleBingXStream)
+	v1 := router.Group("/openApi/spot/v1")
+	{
+		v1.GET("/ticker/bookTicker", s.handleBingXBookTicker)
+		v1.GET("/ticker/price", s.handleBingXPriceTicker)
+		v1.GET("/common/symbols", s.handleBingXSymbols)
+	}
+	private := v1.Group("/trade", s.bingxAuth)
+	{
+		private.POST("/order", s.handleBingXPlaceOrder)
+		private.POST("/cancel", s.handleBingXCancelOrder)
+		private.POST("/cancelOpenOrders", s.handleBingXCancelAllOrders)
+		private.GET("/query", s.handleBingXGetOrder)
+		private.GET("/openOrders", s.handleBingXOpenOrders)
+		private.GET("/myTrades", s.handleBingXFills)
+	}
+	v1.GET("/account/balance", s.bingxAuth, s.handleBingXBalances)
+}
+
+// bingxAuth validates the HMAC signature of the sorted parameters, sent either in the query or in the form.
+func (s *Server) bingxAuth(c *gin.Context) {
+	values, err := readForm(c)
+	if err != nil {
+		bingxRespond(c, bingxParamsErrorCode, err.Error(), nil)
+		c.Abort()
+		return
+	}
+	if c.GetHeader("X-BX-APIKEY") != s.bingx.APIKey {
+		bingxRespond(c, bingxSignErrorCode, "Incorrect apiKey", nil)
+		c.Abort()
+		return
+	}
+	if values.Get("signature") != bingxSignature(withoutParam(values, "signature"), s.bingx.APISecret) {
+		bingxRespond(c, bingxSignErrorCode, "Signature verification failed", nil)
+		c.Abort()
+		return
+	}
+	c.Set(paramsKey, values)
+	c.Next()
+}
+
+func bingxRespond(c *gin.Context, code int, msg string, data any) {
+	c.JSON(http.StatusOK, gin.H{
+		"code": code,
+		"msg":  msg,
+		"data": data,
+	})
+}
+
+func bingxOwner(c *gin.Context) string {
+	return bingxOwnerPrefix + c.GetHeader("X-BX-APIKEY")
+}
+
+func (s *Server) handleBingXBookTicker(c *gin.Context) {
+	ticker, err := s.engine.Ticker(c.Query("symbol"))
+	if err != nil {
+		bingxRespond(c, bingxParamsErrorCode, err.Error(), nil)
+		return
+	}
+	bingxRespond(c, bingxSuccessCode, "", bingxModels.RawBookTickers{
+		{
+			Symbol:    c.Query("symbol"),
+			EventType: "bookTicker",
+			Time:      int(time.Now().UnixMilli()),
+			AskPrice:  formatFloat(ticker.Ask),
+			AskAmount: formatFloat(ticker.AskQty),
+			BidPrice:  formatFloat(ticker.Bid),
+			BidAmount: formatFloat(ticker.BidQty),
+		},
+	})
+}
+
+func (s *Server) handleBingXSymbols(c *gin.Context) {
+	rules, err := s.engine.Rules(c.Query("symbol"))
+	if err != nil {
+		bingxRespond(c, bingxParamsErrorCode, err.Error(), nil)
+		return
+	}
+	status := bingxModels.SymbolStatusOnline
+	if rules.Halted {
+		status = 0
+	}
+	bingxRespond(c, bingxSuccessCode, "", &bingxModels.RawSymbols{
+		Symbols: []bingxModels.RawSymbol{
+			{
+				Symbol:      c.Query("symbol"),
+				TickSize:    rules.TickSize,
+				StepSize:    rules.LotStep,
+				MinQty:      rules.MinQty,
+				MinNotional: rules.MinNotional,
+				Status:      status,
+			},
+		},
+	})
+}
+
+func (s *Server) handleBingXPriceTicker(c *gin.Context) {
+	ticker, err := s.engine.Ticker(c.Query("symbol"))
+	if err != nil {
+		bingxRespond(c, bingxParamsErrorCode, err.Error(), nil)
+		return
+	}
+	trades, err := s.engine.RecentTrades(c.Query("symbol"), 1)
+	if err != nil {
+		bingxRespond(c, bingxParamsErrorCode, err.Error(), nil)
+		return
+	}
+	rawTrades := lo.Map(trades, func(trade Trade, _ int) bingxModels.RawTrade {
+		return bingxModels.RawTrade{
+			Timestamp: int(trade.Time.UnixMilli()),
+			TradeID:   strconv.Itoa(trade.ID),
+			Price:     formatFloat(trade.Price),
+			Amount:    formatFloat(trade.Qty),
+			Volume:    formatFloat(trade.Price * trade.Qty),
+		}
+	})
+	if len(rawTrades) == 0 {
+		// Nothing was traded yet, report the initial market price.
+		rawTrades = append(rawTrades, bingxModels.RawTrade{
+			Price: formatFloat(ticker.LastPrice),
+		})
+	}
+	bingxRespond(c, bingxSuccessCode, "", bingxModels.RawPriceTickers{
+		{
+			Symbol: c.Query("symbol"),
+			Trades: rawTrades,
+		},
+	})
+}
+
+func (s *Server) handleBingXPlaceOrder(c *gin.Context) {
+	values := params(c)
+	options, ok := orderOptions(values.Get("type"), values.Get("timeInForce"))
+	if !ok {
+		bingxRespond(c, bingxParamsErrorCode, "unsupported order type", nil)
+		return
+	}
+	var price float64
+	var err error
+	if !options.Market {
+		if price, err = strconv.ParseFloat(values.Get("price"), 64); err != nil {
+			bingxRespond(c, bingxParamsErrorCode, "invalid price", nil)
+			return
+		}
+	}
+	qty, err := strconv.ParseFloat(values.Get("quantity"), 64)
+	if err != nil {
+		bingxRespond(c, bingxParamsErrorCode, "invalid quantity", nil)
+		return
+	}
+	action := models.OrderAction(strings.ToLower(values.Get("side")))
+	options.ClientOrderID = values.Get("newClientOrderId")
+	order, err := s.engine.PlaceOrder(bingxOwner(c), values.Get("symbol"), action, price, qty, options)
+	switch {
+	case errors.Is(err, ErrInsufficientBalance):
+		bingxRespond(c, bingxBalanceErrorCode, err.Error(), nil)
+		return
+	case err != nil:
+		bingxRespond(c, bingxParamsErrorCode, err.Error(), nil)
+		return
+	}
+	bingxRespond(c, bingxSuccessCode, "", toBingXOrder(&order, values.Get("symbol")))
+}
+
+func (s *Server) handleBingXCancelOrder(c *gin.Context) {
+	values := params(c)
+	order, err := s.findBingXOrder(c)
+	if err == nil {
+		order, err = s.engine.Cancel(bingxOwner(c), values.Get("symbol"), order.ID)
+	}
+	if err != nil {
+		bingxRespond(c, bingxOrderNotFoundCode, "order not exist", nil)
+		return
+	}
+	bingxRespond(c, bingxSuccessCode, "", toBingXOrder(&order, values.Get("symbol")))
+}
+
+func (s *Server) handleBingXCancelAllOrders(c *gin.Context) {
+	values := params(c)
+	cancelled, err := s.engine.CancelAll(bingxOwner(c), values.Get("symbol"))
+	if err != nil {
+		bingxRespond(c, bingxParamsErrorCode, err.Error(), nil)
+		return
+	}
+	bingxRespond(c, bingxSuccessCode, "", &bingxModels.RawCancelledBatch{
+		Orders: toBingXOrders(cancelled, values.Get("symbol")),
+	})
+}
+
+func (s *Server) handleBingXGetOrder(c *gin.Context) {
+	order, err := s.findBingXOrder(c)
+	if err != nil {
+		bingxRespond(c, bingxOrderNotFoundCode, "order not exist", nil)
+		return
+	}
+	bingxRespond(c, bingxSuccessCode, "", toBingXOrder(&order, params(c).Get("symbol")))
+}
+
+func (s *Server) handleBingXOpenOrders(c *gin.Context) {
+	values := params(c)
+	orders, err := s.engine.OpenOrders(bingxOwner(c), values.Get("symbol"))
+	if err != nil {
+		bingxRespond(c, bingxParamsErrorCode, err.Error(), nil)
+		return
+	}
+	bingxRespond(c, bingxSuccessCode, "", &bingxModels.RawOpenOrders{
+		Orders: toBingXOrders(orders, values.Get("symbol")),
+	})
+}
+
+func (s *Server) handleBingXFills(c *gin.Context) {
+	values := params(c)
+	startTime, _ := strconv.ParseInt(values.Get("startTime"), 10, 64)
+	fills, err := s.engine.Fills(bingxOwner(c), values.Get("symbol"), time.UnixMilli(startTime))
+	if err != nil {
+		bingxRespond(c, bingxParamsErrorCode, err.Error(), nil)
+		return
+	}
+	if limit, err := strconv.Atoi(values.Get("limit")); err == nil && limit > 0 && len(fills) > limit {
+		fills = fills[:limit]
+	}
+	bingxRespond(c, bingxSuccessCode, "", &bingxModels.RawFills{
+		Fills: lo.Map(fills, func(fill Fill, _ int) bingxModels.RawFill {
+			return toBingXFill(&fill, values.Get("symbol"))
+		}),
+	})
+}
+
+func (s *Server) handleBingXBalances(c *gin.Context) {
+	bingxRespond(c, bingxSuccessCode, "", &bingxModels.RawBalances{
+		Balances: lo.Map(s.engine.Balances(bingxOwner(c)), func(balance Balance, _ int) bingxModels.RawBalance {
+			return bingxModels.RawBalance{
+				Asset:  balance.Asset,
+				Free:   formatFloat(balance.Free),
+				Locked: formatFloat(balance.Locked),
+			}
+		}),
+	})
+}
+
+func (s *Server) findBingXOrder(c *gin.Context) (Order, error) {
+	values := params(c)
+	if values.Has("clientOrderID") {
+		return s.engine.GetOrderByClientID(bingxOwner(c), values.Get("clientOrderID"))
+	}
+	orderID, err := strconv.Atoi(values.Get("orderId"))
+	if err != nil {
+		return Order{}, ErrOrderNotFound
+	}
+	return s.engine.GetOrder(bingxOwner(c), orderID)
+}
+
+func toBingXOrders(orders []Order, symbol string) []bingxModels.RawPendingOrder {
+	return lo.Map(orders, func(order Order, _ int) bingxModels.RawPendingOrder {
+		return *toBingXOrder(&order, symbol)
+	})
+}
+
+func toBingXOrder(order *Order, symbol string) *bingxModels.RawPendingOrder {
+	if symbol == "" {
+		symbol = splitSymbol(order.Symbol, "-")
+	}
+	return &bingxModels.RawPendingOrder{
+		Symbol:        symbol,
+		OrderID:       order.ID,
+		ClientOrderID: order.ClientOrderID,
+		Timestamp:     int(order.CreatedAt.UnixMilli()),
+		Price:         formatFloat(order.Price),
+		OrigQty:       formatFloat(order.Qty),
+		ExecQty:       formatFloat(order.Filled),
+		CummQuoteQty:  formatFloat(order.FilledQuote),
+		Status:        toBingXOrderStatus(order.Status),
+		Type:          "LIMIT",
+		Side:          strings.ToUpper(string(order.Action)),
+	}
+}
+
+// toBingXFill reports the commission as a negative amount, as the exchange does.
+func toBingXFill(fill *Fill, symbol string) bingxModels.RawFill {
+	pair, _ := parsePair(fill.Symbol)
+	commissionAsset := pair.Quote
+	if fill.Action == models.Buy {
+		commissionAsset = pair.Base
+	}
+	return bingxModels.RawFill{
+		Symbol:          symbol,
+		ID:              fill.TradeID,
+		OrderID:         fill.OrderID,
+		Price:           formatFloat(fill.Price),
+		Qty:             formatFloat(fill.Qty),
+		QuoteQty:        formatFloat(fill.Price * fill.Qty),
+		Commission:      formatFloat(-fill.Fee),
+		CommissionAsset: commissionAsset,
+		Time:            fill.Time.UnixMilli(),
+		IsBuyer:         fill.Action == models.Buy,
+		IsMaker:         fill.Maker,
+	}
+}
+
+func toBingXOrderStatus(status models.OrderStatus) string {
+	switch status {
+	case models.OrderStatusPartiallyFilled:
+		return bingxModels.OrderStatusPartiallyFilled
+	case models.OrderStatusFilled:
+		return bingxModels.OrderStatusFilled
+	case models.OrderStatusCancelled:
+		return bingxModels.OrderStatusCanceled
+	case models.OrderStatusRejected:
+		return bingxModels.OrderStatusFailed
+	default:
+		return bingxModels.OrderStatusNew
+	}
+}
@@ -0,0 +1,81 @@
+package sim
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	bingxModels "github.com/imbonda/vmm-bot/pkg/exchanges/bingx/models"
+	"github.com/imbonda/vmm-bot/pkg/models"
+)
+
+func (s *Server) handleBingXStream(c *gin.Context) {
+	s.serveStream(c, bingxStream{})
+}
+
+// bingxStream speaks the spot market websocket, compressing every message and pinging the client.
+type bingxStream struct{}
+
+func (bingxStream) handle(message []byte) ([]byte, string) {
+	if string(message) == bingxModels.StreamPong {
+		return nil, ""
+	}
+	var request bingxModels.RawStreamRequest
+	if err := json.Unmarshal(message, &request); err != nil {
+		return nil, ""
+	}
+	var symbol string
+	if request.ReqType == "sub" {
+		symbol, _, _ = strings.Cut(request.DataType, "@")
+	}
+	reply, _ := json.Marshal(gin.H{
+		"id":       request.ID,
+		"code":     bingxSuccessCode,
+		"msg":      "",
+		"dataType": "",
+		"data":     nil,
+	})
+	return reply, symbol
+}
+
+func (bingxStream) book(symbol string, ticker Ticker) []byte {
+	message, _ := json.Marshal(gin.H{
+		"code":     bingxSuccessCode,
+		"dataType": symbol + bingxModels.StreamBookTickerSuffix,
+		"data": bingxModels.RawStreamBookTicker{
+			Symbol:    symbol,
+			BidPrice:  formatFloat(ticker.Bid),
+			BidAmount: formatFloat(ticker.BidQty),
+			AskPrice:  formatFloat(ticker.Ask),
+			AskAmount: formatFloat(ticker.AskQty),
+		},
+	})
+	return message
+}
+
+func (bingxStream) trade(symbol string, trade Trade) []byte {
+	message, _ := json.Marshal(gin.H{
+		"code":     bingxSuccessCode,
+		"dataType": symbol + bingxModels.StreamTradeSuffix,
+		"data": gin.H{
+			"e": "trade",
+			"s": symbol,
+			"t": strconv.Itoa(trade.ID),
+			"p": formatFloat(trade.Price),
+			"q": formatFloat(trade.Qty),
+			"T": trade.Time.UnixMilli(),
+			"m": trade.Taker == models.Sell,
+		},
+	})
+	return message
+}
+
+func (bingxStream) ping() []byte {
+	return []byte(bingxModels.StreamPing)
+}
+
+func (bingxStream) compressed() bool {
+	return true
+}
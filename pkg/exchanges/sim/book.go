@@ -0,0 +1,202 @@
+package sim
+
+import (
+	"math"
+	"slices"
+	"time"
+
+	"github.com/imbonda/vmm-bot/pkg/models"
+)
+
+const (
+	maxTradesHistory = 1000
+	maxFillsHistory  = 10000
+)
+
+type book struct {
+	symbol    string
+	bids      []*Order
+	asks      []*Order
+	lastPrice float64
+	trades    []Trade
+	// Fills of the non-house orders.
+	fills []Fill
+}
+
+func (b *book) side(action models.OrderAction) []*Order {
+	if action == models.Buy {
+		return b.bids
+	}
+	return b.asks
+}
+
+func (b *book) setSide(action models.OrderAction, orders []*Order) {
+	if action == models.Buy {
+		b.bids = orders
+	} else {
+		b.asks = orders
+	}
+}
+
+func opposite(action models.OrderAction) models.OrderAction {
+	if action == models.Buy {
+		return models.Sell
+	}
+	return models.Buy
+}
+
+func crosses(taker *Order, maker *Order) bool {
+	if taker.Action == models.Buy {
+		return maker.Price <= taker.Price
+	}
+	return maker.Price >= taker.Price
+}
+
+// match fills the taker against the resting orders of the opposite side, at the makers' prices.
+// Every fill is settled into the accounts of the orders' owners.
+func (b *book) match(taker *Order, nextTradeID func() int, feeRate float64, settle func(*Order, Fill)) {
+	makerSide := opposite(taker.Action)
+	makers := b.side(makerSide)
+	for len(makers) > 0 && taker.Left() > 0 {
+		maker := makers[0]
+		if !crosses(taker, maker) {
+			break
+		}
+		qty := math.Min(taker.Left(), maker.Left())
+		now := time.Now()
+		trade := Trade{
+			ID:     nextTradeID(),
+			Symbol: b.symbol,
+			Price:  maker.Price,
+			Qty:    qty,
+			Taker:  taker.Action,
+			Time:   now,
+		}
+		b.fill(maker, trade, true, feeRate, settle)
+		b.fill(taker, trade, false, feeRate, settle)
+		b.lastPrice = maker.Price
+		b.trades = append(b.trades, trade)
+		if maker.Left() <= 0 {
+			makers = makers[1:]
+		}
+	}
+	b.setSide(makerSide, makers)
+	if len(b.trades) > maxTradesHistory {
+		b.trades = b.trades[len(b.trades)-maxTradesHistory:]
+	}
+	if len(b.fills) > maxFillsHistory {
+		b.fills = b.fills[len(b.fills)-maxFillsHistory:]
+	}
+}
+
+// available returns the qty of the opposite side the order would take right away.
+func (b *book) available(taker *Order) float64 {
+	qty := 0.0
+	for _, maker := range b.side(opposite(taker.Action)) {
+		if !crosses(taker, maker) {
+			break
+		}
+		qty += maker.Left()
+	}
+	return qty
+}
+
+// sweepPrice returns the price of the worst resting order a market order of the qty takes, zero when the opposite
+// side is empty.
+func (b *book) sweepPrice(action models.OrderAction, qty float64) float64 {
+	price := 0.0
+	for _, maker := range b.side(opposite(action)) {
+		price = maker.Price
+		if qty -= maker.Left(); qty <= 0 {
+			break
+		}
+	}
+	return price
+}
+
+// fill executes the trade quantity of the order, the fee is charged in the received asset.
+func (b *book) fill(order *Order, trade Trade, maker bool, feeRate float64, settle func(*Order, Fill)) {
+	executed := Fill{
+		TradeID: trade.ID,
+		OrderID: order.ID,
+		Owner:   order.Owner,
+		Symbol:  b.symbol,
+		Action:  order.Action,
+		Price:   trade.Price,
+		Qty:     trade.Qty,
+		Fee:     trade.Qty * feeRate,
+		Maker:   maker,
+		Time:    trade.Time,
+	}
+	if order.Action == models.Sell {
+		executed.Fee *= trade.Price
+	}
+	order.Filled += trade.Qty
+	order.FilledQuote += trade.Price * trade.Qty
+	order.Fee += executed.Fee
+	order.UpdatedAt = trade.Time
+	settle(order, executed)
+	if order.Owner != houseOwner {
+		b.fills = append(b.fills, executed)
+	}
+	if order.Left() <= 0 {
+		order.Status = models.OrderStatusFilled
+	} else {
+		order.Status = models.OrderStatusPartiallyFilled
+	}
+}
+
+// rest inserts the order into its side keeping price-time priority.
+func (b *book) rest(order *Order) {
+	orders := b.side(order.Action)
+	idx, _ := slices.BinarySearchFunc(orders, order, func(resting, target *Order) int {
+		better := resting.Price > target.Price
+		if order.Action == models.Sell {
+			better = resting.Price < target.Price
+		}
+		switch {
+		case better || resting.Price == target.Price:
+			// Resting orders with an equal price keep their priority.
+			return -1
+		default:
+			return 1
+		}
+	})
+	b.setSide(order.Action, slices.Insert(orders, idx, order))
+}
+
+func (b *book) remove(order *Order) {
+	orders := b.side(order.Action)
+	b.setSide(order.Action, slices.DeleteFunc(orders, func(resting *Order) bool {
+		return resting.ID == order.ID
+	}))
+}
+
+func (b *book) ordersOf(owner string) []*Order {
+	var owned []*Order
+	for _, orders := range [][]*Order{b.bids, b.asks} {
+		for _, order := range orders {
+			if order.Owner == owner {
+				owned = append(owned, order)
+			}
+		}
+	}
+	return owned
+}
+
+// levels aggregates the orders of a side into price levels.
+func (b *book) levels(orders []*Order, limit int) [][2]float64 {
+	var levels [][2]float64
+	for _, order := range orders {
+		n := len(levels)
+		if n > 0 && levels[n-1][0] == order.Price {
+			levels[n-1][1] += order.Left()
+			continue
+		}
+		if limit > 0 && n == limit {
+			break
+		}
+		levels = append(levels, [2]float64{order.Price, order.Left()})
+	}
+	return levels
+}
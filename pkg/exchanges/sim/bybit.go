@@ -0,0 +1,410 @@
+package sim
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/samber/lo"
+
+	bybitModels "github.com/imbonda/vmm-bot/pkg/exchanges/bybit/models"
+	"github.com/imbonda/vmm-bot/pkg/models"
+	"github.com/imbonda/vmm-bot/pkg/utils"
+)
+
+const (
+	bybitOwnerPrefix = "bybit:"
+
+	bybitSuccessCode       = 0
+	bybitParamsErrorCode   = 10001
+	bybitInvalidKeyCode    = 10003
+	bybitSignErrorCode     = 10004
+	bybitOrderNotFoundCode = 110001
+	bybitBalanceErrorCode  = 170131
+	bybitPrecisionCode     = 170134
+	bybitHaltedCode        = 170157
+)
+
+func (s *Server) registerBybitRoutes(router *gin.Engine) {
+	router.GET("/v5/public/spot", s.handleBybitStream)
+	v5 := router.Group("/v5")
+	{
+		v5.GET("/market/orderbook", s.handleBybitOrderBook)
+		v5.GET("/market/tickers", s.handleBybitTickers)
+		v5.GET("/market/instruments-info", s.handleBybitInstruments)
+	}
+	private := v5.Group("/order", s.bybitAuth)
+	{
+		private.POST("/create", s.handleBybitPlaceOrder)
+		private.POST("/cancel", s.handleBybitCancelOrder)
+		private.POST("/cancel-all", s.handleBybitCancelAllOrders)
+		private.GET("/realtime", s.handleBybitOpenOrders)
+		private.GET("/history", s.handleBybitOrderHistory)
+	}
+	v5.GET("/execution/list", s.bybitAuth, s.handleBybitExecutions)
+	v5.GET("/account/wallet-balance", s.bybitAuth, s.handleBybitWalletBalance)
+}
+
+// bybitAuth validates the HMAC signature of the v5 API (timestamp + api key + recv window + payload).
+func (s *Server) bybitAuth(c *gin.Context) {
+	var payload string
+	values := url.Values{}
+	if c.Request.Method == http.MethodGet {
+		payload = c.Request.URL.RawQuery
+		values = c.Request.URL.Query()
+	} else {
+		body, err := c.GetRawData()
+		if err != nil {
+			bybitRespond(c, bybitParamsErrorCode, err.Error(), nil)
+			c.Abort()
+			return
+		}
+		payload = string(body)
+		var raw map[string]any
+		if err = json.Unmarshal(body, &raw); err != nil {
+			bybitRespond(c, bybitParamsErrorCode, "invalid json body", nil)
+			c.Abort()
+			return
+		}
+		for key, value := range raw {
+			values.Set(key, fmt.Sprint(value))
+		}
+	}
+	apiKey := c.GetHeader("X-BAPI-API-KEY")
+	if apiKey != s.bybit.APIKey {
+		bybitRespond(c, bybitInvalidKeyCode, "API key is invalid.", nil)
+		c.Abort()
+		return
+	}
+	signature := bybitSignature(c.GetHeader("X-BAPI-TIMESTAMP"), apiKey, c.GetHeader("X-BAPI-RECV-WINDOW"), payload, s.bybit.APISecret)
+	if c.GetHeader("X-BAPI-SIGN") != signature {
+		bybitRespond(c, bybitSignErrorCode, "error sign!", nil)
+		c.Abort()
+		return
+	}
+	c.Set(paramsKey, values)
+	c.Next()
+}
+
+func bybitRespond(c *gin.Context, code int, msg string, result any) {
+	if result == nil {
+		result = gin.H{}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"retCode":    code,
+		"retMsg":     msg,
+		"result":     result,
+		"retExtInfo": gin.H{},
+		"time":       time.Now().UnixMilli(),
+	})
+}
+
+func bybitOwner(c *gin.Context) string {
+	return bybitOwnerPrefix + c.GetHeader("X-BAPI-API-KEY")
+}
+
+func (s *Server) handleBybitOrderBook(c *gin.Context) {
+	symbol := c.Query("symbol")
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "1"))
+	bids, asks, err := s.engine.Depth(symbol, limit)
+	if err != nil {
+		bybitRespond(c, bybitParamsErrorCode, err.Error(), nil)
+		return
+	}
+	bybitRespond(c, bybitSuccessCode, "OK", &models.OrderBook{
+		Symbol: NormalizeSymbol(symbol),
+		Asks:   formatLevels(asks),
+		Bids:   formatLevels(bids),
+	})
+}
+
+func (s *Server) handleBybitTickers(c *gin.Context) {
+	ticker, err := s.engine.Ticker(c.Query("symbol"))
+	if err != nil {
+		bybitRespond(c, bybitParamsErrorCode, err.Error(), nil)
+		return
+	}
+	bybitRespond(c, bybitSuccessCode, "OK", &bybitModels.RawTickersResult{
+		Category: "spot",
+		List: []bybitModels.RawTicker{
+			{
+				Symbol:       ticker.Symbol,
+				LastPrice:    formatFloat(ticker.LastPrice),
+				BestAskPrice: formatFloat(ticker.Ask),
+				BestAskQty:   formatFloat(ticker.AskQty),
+				BestBidPrice: formatFloat(ticker.Bid),
+				BestBidQty:   formatFloat(ticker.BidQty),
+			},
+		},
+	})
+}
+
+func (s *Server) handleBybitInstruments(c *gin.Context) {
+	rules, err := s.engine.Rules(c.Query("symbol"))
+	if err != nil {
+		bybitRespond(c, bybitParamsErrorCode, err.Error(), nil)
+		return
+	}
+	status := bybitModels.InstrumentStatusTrading
+	if rules.Halted {
+		status = "Closed"
+	}
+	bybitRespond(c, bybitSuccessCode, "OK", &bybitModels.RawInstrumentsResult{
+		Category: "spot",
+		List: []bybitModels.RawInstrument{
+			{
+				Symbol: rules.Symbol,
+				Status: status,
+				LotSizeFilter: bybitModels.RawLotSizeFilter{
+					BasePrecision: formatFloat(rules.LotStep),
+					MinOrderQty:   formatFloat(rules.MinQty),
+					MinOrderAmt:   formatFloat(rules.MinNotional),
+				},
+				PriceFilter: bybitModels.RawPriceFilter{
+					TickSize: formatFloat(rules.TickSize),
+				},
+			},
+		},
+	})
+}
+
+func (s *Server) handleBybitPlaceOrder(c *gin.Context) {
+	values := params(c)
+	options, ok := orderOptions(values.Get("orderType"), values.Get("timeInForce"))
+	if !ok {
+		bybitRespond(c, bybitParamsErrorCode, "unsupported order type", nil)
+		return
+	}
+	action := models.OrderAction(strings.ToLower(values.Get("side")))
+	// Market buys are sized in quote currency unless told otherwise, which the engine doesn't support.
+	if options.Market && action == models.Buy && values.Get("marketUnit") != "baseCoin" {
+		bybitRespond(c, bybitParamsErrorCode, "unsupported market unit", nil)
+		return
+	}
+	var price float64
+	var err error
+	if !options.Market {
+		if price, err = utils.ParseFloat(values.Get("price")); err != nil {
+			bybitRespond(c, bybitParamsErrorCode, "invalid price", nil)
+			return
+		}
+	}
+	qty, err := utils.ParseFloat(values.Get("qty"))
+	if err != nil {
+		bybitRespond(c, bybitParamsErrorCode, "invalid qty", nil)
+		return
+	}
+	options.ClientOrderID = values.Get("orderLinkId")
+	order, err := s.engine.PlaceOrder(bybitOwner(c), values.Get("symbol"), action, price, qty, options)
+	switch {
+	case errors.Is(err, ErrInsufficientBalance):
+		bybitRespond(c, bybitBalanceErrorCode, err.Error(), nil)
+		return
+	case errors.Is(err, ErrInvalidPrecision):
+		bybitRespond(c, bybitPrecisionCode, err.Error(), nil)
+		return
+	case errors.Is(err, ErrSymbolHalted):
+		bybitRespond(c, bybitHaltedCode, err.Error(), nil)
+		return
+	case err != nil:
+		bybitRespond(c, bybitParamsErrorCode, err.Error(), nil)
+		return
+	}
+	bybitRespond(c, bybitSuccessCode, "OK", &bybitModels.RawPlacedOrder{
+		OrderID:     strconv.Itoa(order.ID),
+		OrderLinkID: order.ClientOrderID,
+	})
+}
+
+func (s *Server) handleBybitCancelOrder(c *gin.Context) {
+	values := params(c)
+	order, err := s.findBybitOrder(c, values)
+	if err == nil {
+		order, err = s.engine.Cancel(bybitOwner(c), values.Get("symbol"), order.ID)
+	}
+	if err != nil {
+		bybitRespond(c, bybitOrderNotFoundCode, "Order does not exist.", nil)
+		return
+	}
+	bybitRespond(c, bybitSuccessCode, "OK", &bybitModels.RawPlacedOrder{
+		OrderID:     strconv.Itoa(order.ID),
+		OrderLinkID: order.ClientOrderID,
+	})
+}
+
+func (s *Server) handleBybitCancelAllOrders(c *gin.Context) {
+	cancelled, err := s.engine.CancelAll(bybitOwner(c), params(c).Get("symbol"))
+	if err != nil {
+		bybitRespond(c, bybitParamsErrorCode, err.Error(), nil)
+		return
+	}
+	bybitRespond(c, bybitSuccessCode, "OK", gin.H{
+		"list": lo.Map(cancelled, func(order Order, _ int) bybitModels.RawPlacedOrder {
+			return bybitModels.RawPlacedOrder{
+				OrderID:     strconv.Itoa(order.ID),
+				OrderLinkID: order.ClientOrderID,
+			}
+		}),
+		"success": "1",
+	})
+}
+
+func (s *Server) handleBybitOpenOrders(c *gin.Context) {
+	values := params(c)
+	if values.Has("orderId") || values.Has("orderLinkId") {
+		order, err := s.findBybitOrder(c, values)
+		if err != nil || !isOpen(&order) {
+			s.respondBybitOrders(c, nil)
+			return
+		}
+		s.respondBybitOrders(c, []Order{order})
+		return
+	}
+	orders, err := s.engine.OpenOrders(bybitOwner(c), values.Get("symbol"))
+	if err != nil {
+		bybitRespond(c, bybitParamsErrorCode, err.Error(), nil)
+		return
+	}
+	s.respondBybitOrders(c, orders)
+}
+
+func (s *Server) handleBybitOrderHistory(c *gin.Context) {
+	order, err := s.findBybitOrder(c, params(c))
+	if err != nil {
+		s.respondBybitOrders(c, nil)
+		return
+	}
+	s.respondBybitOrders(c, []Order{order})
+}
+
+// handleBybitExecutions pages through the fills, the cursor is the offset of the next page.
+func (s *Server) handleBybitExecutions(c *gin.Context) {
+	values := params(c)
+	startTime, _ := strconv.ParseInt(values.Get("startTime"), 10, 64)
+	limit, err := strconv.Atoi(values.Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = 50
+	}
+	offset, _ := strconv.Atoi(values.Get("cursor"))
+	fills, err := s.engine.Fills(bybitOwner(c), values.Get("symbol"), time.UnixMilli(startTime))
+	if err != nil {
+		bybitRespond(c, bybitParamsErrorCode, err.Error(), nil)
+		return
+	}
+	// Newest first, as the exchange does.
+	slices.Reverse(fills)
+	page := fills[min(offset, len(fills)):min(offset+limit, len(fills))]
+	cursor := ""
+	if offset+limit < len(fills) {
+		cursor = strconv.Itoa(offset + limit)
+	}
+	bybitRespond(c, bybitSuccessCode, "OK", &bybitModels.RawExecutionsResult{
+		Category:       "spot",
+		NextPageCursor: cursor,
+		List: lo.Map(page, func(fill Fill, _ int) bybitModels.RawExecution {
+			return toBybitExecution(&fill)
+		}),
+	})
+}
+
+func (s *Server) handleBybitWalletBalance(c *gin.Context) {
+	coins := lo.Map(s.engine.Balances(bybitOwner(c)), func(balance Balance, _ int) bybitModels.RawWalletCoin {
+		return bybitModels.RawWalletCoin{
+			Coin:          balance.Asset,
+			WalletBalance: formatFloat(balance.Free + balance.Locked),
+			Locked:        formatFloat(balance.Locked),
+		}
+	})
+	bybitRespond(c, bybitSuccessCode, "OK", &bybitModels.RawWalletBalanceResult{
+		List: []bybitModels.RawWalletAccount{{
+			AccountType: "UNIFIED",
+			Coin:        coins,
+		}},
+	})
+}
+
+func (s *Server) findBybitOrder(c *gin.Context, values url.Values) (Order, error) {
+	if values.Has("orderLinkId") {
+		return s.engine.GetOrderByClientID(bybitOwner(c), values.Get("orderLinkId"))
+	}
+	orderID, err := strconv.Atoi(values.Get("orderId"))
+	if err != nil {
+		return Order{}, ErrOrderNotFound
+	}
+	return s.engine.GetOrder(bybitOwner(c), orderID)
+}
+
+func (s *Server) respondBybitOrders(c *gin.Context, orders []Order) {
+	bybitRespond(c, bybitSuccessCode, "OK", &bybitModels.RawOrdersResult{
+		Category: "spot",
+		List: lo.Map(orders, func(order Order, _ int) bybitModels.RawOrder {
+			return toBybitOrder(&order)
+		}),
+	})
+}
+
+func toBybitOrder(order *Order) bybitModels.RawOrder {
+	side := "Sell"
+	if order.Action == models.Buy {
+		side = "Buy"
+	}
+	return bybitModels.RawOrder{
+		OrderID:     strconv.Itoa(order.ID),
+		OrderLinkID: order.ClientOrderID,
+		Symbol:      order.Symbol,
+		Price:       formatFloat(order.Price),
+		Qty:         formatFloat(order.Qty),
+		Side:        side,
+		OrderStatus: toBybitOrderStatus(order.Status),
+		OrderType:   "Limit",
+		TimeInForce: "GTC",
+		AvgPrice:    formatFloat(order.AvgPrice()),
+		CumExecQty:  formatFloat(order.Filled),
+		CumExecFee:  formatFloat(order.Fee),
+		CreatedTime: strconv.FormatInt(order.CreatedAt.UnixMilli(), 10),
+		UpdatedTime: strconv.FormatInt(order.UpdatedAt.UnixMilli(), 10),
+	}
+}
+
+func toBybitExecution(fill *Fill) bybitModels.RawExecution {
+	pair, _ := parsePair(fill.Symbol)
+	side, feeCurrency := "Sell", pair.Quote
+	if fill.Action == models.Buy {
+		side, feeCurrency = "Buy", pair.Base
+	}
+	return bybitModels.RawExecution{
+		Symbol:      fill.Symbol,
+		OrderID:     strconv.Itoa(fill.OrderID),
+		Side:        side,
+		ExecID:      strconv.Itoa(fill.TradeID),
+		ExecPrice:   formatFloat(fill.Price),
+		ExecQty:     formatFloat(fill.Qty),
+		ExecFee:     formatFloat(fill.Fee),
+		FeeCurrency: feeCurrency,
+		ExecType:    "Trade",
+		ExecTime:    strconv.FormatInt(fill.Time.UnixMilli(), 10),
+		IsMaker:     fill.Maker,
+	}
+}
+
+func toBybitOrderStatus(status models.OrderStatus) string {
+	switch status {
+	case models.OrderStatusPartiallyFilled:
+		return bybitModels.OrderStatusPartiallyFilled
+	case models.OrderStatusFilled:
+		return bybitModels.OrderStatusFilled
+	case models.OrderStatusCancelled:
+		return bybitModels.OrderStatusCancelled
+	case models.OrderStatusRejected:
+		return bybitModels.OrderStatusRejected
+	default:
+		return bybitModels.OrderStatusNew
+	}
+}
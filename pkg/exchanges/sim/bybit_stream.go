@@ -0,0 +1,88 @@
+package sim
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	bybitModels "github.com/imbonda/vmm-bot/pkg/exchanges/bybit/models"
+	"github.com/imbonda/vmm-bot/pkg/models"
+)
+
+func (s *Server) handleBybitStream(c *gin.Context) {
+	s.serveStream(c, bybitStream{})
+}
+
+// bybitStream speaks the v5 public spot websocket, pushing the orderbook.1 and publicTrade topics.
+type bybitStream struct{}
+
+func (bybitStream) handle(message []byte) ([]byte, string) {
+	var request bybitModels.RawStreamRequest
+	if err := json.Unmarshal(message, &request); err != nil {
+		return nil, ""
+	}
+	var symbol string
+	if request.Op == bybitModels.StreamOpSubscribe {
+		for _, topic := range request.Args {
+			if rest, found := strings.CutPrefix(topic, "orderbook.1."); found {
+				symbol = rest
+			}
+		}
+	}
+	retMsg := request.Op
+	if request.Op == bybitModels.StreamOpPing {
+		retMsg = "pong"
+	}
+	reply, _ := json.Marshal(gin.H{
+		"success": true,
+		"ret_msg": retMsg,
+		"op":      request.Op,
+	})
+	return reply, symbol
+}
+
+func (bybitStream) book(symbol string, ticker Ticker) []byte {
+	message, _ := json.Marshal(gin.H{
+		"topic": "orderbook.1." + symbol,
+		"type":  "snapshot",
+		"ts":    time.Now().UnixMilli(),
+		"data": gin.H{
+			"s": symbol,
+			"a": [][]string{{formatFloat(ticker.Ask), formatFloat(ticker.AskQty)}},
+			"b": [][]string{{formatFloat(ticker.Bid), formatFloat(ticker.BidQty)}},
+		},
+	})
+	return message
+}
+
+func (bybitStream) trade(symbol string, trade Trade) []byte {
+	side := "Sell"
+	if trade.Taker == models.Buy {
+		side = "Buy"
+	}
+	message, _ := json.Marshal(gin.H{
+		"topic": "publicTrade." + symbol,
+		"type":  "snapshot",
+		"ts":    time.Now().UnixMilli(),
+		"data": []bybitModels.RawStreamTrade{
+			{
+				Time:   trade.Time.UnixMilli(),
+				Symbol: symbol,
+				Side:   side,
+				Qty:    formatFloat(trade.Qty),
+				Price:  formatFloat(trade.Price),
+			},
+		},
+	})
+	return message
+}
+
+func (bybitStream) ping() []byte {
+	return nil
+}
+
+func (bybitStream) compressed() bool {
+	return false
+}
@@ -0,0 +1,489 @@
+package sim
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/imbonda/vmm-bot/pkg/exchanges"
+	"github.com/imbonda/vmm-bot/pkg/models"
+)
+
+// houseOwner owns the liquidity the engine provides by itself.
+const houseOwner = "house"
+
+var (
+	ErrUnknownSymbol = fmt.Errorf("unknown symbol")
+	ErrOrderNotFound = fmt.Errorf("order not found")
+	ErrInvalidOrder  = fmt.Errorf("invalid order")
+	// ErrInvalidPrecision is an invalid order whose price or qty isn't a multiple of the tick size or lot step.
+	ErrInvalidPrecision = fmt.Errorf("%w precision", ErrInvalidOrder)
+	// ErrDuplicateClientOrderID is an invalid order whose client order id was already used by its owner.
+	ErrDuplicateClientOrderID = fmt.Errorf("%w, duplicate client order id", ErrInvalidOrder)
+)
+
+// Order fees are charged in the received asset, base for buys and quote for sells.
+type Order struct {
+	ID            int
+	ClientOrderID string
+	Owner         string
+	Symbol        string
+	Action        models.OrderAction
+	Price         float64
+	Qty           float64
+	Filled        float64
+	FilledQuote   float64
+	Fee           float64
+	Status        models.OrderStatus
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+func (o *Order) Left() float64 {
+	return o.Qty - o.Filled
+}
+
+func (o *Order) AvgPrice() float64 {
+	if o.Filled == 0 {
+		return 0
+	}
+	return o.FilledQuote / o.Filled
+}
+
+type Trade struct {
+	ID     int
+	Symbol string
+	Price  float64
+	Qty    float64
+	Taker  models.OrderAction
+	Time   time.Time
+}
+
+// Fill is the execution of an order by a trade, the fee is charged in the received asset.
+type Fill struct {
+	TradeID int
+	OrderID int
+	Owner   string
+	Symbol  string
+	Action  models.OrderAction
+	Price   float64
+	Qty     float64
+	Fee     float64
+	Maker   bool
+	Time    time.Time
+}
+
+type Ticker struct {
+	Symbol    string
+	LastPrice float64
+	Ask       float64
+	AskQty    float64
+	Bid       float64
+	BidQty    float64
+}
+
+// Engine is a minimal price-time priority matching engine shared by all exchange dialects.
+type Engine struct {
+	mu          sync.Mutex
+	books       map[string]*book
+	orders      map[int]*Order
+	nextOrderID int
+	nextTradeID int
+	spread      float64
+	depth       float64
+	fee         float64
+	accounts    map[string]*account
+	balance     float64
+	balances    map[string]float64
+	rules       SymbolRules
+}
+
+type NewEngineInput struct {
+	// Markets maps a symbol (in any exchange dialect) to its initial price.
+	Markets map[string]float64
+	// Spread is the relative distance between the house bid and ask.
+	Spread float64
+	// Depth is the quantity of every house order.
+	Depth float64
+	// Fee is the rate charged on every fill, for makers and takers alike.
+	Fee float64
+	// Balance every account starts with in each asset of the markets.
+	Balance float64
+	// Balances overrides the initial balance of specific assets.
+	Balances map[string]float64
+	// Rules every market enforces on the placed orders, the symbol is ignored.
+	Rules SymbolRules
+}
+
+func NewEngine(input *NewEngineInput) *Engine {
+	engine := &Engine{
+		books:    make(map[string]*book),
+		orders:   make(map[int]*Order),
+		spread:   input.Spread,
+		depth:    input.Depth,
+		fee:      input.Fee,
+		accounts: make(map[string]*account),
+		balance:  input.Balance,
+		balances: make(map[string]float64),
+		rules:    input.Rules,
+	}
+	for asset, balance := range input.Balances {
+		engine.balances[strings.ToUpper(asset)] = balance
+	}
+	for symbol, price := range input.Markets {
+		key := NormalizeSymbol(symbol)
+		b := &book{symbol: key, lastPrice: price}
+		engine.books[key] = b
+		engine.ensureLiquidity(b)
+	}
+	return engine
+}
+
+// NormalizeSymbol maps the different exchange dialects (BTCUSDT, BTC_USDT, BTC-USDT) to a single key.
+func NormalizeSymbol(symbol string) string {
+	return strings.NewReplacer("_", "", "-", "", "/", "").Replace(strings.ToUpper(symbol))
+}
+
+// parsePair splits a symbol of any dialect into its base and quote, by its normalized key.
+func parsePair(symbol string) (models.Pair, error) {
+	return exchanges.ConcatenatedSymbolFormat.Parse(NormalizeSymbol(symbol))
+}
+
+// OrderOptions are the optional properties of a placed order, the zero value is a GTC limit order.
+type OrderOptions struct {
+	ClientOrderID string
+	// Market orders ignore their price, they sweep the book up to their qty and are cancelled when it runs out.
+	Market bool
+	// PostOnly orders are cancelled instead of taking liquidity.
+	PostOnly    bool
+	TimeInForce models.TimeInForce
+}
+
+// orderOptions parses the order type and time in force, which Bybit and BingX name alike up to the case. An empty time
+// in force is GTC.
+func orderOptions(orderType, timeInForce string) (OrderOptions, bool) {
+	var options OrderOptions
+	switch strings.ToUpper(orderType) {
+	case "LIMIT":
+	case "MARKET":
+		options.Market = true
+	default:
+		return options, false
+	}
+	switch strings.ToUpper(timeInForce) {
+	case "", "GTC":
+		options.TimeInForce = models.TimeInForceGTC
+	case "IOC":
+		options.TimeInForce = models.TimeInForceIOC
+	case "FOK":
+		options.TimeInForce = models.TimeInForceFOK
+	case "POSTONLY":
+		options.PostOnly = true
+		options.TimeInForce = models.TimeInForceGTC
+	default:
+		return options, false
+	}
+	return options, !(options.Market && options.PostOnly)
+}
+
+func (e *Engine) PlaceOrder(owner, symbol string, action models.OrderAction, price, qty float64, options OrderOptions) (Order, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	b, err := e.getBook(symbol)
+	if err != nil {
+		return Order{}, err
+	}
+	if qty <= 0 || (action != models.Buy && action != models.Sell) {
+		return Order{}, ErrInvalidOrder
+	}
+	rules := e.rules
+	rules.Symbol = b.symbol
+	if options.Market {
+		// The price of a market order is the worst price it sweeps, which is the price of a house order.
+		if price = b.sweepPrice(action, qty); price <= 0 {
+			return Order{}, fmt.Errorf("%w: no liquidity for a market order", ErrInvalidOrder)
+		}
+		rules.TickSize = 0
+	}
+	if price <= 0 {
+		return Order{}, ErrInvalidOrder
+	}
+	if err = rules.validate(price, qty); err != nil {
+		return Order{}, err
+	}
+	if _, found := e.findByClientID(owner, options.ClientOrderID); found {
+		return Order{}, fmt.Errorf("%w: %s", ErrDuplicateClientOrderID, options.ClientOrderID)
+	}
+	now := time.Now()
+	e.nextOrderID++
+	order := &Order{
+		ID:            e.nextOrderID,
+		ClientOrderID: options.ClientOrderID,
+		Owner:         owner,
+		Symbol:        b.symbol,
+		Action:        action,
+		Price:         price,
+		Qty:           qty,
+		Status:        models.OrderStatusNew,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	// A post only order which would take liquidity, and a FOK order which can't be filled in full, are cancelled
+	// without any fill.
+	available := b.available(order)
+	if (options.PostOnly && available > 0) || (options.TimeInForce == models.TimeInForceFOK && available < qty) {
+		order.Status = models.OrderStatusCancelled
+		e.orders[order.ID] = order
+		return *order, nil
+	}
+	if err = e.lock(order); err != nil {
+		return Order{}, err
+	}
+	e.orders[order.ID] = order
+	b.match(order, e.newTradeID, e.fee, e.settle)
+	switch {
+	case order.Left() <= 0:
+	case options.Market || options.TimeInForce == models.TimeInForceIOC || options.TimeInForce == models.TimeInForceFOK:
+		e.unlock(order, order.Left())
+		order.Status = models.OrderStatusCancelled
+	default:
+		b.rest(order)
+	}
+	e.ensureLiquidity(b)
+	return *order, nil
+}
+
+func (e *Engine) Cancel(owner, symbol string, orderID int) (Order, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	b, err := e.getBook(symbol)
+	if err != nil {
+		return Order{}, err
+	}
+	order, found := e.orders[orderID]
+	if !found || order.Owner != owner || order.Symbol != b.symbol || !isOpen(order) {
+		return Order{}, ErrOrderNotFound
+	}
+	b.remove(order)
+	e.unlock(order, order.Left())
+	order.Status = models.OrderStatusCancelled
+	order.UpdatedAt = time.Now()
+	e.ensureLiquidity(b)
+	return *order, nil
+}
+
+func (e *Engine) CancelAll(owner, symbol string) ([]Order, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	b, err := e.getBook(symbol)
+	if err != nil {
+		return nil, err
+	}
+	var cancelled []Order
+	for _, order := range b.ordersOf(owner) {
+		b.remove(order)
+		e.unlock(order, order.Left())
+		order.Status = models.OrderStatusCancelled
+		order.UpdatedAt = time.Now()
+		cancelled = append(cancelled, *order)
+	}
+	e.ensureLiquidity(b)
+	return cancelled, nil
+}
+
+func (e *Engine) GetOrder(owner string, orderID int) (Order, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	order, found := e.orders[orderID]
+	if !found || order.Owner != owner {
+		return Order{}, ErrOrderNotFound
+	}
+	return *order, nil
+}
+
+func (e *Engine) GetOrderByClientID(owner string, clientOrderID string) (Order, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	order, found := e.findByClientID(owner, clientOrderID)
+	if !found {
+		return Order{}, ErrOrderNotFound
+	}
+	return *order, nil
+}
+
+func (e *Engine) findByClientID(owner string, clientOrderID string) (*Order, bool) {
+	if clientOrderID == "" {
+		return nil, false
+	}
+	for _, order := range e.orders {
+		if order.Owner == owner && order.ClientOrderID == clientOrderID {
+			return order, true
+		}
+	}
+	return nil, false
+}
+
+func (e *Engine) OpenOrders(owner, symbol string) ([]Order, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	b, err := e.getBook(symbol)
+	if err != nil {
+		return nil, err
+	}
+	var open []Order
+	for _, order := range b.ordersOf(owner) {
+		open = append(open, *order)
+	}
+	return open, nil
+}
+
+// Fills returns the fills of the owner since the given time, oldest first.
+func (e *Engine) Fills(owner, symbol string, since time.Time) ([]Fill, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	b, err := e.getBook(symbol)
+	if err != nil {
+		return nil, err
+	}
+	var fills []Fill
+	for _, fill := range b.fills {
+		if fill.Owner == owner && !fill.Time.Before(since) {
+			fills = append(fills, fill)
+		}
+	}
+	return fills, nil
+}
+
+// FinishedOrders returns the filled or cancelled orders of the owner updated within the given range, oldest first.
+func (e *Engine) FinishedOrders(owner, symbol string, start, end time.Time) ([]Order, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	b, err := e.getBook(symbol)
+	if err != nil {
+		return nil, err
+	}
+	var finished []Order
+	for _, order := range e.orders {
+		if order.Owner != owner || order.Symbol != b.symbol || isOpen(order) {
+			continue
+		}
+		if order.UpdatedAt.Before(start) || order.UpdatedAt.After(end) {
+			continue
+		}
+		finished = append(finished, *order)
+	}
+	slices.SortFunc(finished, func(a, b Order) int {
+		return a.UpdatedAt.Compare(b.UpdatedAt)
+	})
+	return finished, nil
+}
+
+func (e *Engine) Depth(symbol string, limit int) (bids [][2]float64, asks [][2]float64, err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	b, err := e.getBook(symbol)
+	if err != nil {
+		return nil, nil, err
+	}
+	return b.levels(b.bids, limit), b.levels(b.asks, limit), nil
+}
+
+func (e *Engine) Ticker(symbol string) (Ticker, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	b, err := e.getBook(symbol)
+	if err != nil {
+		return Ticker{}, err
+	}
+	ticker := Ticker{
+		Symbol:    b.symbol,
+		LastPrice: b.lastPrice,
+	}
+	if bids := b.levels(b.bids, 1); len(bids) > 0 {
+		ticker.Bid, ticker.BidQty = bids[0][0], bids[0][1]
+	}
+	if asks := b.levels(b.asks, 1); len(asks) > 0 {
+		ticker.Ask, ticker.AskQty = asks[0][0], asks[0][1]
+	}
+	return ticker, nil
+}
+
+func (e *Engine) Tickers() []Ticker {
+	e.mu.Lock()
+	symbols := make([]string, 0, len(e.books))
+	for symbol := range e.books {
+		symbols = append(symbols, symbol)
+	}
+	e.mu.Unlock()
+	tickers := make([]Ticker, 0, len(symbols))
+	for _, symbol := range symbols {
+		if ticker, err := e.Ticker(symbol); err == nil {
+			tickers = append(tickers, ticker)
+		}
+	}
+	return tickers
+}
+
+func (e *Engine) RecentTrades(symbol string, limit int) ([]Trade, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	b, err := e.getBook(symbol)
+	if err != nil {
+		return nil, err
+	}
+	trades := make([]Trade, 0, limit)
+	for i := len(b.trades) - 1; i >= 0 && len(trades) < limit; i-- {
+		trades = append(trades, b.trades[i])
+	}
+	return trades, nil
+}
+
+func (e *Engine) getBook(symbol string) (*book, error) {
+	b, found := e.books[NormalizeSymbol(symbol)]
+	if !found {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownSymbol, symbol)
+	}
+	return b, nil
+}
+
+func (e *Engine) newTradeID() int {
+	e.nextTradeID++
+	return e.nextTradeID
+}
+
+// ensureLiquidity keeps a house order on every side of the book, so there's always a spread to trade in.
+func (e *Engine) ensureLiquidity(b *book) {
+	if e.depth <= 0 {
+		return
+	}
+	sides := map[models.OrderAction]float64{
+		models.Buy:  b.lastPrice * (1 - e.spread/2),
+		models.Sell: b.lastPrice * (1 + e.spread/2),
+	}
+	for action, price := range sides {
+		if len(b.side(action)) > 0 {
+			continue
+		}
+		now := time.Now()
+		e.nextOrderID++
+		order := &Order{
+			ID:        e.nextOrderID,
+			Owner:     houseOwner,
+			Symbol:    b.symbol,
+			Action:    action,
+			Price:     price,
+			Qty:       e.depth,
+			Status:    models.OrderStatusNew,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		e.orders[order.ID] = order
+		b.rest(order)
+	}
+}
+
+func isOpen(order *Order) bool {
+	return order.Status == models.OrderStatusNew || order.Status == models.OrderStatusPartiallyFilled
+}
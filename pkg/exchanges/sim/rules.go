@@ -0,0 +1,84 @@
+package sim
+
+import (
+	"fmt"
+	"math"
+	"slices"
+
+	exchangeErrors "github.com/imbonda/vmm-bot/pkg/exchanges/errors"
+)
+
+// stepTolerance absorbs the float error of prices and quantities sent as decimal strings.
+const stepTolerance = 1e-6
+
+var ErrSymbolHalted = exchangeErrors.ErrSymbolHalted
+
+// SymbolRules are the trading rules of a market, zero values aren't enforced.
+type SymbolRules struct {
+	Symbol      string
+	TickSize    float64
+	LotStep     float64
+	MinQty      float64
+	MinNotional float64
+	Halted      bool
+}
+
+// Rules returns the trading rules of the market, every market of the engine shares the same rules.
+func (e *Engine) Rules(symbol string) (SymbolRules, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	b, err := e.getBook(symbol)
+	if err != nil {
+		return SymbolRules{}, err
+	}
+	rules := e.rules
+	rules.Symbol = b.symbol
+	return rules, nil
+}
+
+// AllRules returns the trading rules of every market, sorted by symbol.
+func (e *Engine) AllRules() []SymbolRules {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	all := make([]SymbolRules, 0, len(e.books))
+	for symbol := range e.books {
+		rules := e.rules
+		rules.Symbol = symbol
+		all = append(all, rules)
+	}
+	slices.SortFunc(all, func(a, b SymbolRules) int {
+		if a.Symbol < b.Symbol {
+			return -1
+		}
+		if a.Symbol > b.Symbol {
+			return 1
+		}
+		return 0
+	})
+	return all
+}
+
+// validate rejects orders of halted markets and orders breaking the tick size, lot step or minimums.
+func (r SymbolRules) validate(price, qty float64) error {
+	switch {
+	case r.Halted:
+		return fmt.Errorf("%w: %s", ErrSymbolHalted, r.Symbol)
+	case !isMultipleOf(price, r.TickSize):
+		return fmt.Errorf("%w: price %f is not a multiple of the tick size %f", ErrInvalidPrecision, price, r.TickSize)
+	case !isMultipleOf(qty, r.LotStep):
+		return fmt.Errorf("%w: qty %f is not a multiple of the lot step %f", ErrInvalidPrecision, qty, r.LotStep)
+	case qty < r.MinQty:
+		return fmt.Errorf("%w: qty %f is below the min qty %f", ErrInvalidOrder, qty, r.MinQty)
+	case price*qty < r.MinNotional:
+		return fmt.Errorf("%w: notional %f is below the min notional %f", ErrInvalidOrder, price*qty, r.MinNotional)
+	}
+	return nil
+}
+
+func isMultipleOf(value, step float64) bool {
+	if step <= 0 {
+		return true
+	}
+	steps := value / step
+	return math.Abs(steps-math.Round(steps)) < stepTolerance
+}
@@ -0,0 +1,130 @@
+package sim
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+
+	"github.com/imbonda/vmm-bot/pkg/utils"
+)
+
+const paramsKey = "params"
+
+// Server exposes the engine through the REST dialects of all supported exchanges on a single address.
+type Server struct {
+	addr     string
+	server   *http.Server
+	engine   *Engine
+	bybit    *utils.Credentials
+	biconomy *utils.Credentials
+	bingx    *utils.Credentials
+	logger   log.Logger
+}
+
+type NewServerInput struct {
+	ListenAddress string
+	Engine        *Engine
+	Bybit         *utils.Credentials
+	Biconomy      *utils.Credentials
+	BingX         *utils.Credentials
+	Logger        log.Logger
+}
+
+func NewServer(input *NewServerInput) *Server {
+	gin.SetMode(gin.ReleaseMode)
+	router := gin.New()
+	router.Use(gin.Recovery())
+
+	server := &Server{
+		addr: input.ListenAddress,
+		server: &http.Server{
+			Addr:    input.ListenAddress,
+			Handler: router,
+		},
+		engine:   input.Engine,
+		bybit:    input.Bybit,
+		biconomy: input.Biconomy,
+		bingx:    input.BingX,
+		logger:   input.Logger,
+	}
+
+	server.registerBybitRoutes(router)
+	server.registerBiconomyRoutes(router)
+	server.registerBingXRoutes(router)
+
+	return server
+}
+
+func (s *Server) Handler() http.Handler {
+	return s.server.Handler
+}
+
+func (s *Server) Start(ctx context.Context) error {
+	go func() {
+		level.Info(s.logger).Log("msg", "starting mock exchange server", "address", s.addr)
+
+		err := s.server.ListenAndServe()
+		if err != nil && err != http.ErrServerClosed {
+			level.Error(s.logger).Log("msg", "error starting mock exchange server", "err", err)
+		}
+	}()
+	return nil
+}
+
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.server.Shutdown(ctx)
+}
+
+// readForm parses the request parameters from the query string or the url-encoded body.
+func readForm(c *gin.Context) (url.Values, error) {
+	if c.Request.Method == http.MethodGet {
+		return c.Request.URL.Query(), nil
+	}
+	body, err := c.GetRawData()
+	if err != nil {
+		return nil, err
+	}
+	return url.ParseQuery(string(body))
+}
+
+func params(c *gin.Context) url.Values {
+	if values, found := c.Get(paramsKey); found {
+		return values.(url.Values)
+	}
+	return c.Request.URL.Query()
+}
+
+func withoutParam(values url.Values, key string) url.Values {
+	clone := url.Values{}
+	for k, v := range values {
+		if k != key {
+			clone[k] = v
+		}
+	}
+	return clone
+}
+
+// splitSymbol re-inserts the separator between base and quote of a normalized symbol.
+func splitSymbol(symbol, separator string) string {
+	pair, err := parsePair(symbol)
+	if err != nil {
+		return symbol
+	}
+	return pair.Base + separator + pair.Quote
+}
+
+func formatFloat(value float64) string {
+	return utils.FormatFloatToString(value, -1)
+}
+
+func formatLevels(levels [][2]float64) [][]string {
+	formatted := make([][]string, 0, len(levels))
+	for _, level := range levels {
+		formatted = append(formatted, []string{formatFloat(level[0]), formatFloat(level[1])})
+	}
+	return formatted
+}
@@ -0,0 +1,54 @@
+package sim
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// The signatures are implemented here from the exchange docs rather than taken from the clients,
+// so a signing bug in a client doesn't pass on both sides.
+
+// bybitSignature is the hex HMAC-SHA256 of timestamp + api key + recv window + payload.
+func bybitSignature(timestamp, apiKey, recvWindow, payload, secret string) string {
+	return hmacSHA256(timestamp+apiKey+recvWindow+payload, secret)
+}
+
+// biconomySignature is the upper-cased hex MD5 of the sorted parameters followed by the secret key.
+func biconomySignature(values url.Values, secret string) string {
+	payload := sortedParams(values)
+	if payload != "" {
+		payload += "&"
+	}
+	sum := md5.Sum([]byte(payload + "secret_key=" + secret))
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
+
+// bingxSignature is the hex HMAC-SHA256 of the sorted parameters.
+func bingxSignature(values url.Values, secret string) string {
+	return hmacSHA256(sortedParams(values), secret)
+}
+
+func hmacSHA256(payload, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// sortedParams joins the unescaped parameters as key=value pairs sorted by key.
+func sortedParams(values url.Values) string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, key+"="+strings.Join(values[key], ","))
+	}
+	return strings.Join(pairs, "&")
+}
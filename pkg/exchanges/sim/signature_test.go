@@ -0,0 +1,37 @@
+package sim
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// The expected signatures were computed with openssl dgst, apart from any Go code.
+
+func TestBybitSignature(t *testing.T) {
+	signature := bybitSignature("1658385579423", "XXXXXXXXXX", "5000", "category=spot&symbol=BTCUSDT", "YYYYYYYYYY")
+	assert.Equal(t, "9a53e67c417076242d9fc43a37403cc69e5969fd3cb23e5a7e81e19505a7aa6d", signature)
+}
+
+func TestBiconomySignature(t *testing.T) {
+	values := url.Values{
+		"side":    {"2"},
+		"price":   {"100"},
+		"market":  {"BTC_USDT"},
+		"api_key": {"xk"},
+		"amount":  {"1"},
+	}
+	assert.Equal(t, "752A860DCE2B65DDF74930C89B1C0E86", biconomySignature(values, "xs"))
+}
+
+func TestBingXSignature(t *testing.T) {
+	values := url.Values{
+		"type":      {"LIMIT"},
+		"timestamp": {"1700000000000"},
+		"symbol":    {"BTC-USDT"},
+		"side":      {"BUY"},
+		"quantity":  {"1"},
+	}
+	assert.Equal(t, "4eb9c296e2500980c281dadad3756798e55427443f0f7df6f4334de66573ebb4", bingxSignature(values, "xs"))
+}
@@ -0,0 +1,136 @@
+package sim
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-kit/log/level"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	streamPushInterval = 200 * time.Millisecond
+	streamPingInterval = 5 * time.Second
+)
+
+var upgrader = websocket.Upgrader{
+	CheckOrigin: func(*http.Request) bool { return true },
+}
+
+// streamDialect encodes the engine market data in the public websocket protocol of an exchange.
+type streamDialect interface {
+	// handle answers a client message, returning the symbol it subscribes to if any.
+	handle(message []byte) (reply []byte, symbol string)
+	book(symbol string, ticker Ticker) []byte
+	trade(symbol string, trade Trade) []byte
+	// ping returns the heartbeat sent by the server, nil when the client pings.
+	ping() []byte
+	compressed() bool
+}
+
+// serveStream pushes the top of book and the last trade of the subscribed symbols whenever they change.
+func (s *Server) serveStream(c *gin.Context, dialect streamDialect) {
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		level.Warn(s.logger).Log("msg", "failed upgrading stream", "err", err)
+		return
+	}
+	defer conn.Close()
+
+	var mu sync.Mutex
+	write := func(message []byte) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if dialect.compressed() {
+			var buf bytes.Buffer
+			writer := gzip.NewWriter(&buf)
+			if _, err := writer.Write(message); err != nil {
+				return err
+			}
+			if err := writer.Close(); err != nil {
+				return err
+			}
+			return conn.WriteMessage(websocket.BinaryMessage, buf.Bytes())
+		}
+		return conn.WriteMessage(websocket.TextMessage, message)
+	}
+
+	var subsMu sync.Mutex
+	subscriptions := make(map[string]bool)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			_, message, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			reply, symbol := dialect.handle(message)
+			if symbol != "" {
+				subsMu.Lock()
+				subscriptions[symbol] = true
+				subsMu.Unlock()
+			}
+			if reply != nil && write(reply) != nil {
+				return
+			}
+		}
+	}()
+
+	pushed := make(map[string]Ticker)
+	lastTrades := make(map[string]int)
+	push := time.NewTicker(streamPushInterval)
+	defer push.Stop()
+	ping := time.NewTicker(streamPingInterval)
+	defer ping.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ping.C:
+			if message := dialect.ping(); message != nil && write(message) != nil {
+				return
+			}
+		case <-push.C:
+			subsMu.Lock()
+			symbols := make([]string, 0, len(subscriptions))
+			for symbol := range subscriptions {
+				symbols = append(symbols, symbol)
+			}
+			subsMu.Unlock()
+			for _, symbol := range symbols {
+				if err := s.pushStream(symbol, dialect, write, pushed, lastTrades); err != nil {
+					return
+				}
+			}
+		}
+	}
+}
+
+func (s *Server) pushStream(symbol string, dialect streamDialect, write func([]byte) error, pushed map[string]Ticker, lastTrades map[string]int) error {
+	ticker, err := s.engine.Ticker(symbol)
+	if err != nil {
+		return nil
+	}
+	if previous, found := pushed[symbol]; !found || previous.Ask != ticker.Ask || previous.Bid != ticker.Bid {
+		if err = write(dialect.book(symbol, ticker)); err != nil {
+			return err
+		}
+		pushed[symbol] = ticker
+	}
+	trades, err := s.engine.RecentTrades(symbol, 1)
+	if err != nil || len(trades) == 0 {
+		return nil
+	}
+	if trades[0].ID != lastTrades[symbol] {
+		if err = write(dialect.trade(symbol, trades[0])); err != nil {
+			return err
+		}
+		lastTrades[symbol] = trades[0].ID
+	}
+	return nil
+}
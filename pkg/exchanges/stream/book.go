@@ -0,0 +1,124 @@
+package stream
+
+import (
+	"sync"
+	"time"
+
+	"github.com/imbonda/vmm-bot/pkg/models"
+	"github.com/imbonda/vmm-bot/pkg/utils"
+)
+
+// TopOfBook is the locally cached best ask, best bid and last traded price of a symbol.
+type TopOfBook struct {
+	mu        sync.RWMutex
+	symbol    string
+	ask       string
+	bid       string
+	lastPrice string
+	// updatedAt is the time of the last update of the best ask and bid.
+	updatedAt time.Time
+	// Levels of dialects streaming incremental depth updates.
+	asks map[string]string
+	bids map[string]string
+}
+
+func newTopOfBook(symbol string) *TopOfBook {
+	return &TopOfBook{
+		symbol: symbol,
+		asks:   make(map[string]string),
+		bids:   make(map[string]string),
+	}
+}
+
+// SetBook replaces the best ask and bid.
+func (b *TopOfBook) SetBook(ask, bid string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.ask, b.bid = ask, bid
+	b.updatedAt = time.Now()
+}
+
+// ApplyLevels updates the depth with [price, qty] levels, a zero qty removes the level.
+// When clean is set the levels replace the whole depth.
+func (b *TopOfBook) ApplyLevels(clean bool, asks, bids [][]string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if clean {
+		clear(b.asks)
+		clear(b.bids)
+	}
+	applyLevels(b.asks, asks)
+	applyLevels(b.bids, bids)
+	b.ask = bestLevel(b.asks, func(price, best float64) bool { return price < best })
+	b.bid = bestLevel(b.bids, func(price, best float64) bool { return price > best })
+	b.updatedAt = time.Now()
+}
+
+func (b *TopOfBook) SetLastPrice(price string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.lastPrice = price
+}
+
+// SeedLastPrice sets the last price only if no trade was streamed yet.
+func (b *TopOfBook) SeedLastPrice(price string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.lastPrice == "" {
+		b.lastPrice = price
+	}
+}
+
+// Ticker returns false until both sides of the book and a last price are known.
+func (b *TopOfBook) Ticker() (*models.Ticker, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.ask == "" || b.bid == "" || b.lastPrice == "" {
+		return nil, false
+	}
+	return &models.Ticker{
+		Symbol:    b.symbol,
+		LastPrice: b.lastPrice,
+		BestAsk:   b.ask,
+		BestBid:   b.bid,
+		Time:      b.updatedAt,
+	}, true
+}
+
+// reset drops the book of a lost connection, the last price is kept as it doesn't go stale.
+func (b *TopOfBook) reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.ask, b.bid = "", ""
+	clear(b.asks)
+	clear(b.bids)
+}
+
+func applyLevels(side map[string]string, levels [][]string) {
+	for _, level := range levels {
+		if len(level) < 2 {
+			continue
+		}
+		qty, err := utils.ParseFloat(level[1])
+		if err != nil || qty == 0 {
+			delete(side, level[0])
+			continue
+		}
+		side[level[0]] = level[1]
+	}
+}
+
+func bestLevel(side map[string]string, better func(price, best float64) bool) string {
+	var best string
+	var bestPrice float64
+	for price := range side {
+		value, err := utils.ParseFloat(price)
+		if err != nil {
+			continue
+		}
+		if best == "" || better(value, bestPrice) {
+			best, bestPrice = price, value
+		}
+	}
+	return best
+}
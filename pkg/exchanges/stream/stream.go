@@ -0,0 +1,214 @@
+package stream
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/gorilla/websocket"
+
+	"github.com/imbonda/vmm-bot/pkg/models"
+)
+
+const maxReconnectDelay = 30 * time.Second
+
+// Dialect adapts a stream to the public market data websocket protocol of an exchange.
+type Dialect interface {
+	// URL of the public market data stream.
+	URL() string
+	// Subscribe returns the messages subscribing to the book ticker and trades of a symbol.
+	Subscribe(symbol string) ([][]byte, error)
+	// Ping returns the heartbeat message, nil when the exchange pings the client instead.
+	Ping() []byte
+	// Handle decodes a message into the book, and returns a reply to send back if any.
+	Handle(message []byte, book *TopOfBook) ([]byte, error)
+}
+
+// Stream keeps the top-of-book of a single symbol up to date over a websocket,
+// reconnecting whenever the connection fails or goes silent.
+type Stream struct {
+	dialect        Dialect
+	symbol         string
+	book           *TopOfBook
+	pingInterval   time.Duration
+	staleAfter     time.Duration
+	reconnectDelay time.Duration
+	logger         log.Logger
+	connected      atomic.Bool
+	lastMessageAt  atomic.Int64
+	writeMu        sync.Mutex
+	stopChan       chan struct{}
+	stopOnce       sync.Once
+}
+
+type NewStreamInput struct {
+	Dialect        Dialect
+	Symbol         string
+	PingInterval   time.Duration
+	StaleAfter     time.Duration
+	ReconnectDelay time.Duration
+	Logger         log.Logger
+}
+
+func NewStream(input *NewStreamInput) *Stream {
+	return &Stream{
+		dialect:        input.Dialect,
+		symbol:         input.Symbol,
+		book:           newTopOfBook(input.Symbol),
+		pingInterval:   input.PingInterval,
+		staleAfter:     input.StaleAfter,
+		reconnectDelay: input.ReconnectDelay,
+		logger:         log.With(input.Logger, "stream", input.Dialect.URL(), "streamSymbol", input.Symbol),
+		stopChan:       make(chan struct{}),
+	}
+}
+
+func (s *Stream) Start(ctx context.Context) {
+	go s.run(ctx)
+}
+
+func (s *Stream) Close() {
+	s.stopOnce.Do(func() {
+		close(s.stopChan)
+	})
+}
+
+// Ticker returns the cached ticker, or false while the stream is disconnected, stale or not yet synced.
+func (s *Stream) Ticker() (*models.Ticker, bool) {
+	if !s.connected.Load() {
+		return nil, false
+	}
+	if time.Since(time.UnixMilli(s.lastMessageAt.Load())) > s.staleAfter {
+		return nil, false
+	}
+	return s.book.Ticker()
+}
+
+// SeedLastPrice provides a last price until the first trade is streamed.
+func (s *Stream) SeedLastPrice(price string) {
+	s.book.SeedLastPrice(price)
+}
+
+func (s *Stream) run(ctx context.Context) {
+	delay := s.reconnectDelay
+	for {
+		synced, err := s.connect(ctx)
+		if synced {
+			delay = s.reconnectDelay
+		}
+		level.Warn(s.logger).Log("msg", "stream disconnected", "err", err, "reconnectIn", delay)
+		select {
+		case <-s.stopChan:
+			return
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, maxReconnectDelay)
+	}
+}
+
+// connect runs a single connection until it fails, reporting whether any message was received.
+func (s *Stream) connect(ctx context.Context) (bool, error) {
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, s.dialect.URL(), nil)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+	defer s.connected.Store(false)
+	s.book.reset()
+
+	messages, err := s.dialect.Subscribe(s.symbol)
+	if err != nil {
+		return false, err
+	}
+	for _, message := range messages {
+		if err = s.write(conn, message); err != nil {
+			return false, err
+		}
+	}
+	level.Info(s.logger).Log("msg", "stream connected")
+
+	done := make(chan struct{})
+	defer close(done)
+	go s.heartbeat(conn, done)
+
+	synced := false
+	for {
+		if err = conn.SetReadDeadline(time.Now().Add(s.staleAfter)); err != nil {
+			return synced, err
+		}
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			return synced, err
+		}
+		synced = true
+		s.lastMessageAt.Store(time.Now().UnixMilli())
+		s.connected.Store(true)
+		if message, err = decompress(message); err != nil {
+			return synced, err
+		}
+		reply, err := s.dialect.Handle(message, s.book)
+		if err != nil {
+			level.Warn(s.logger).Log("msg", "failed handling stream message", "err", err)
+			continue
+		}
+		if reply != nil {
+			if err = s.write(conn, reply); err != nil {
+				return synced, err
+			}
+		}
+	}
+}
+
+func (s *Stream) heartbeat(conn *websocket.Conn, done chan struct{}) {
+	ping := s.dialect.Ping()
+	var pings <-chan time.Time
+	if ping != nil && s.pingInterval > 0 {
+		ticker := time.NewTicker(s.pingInterval)
+		defer ticker.Stop()
+		pings = ticker.C
+	}
+	for {
+		select {
+		case <-done:
+			return
+		case <-s.stopChan:
+			// Unblocks the read loop.
+			conn.Close()
+			return
+		case <-pings:
+			if err := s.write(conn, ping); err != nil {
+				level.Warn(s.logger).Log("msg", "failed sending stream ping", "err", err)
+				return
+			}
+		}
+	}
+}
+
+// write serializes the writes, websocket connections support a single concurrent writer.
+func (s *Stream) write(conn *websocket.Conn, message []byte) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	return conn.WriteMessage(websocket.TextMessage, message)
+}
+
+// decompress inflates gzip compressed messages, some exchanges compress every message.
+func decompress(message []byte) ([]byte, error) {
+	if len(message) < 2 || message[0] != 0x1f || message[1] != 0x8b {
+		return message, nil
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(message))
+	if err != nil {
+		return nil, fmt.Errorf("failed decompressing stream message: %w", err)
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
@@ -0,0 +1,94 @@
+package exchanges
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/imbonda/vmm-bot/pkg/models"
+)
+
+// knownQuotes are the quote currencies recognized when a symbol has no base/quote separator.
+var knownQuotes = []string{"USDT", "USDC", "BTC", "ETH"}
+
+// SymbolFormat translates between canonical pairs and the symbols of an exchange.
+type SymbolFormat struct {
+	Format func(pair models.Pair) string
+	Parse  func(symbol string) (models.Pair, error)
+}
+
+var (
+	symbolFormatsMu sync.RWMutex
+	symbolFormats   = make(map[Exchange]SymbolFormat)
+)
+
+// RegisterSymbolFormat is called by every exchange adapter to register the symbol format of its exchange.
+func RegisterSymbolFormat(exchange Exchange, format SymbolFormat) {
+	symbolFormatsMu.Lock()
+	defer symbolFormatsMu.Unlock()
+	symbolFormats[exchange] = format
+}
+
+// FormatSymbol writes the pair in the symbol format of the exchange.
+func FormatSymbol(exchange Exchange, pair models.Pair) (string, error) {
+	format, err := getSymbolFormat(exchange)
+	if err != nil {
+		return "", err
+	}
+	return format.Format(pair), nil
+}
+
+// ParseSymbol reads a symbol written in the symbol format of the exchange.
+func ParseSymbol(exchange Exchange, symbol string) (models.Pair, error) {
+	format, err := getSymbolFormat(exchange)
+	if err != nil {
+		return models.Pair{}, err
+	}
+	return format.Parse(symbol)
+}
+
+func getSymbolFormat(exchange Exchange) (SymbolFormat, error) {
+	symbolFormatsMu.RLock()
+	defer symbolFormatsMu.RUnlock()
+	format, found := symbolFormats[exchange]
+	if !found {
+		return SymbolFormat{}, fmt.Errorf("no symbol format registered for exchange: %s", exchange)
+	}
+	return format, nil
+}
+
+// SeparatedSymbolFormat writes the base and quote joined by the separator, e.g. STOP_USDT.
+func SeparatedSymbolFormat(separator string) SymbolFormat {
+	return SymbolFormat{
+		Format: func(pair models.Pair) string {
+			return pair.Base + separator + pair.Quote
+		},
+		Parse: func(symbol string) (models.Pair, error) {
+			base, quote, found := strings.Cut(strings.ToUpper(symbol), separator)
+			if !found || base == "" || quote == "" {
+				return models.Pair{}, fmt.Errorf("invalid symbol: %s, expected BASE%sQUOTE", symbol, separator)
+			}
+			return models.Pair{Base: base, Quote: quote}, nil
+		},
+	}
+}
+
+// ConcatenatedSymbolFormat writes the base and quote without a separator, e.g. STOPUSDT.
+// Such symbols can only be parsed when the quote is one of the known quotes.
+var ConcatenatedSymbolFormat = SymbolFormat{
+	Format: func(pair models.Pair) string {
+		return pair.Base + pair.Quote
+	},
+	Parse: func(symbol string) (models.Pair, error) {
+		symbol = strings.ToUpper(symbol)
+		if strings.ContainsAny(symbol, "_-/") {
+			return models.Pair{}, fmt.Errorf("invalid symbol: %s, expected BASEQUOTE", symbol)
+		}
+		for _, quote := range knownQuotes {
+			if base, found := strings.CutSuffix(symbol, quote); found && base != "" {
+				return models.Pair{Base: base, Quote: quote}, nil
+			}
+		}
+		return models.Pair{}, fmt.Errorf("unknown quote of symbol: %s, write it as BASE/QUOTE", symbol)
+	},
+}
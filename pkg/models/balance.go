@@ -0,0 +1,56 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/imbonda/vmm-bot/pkg/utils"
+)
+
+// Balance of a single asset, the free amount is available for new orders while the locked one backs open orders.
+type Balance struct {
+	Asset  string `json:"asset"`
+	Free   string `json:"free"`
+	Locked string `json:"locked"`
+}
+
+func (b *Balance) FreeAmount() (float64, error) {
+	return parseAmount(b.Asset, "free", b.Free)
+}
+
+func (b *Balance) LockedAmount() (float64, error) {
+	return parseAmount(b.Asset, "locked", b.Locked)
+}
+
+func (b *Balance) TotalAmount() (float64, error) {
+	free, err := b.FreeAmount()
+	if err != nil {
+		return 0, err
+	}
+	locked, err := b.LockedAmount()
+	if err != nil {
+		return 0, err
+	}
+	return free + locked, nil
+}
+
+// FindBalance returns the balance of the asset, assets missing from the balances have none.
+func FindBalance(balances []*Balance, asset string) *Balance {
+	for _, balance := range balances {
+		if strings.EqualFold(balance.Asset, asset) {
+			return balance
+		}
+	}
+	return &Balance{Asset: asset}
+}
+
+func parseAmount(asset, kind, amount string) (float64, error) {
+	if amount == "" {
+		return 0, nil
+	}
+	value, err := utils.ParseFloat(amount)
+	if err != nil {
+		return 0, fmt.Errorf("failed parse %s %s balance: %s", asset, kind, amount)
+	}
+	return value, nil
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+type CircuitState string
+
+const (
+	CircuitClosed   CircuitState = "closed"
+	CircuitOpen     CircuitState = "open"
+	CircuitHalfOpen CircuitState = "half_open"
+)
+
+// CircuitStatus is the state of the circuit breaker of an exchange client. An open circuit fails the requests without
+// sending them, until a trial request succeeds after its cooldown.
+type CircuitStatus struct {
+	Exchange            string       `json:"exchange"`
+	State               CircuitState `json:"state"`
+	ConsecutiveFailures int          `json:"consecutiveFailures"`
+	OpenedAt            *time.Time   `json:"openedAt,omitempty"`
+}
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/imbonda/vmm-bot/pkg/utils"
+)
+
+// Fill is an execution of an order, the quantity is in base currency and the fee in FeeAsset.
+type Fill struct {
+	Symbol   string      `json:"symbol"`
+	TradeID  string      `json:"tradeId"`
+	OrderID  string      `json:"orderId"`
+	Action   OrderAction `json:"action"`
+	Price    string      `json:"price"`
+	Qty      string      `json:"qty"`
+	Fee      string      `json:"fee"`
+	FeeAsset string      `json:"feeAsset"`
+	Maker    bool        `json:"maker"`
+	Time     time.Time   `json:"time"`
+}
+
+type FillsSummary struct {
+	Fills        int     `json:"fills"`
+	BuyQty       float64 `json:"buyQty"`
+	BuyQuote     float64 `json:"buyQuote"`
+	AvgBuyPrice  float64 `json:"avgBuyPrice"`
+	SellQty      float64 `json:"sellQty"`
+	SellQuote    float64 `json:"sellQuote"`
+	AvgSellPrice float64 `json:"avgSellPrice"`
+	// Fees paid per asset.
+	Fees map[string]float64 `json:"fees"`
+}
+
+type FillsOutput struct {
+	Since   time.Time     `json:"since"`
+	Fills   []*Fill       `json:"fills"`
+	Summary *FillsSummary `json:"summary"`
+}
+
+// SummarizeFills aggregates the executed volume, average prices and fees of the fills.
+func SummarizeFills(fills []*Fill) (*FillsSummary, error) {
+	summary := &FillsSummary{
+		Fills: len(fills),
+		Fees:  make(map[string]float64),
+	}
+	for _, fill := range fills {
+		price, err := utils.ParseFloat(fill.Price)
+		if err != nil {
+			return nil, fmt.Errorf("failed parse fill price: %s", fill.Price)
+		}
+		qty, err := utils.ParseFloat(fill.Qty)
+		if err != nil {
+			return nil, fmt.Errorf("failed parse fill qty: %s", fill.Qty)
+		}
+		if fill.Fee != "" {
+			fee, err := utils.ParseFloat(fill.Fee)
+			if err != nil {
+				return nil, fmt.Errorf("failed parse fill fee: %s", fill.Fee)
+			}
+			summary.Fees[fill.FeeAsset] += fee
+		}
+		if fill.Action == Buy {
+			summary.BuyQty += qty
+			summary.BuyQuote += price * qty
+		} else {
+			summary.SellQty += qty
+			summary.SellQuote += price * qty
+		}
+	}
+	if summary.BuyQty > 0 {
+		summary.AvgBuyPrice = summary.BuyQuote / summary.BuyQty
+	}
+	if summary.SellQty > 0 {
+		summary.AvgSellPrice = summary.SellQuote / summary.SellQty
+	}
+	return summary, nil
+}
@@ -0,0 +1,25 @@
+package models
+
+// PairHealth reports the probes of a pair, Problems explains why they fail.
+type PairHealth struct {
+	Pair     string   `json:"pair"`
+	Live     bool     `json:"live"`
+	Ready    bool     `json:"ready"`
+	Problems []string `json:"problems,omitempty"`
+}
+
+// Health reports the probes of the service, which fail when the probe of any pair does.
+type Health struct {
+	Live  bool          `json:"live"`
+	Ready bool          `json:"ready"`
+	Pairs []*PairHealth `json:"pairs"`
+}
+
+func NewHealth(pairs []*PairHealth) *Health {
+	health := &Health{Live: true, Ready: true, Pairs: pairs}
+	for _, pair := range pairs {
+		health.Live = health.Live && pair.Live
+		health.Ready = health.Ready && pair.Ready
+	}
+	return health
+}
@@ -0,0 +1,16 @@
+package models
+
+// InventorySkew describes how the quotes of an iteration were skewed toward the target inventory ratio.
+type InventorySkew struct {
+	Base  float64 `json:"base"`
+	Quote float64 `json:"quote"`
+	// Ratio is the share of the base currency value in the inventory value.
+	Ratio       float64 `json:"ratio"`
+	TargetRatio float64 `json:"targetRatio"`
+	// Deviation from the target ratio in [-1, 1], positive when holding too much base and -1/1 at the bounds.
+	Deviation     float64 `json:"deviation"`
+	SellQtyFactor float64 `json:"sellQtyFactor"`
+	BuyQtyFactor  float64 `json:"buyQtyFactor"`
+	SellStopped   bool    `json:"sellStopped"`
+	BuyStopped    bool    `json:"buyStopped"`
+}
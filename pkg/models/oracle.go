@@ -0,0 +1,24 @@
+package models
+
+// OracleSkipReason names why the oracle check skipped an iteration.
+type OracleSkipReason string
+
+const (
+	OracleSkipDeviation    OracleSkipReason = "deviation"
+	OracleSkipStaleTicker  OracleSkipReason = "stale_ticker"
+	OracleSkipStaleOracle  OracleSkipReason = "stale_oracle_ticker"
+	OracleSkipNotConverged OracleSkipReason = "not_converged"
+)
+
+// OracleCheck compares the last price of the venue with the oracle price, and the age of their tickers, before trading.
+type OracleCheck struct {
+	LastPrice   float64 `json:"lastPrice"`
+	OraclePrice float64 `json:"oraclePrice"`
+	// Deviation is the distance of the last price from the oracle price, relative to the oracle price.
+	Deviation float64 `json:"deviation"`
+	// TickerAge and OracleTickerAge are zero when the exchanges don't report the time of their tickers.
+	TickerAge       Duration         `json:"tickerAge" swaggertype:"string" example:"1s"`
+	OracleTickerAge Duration         `json:"oracleTickerAge" swaggertype:"string" example:"1s"`
+	Halted          bool             `json:"halted"`
+	SkipReason      OracleSkipReason `json:"skipReason,omitempty"`
+}
@@ -1,5 +1,7 @@
 package models
 
+import "fmt"
+
 type OrderAction string
 
 const (
@@ -7,9 +9,104 @@ const (
 	Sell OrderAction = "sell"
 )
 
+type OrderStatus string
+
+const (
+	OrderStatusNew             OrderStatus = "new"
+	OrderStatusPartiallyFilled OrderStatus = "partially_filled"
+	OrderStatusFilled          OrderStatus = "filled"
+	OrderStatusCancelled       OrderStatus = "cancelled"
+	OrderStatusRejected        OrderStatus = "rejected"
+	OrderStatusUnknown         OrderStatus = "unknown"
+)
+
+// OrderType is mapped by every exchange client into its native order type, or fails with
+// exchangeErrors.ErrUnsupported when the exchange doesn't have it.
+type OrderType string
+
+const (
+	OrderTypeLimit OrderType = "limit"
+	// OrderTypeMarket is filled at the book prices, its price is ignored and its qty is in base currency.
+	OrderTypeMarket OrderType = "market"
+	// OrderTypeLimitMaker is a post only limit order, which is cancelled instead of taking liquidity.
+	OrderTypeLimitMaker OrderType = "limit_maker"
+)
+
+// TimeInForce is how long an order stays open before its unfilled qty is cancelled.
+type TimeInForce string
+
+const (
+	// TimeInForceGTC rests until the order is filled or cancelled.
+	TimeInForceGTC TimeInForce = "gtc"
+	// TimeInForceIOC fills what it can right away, and cancels the rest.
+	TimeInForceIOC TimeInForce = "ioc"
+	// TimeInForceFOK is either filled in full right away, or cancelled.
+	TimeInForceFOK TimeInForce = "fok"
+)
+
 type Order struct {
 	Symbol string
 	Price  string
 	Qty    string
 	Action OrderAction
+	// Type and TimeInForce default to a GTC limit order, and to IOC for a market order.
+	Type        OrderType
+	TimeInForce TimeInForce
+	// ClientOrderID is sent along with the order, so that the exchange rejects it when it's placed twice, and it can
+	// be found by GetOrderByClientID when its placement failed without telling whether it was placed.
+	ClientOrderID string
+}
+
+// Kind returns the type and time in force of the order, resolving their defaults.
+func (o *Order) Kind() (OrderType, TimeInForce) {
+	orderType := OrderTypeLimit
+	if o.Type != "" {
+		orderType = o.Type
+	}
+	timeInForce := TimeInForceGTC
+	switch {
+	case o.TimeInForce != "":
+		timeInForce = o.TimeInForce
+	case orderType == OrderTypeMarket:
+		timeInForce = TimeInForceIOC
+	}
+	return orderType, timeInForce
+}
+
+// ValidateOrderKind fails the order types and times in force which no exchange has, like a post only IOC order.
+func ValidateOrderKind(orderType OrderType, timeInForce TimeInForce) error {
+	switch orderType {
+	case OrderTypeLimit, OrderTypeMarket, OrderTypeLimitMaker:
+	default:
+		return fmt.Errorf("invalid order type: %s, expected %s, %s or %s", orderType, OrderTypeLimit, OrderTypeMarket, OrderTypeLimitMaker)
+	}
+	switch timeInForce {
+	case TimeInForceGTC, TimeInForceIOC, TimeInForceFOK:
+	default:
+		return fmt.Errorf("invalid time in force: %s, expected %s, %s or %s", timeInForce, TimeInForceGTC, TimeInForceIOC, TimeInForceFOK)
+	}
+	switch {
+	case orderType == OrderTypeMarket && timeInForce == TimeInForceGTC:
+		return fmt.Errorf("invalid time in force of a market order: %s", timeInForce)
+	case orderType == OrderTypeLimitMaker && timeInForce != TimeInForceGTC:
+		return fmt.Errorf("invalid time in force of a limit maker order: %s", timeInForce)
+	}
+	return nil
+}
+
+// OrderRecord is the exchange agnostic view of an order as reported by the exchange.
+type OrderRecord struct {
+	Symbol        string      `json:"symbol"`
+	OrderID       string      `json:"orderId"`
+	ClientOrderID string      `json:"clientOrderId"`
+	Action        OrderAction `json:"action"`
+	Price         string      `json:"price"`
+	Qty           string      `json:"qty"`
+	FilledQty     string      `json:"filledQty"`
+	AvgPrice      string      `json:"avgPrice"`
+	Status        OrderStatus `json:"status"`
+}
+
+func (o *OrderRecord) IsOpen() bool {
+	return o.Status == OrderStatusNew || o.Status == OrderStatusPartiallyFilled
 }
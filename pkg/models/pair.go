@@ -0,0 +1,28 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PairSeparator separates the base and quote of a canonical symbol.
+const PairSeparator = "/"
+
+// Pair is the canonical, exchange agnostic symbol of a market, written BASE/QUOTE.
+type Pair struct {
+	Base  string `json:"base"`
+	Quote string `json:"quote"`
+}
+
+// ParsePair parses a canonical BASE/QUOTE symbol.
+func ParsePair(symbol string) (Pair, error) {
+	base, quote, found := strings.Cut(strings.ToUpper(strings.TrimSpace(symbol)), PairSeparator)
+	if !found || base == "" || quote == "" || strings.Contains(quote, PairSeparator) {
+		return Pair{}, fmt.Errorf("invalid symbol: %s, expected BASE/QUOTE", symbol)
+	}
+	return Pair{Base: base, Quote: quote}, nil
+}
+
+func (p Pair) String() string {
+	return p.Base + PairSeparator + p.Quote
+}
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+type PaperAccount struct {
+	Symbol      string  `json:"symbol"`
+	Base        float64 `json:"base"`
+	Quote       float64 `json:"quote"`
+	LockedBase  float64 `json:"lockedBase"`
+	LockedQuote float64 `json:"lockedQuote"`
+	FeesPaid    float64 `json:"feesPaid"`
+	// Fills and FilledVolume, in base currency, count every fill of the account, the fills of the state are only the
+	// latest ones.
+	Fills        int     `json:"fills"`
+	FilledVolume float64 `json:"filledVolume"`
+}
+
+type PaperFill struct {
+	TradeID string      `json:"tradeId"`
+	OrderID string      `json:"orderId"`
+	Symbol  string      `json:"symbol"`
+	Action  OrderAction `json:"action"`
+	Price   float64     `json:"price"`
+	Qty     float64     `json:"qty"`
+	Fee     float64     `json:"fee"`
+	Maker   bool        `json:"maker"`
+	Time    time.Time   `json:"time"`
+}
+
+type PaperState struct {
+	Accounts   []PaperAccount `json:"accounts"`
+	OpenOrders []*OrderRecord `json:"openOrders"`
+	Fills      []PaperFill    `json:"fills"`
+}
@@ -0,0 +1,122 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/imbonda/vmm-bot/pkg/utils"
+)
+
+var ErrInvalidParams = fmt.Errorf("invalid params")
+
+// Duration is a time.Duration written in JSON as a duration string, such as 30s.
+type Duration time.Duration
+
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return err
+	}
+	*d = Duration(duration)
+	return nil
+}
+
+// TradeParams are the trading parameters of a pair which can be updated while it trades.
+type TradeParams struct {
+	CandleHeight      float64 `json:"candleHeight"`
+	SpreadMarginLower float64 `json:"spreadMarginLower"`
+	SpreadMarginUpper float64 `json:"spreadMarginUpper"`
+	TradeAmountMin    float64 `json:"tradeAmountMin"`
+	TradeAmountMax    float64 `json:"tradeAmountMax"`
+}
+
+func (p TradeParams) Validate() error {
+	switch {
+	case p.CandleHeight <= 0:
+		return fmt.Errorf("%w: candleHeight must be positive", ErrInvalidParams)
+	case p.SpreadMarginLower < 0 || p.SpreadMarginUpper < p.SpreadMarginLower:
+		return fmt.Errorf("%w: expected 0 <= spreadMarginLower <= spreadMarginUpper", ErrInvalidParams)
+	case p.TradeAmountMin <= 0 || p.TradeAmountMax < p.TradeAmountMin:
+		return fmt.Errorf("%w: tradeAmountMin must be positive and not above tradeAmountMax", ErrInvalidParams)
+	}
+	return nil
+}
+
+// ScheduleParams are the interval of a pair and the number of iterations run within it.
+type ScheduleParams struct {
+	IntervalExecutionDuration      Duration `json:"intervalExecutionDuration" swaggertype:"string" example:"60s"`
+	NumOfTradeIterationsInInterval int      `json:"numOfTradeIterationsInInterval"`
+}
+
+func (p ScheduleParams) Validate() error {
+	if time.Duration(p.IntervalExecutionDuration) < utils.MinIntervalDuration || p.NumOfTradeIterationsInInterval <= 0 {
+		return fmt.Errorf(
+			"%w: intervalExecutionDuration must be at least %s and numOfTradeIterationsInInterval positive",
+			ErrInvalidParams,
+			utils.MinIntervalDuration,
+		)
+	}
+	return nil
+}
+
+// PairParams are the parameters of a pair which can be updated while it trades, pairs traded on request have no schedule.
+type PairParams struct {
+	Trade    TradeParams     `json:"trade"`
+	Schedule *ScheduleParams `json:"schedule,omitempty"`
+}
+
+func (p *PairParams) Clone() *PairParams {
+	clone := *p
+	if p.Schedule != nil {
+		schedule := *p.Schedule
+		clone.Schedule = &schedule
+	}
+	return &clone
+}
+
+func (p *PairParams) Validate() error {
+	if err := p.Trade.Validate(); err != nil {
+		return err
+	}
+	if p.Schedule != nil {
+		return p.Schedule.Validate()
+	}
+	return nil
+}
+
+// ParamsChange is an update of the parameters of a pair.
+type ParamsChange struct {
+	Pair     string      `json:"pair"`
+	Previous *PairParams `json:"previous"`
+	Current  *PairParams `json:"current"`
+}
+
+// DiffParams describes the changed fields of two parameter structs of the same type, such as "candleHeight: 0.01 -> 0.02".
+func DiffParams[P TradeParams | ScheduleParams](previous, current P) string {
+	previousValue, currentValue := reflect.ValueOf(previous), reflect.ValueOf(current)
+	var changes []string
+	for i := range previousValue.NumField() {
+		before, after := previousValue.Field(i).Interface(), currentValue.Field(i).Interface()
+		if before == after {
+			continue
+		}
+		name, _, _ := strings.Cut(previousValue.Type().Field(i).Tag.Get("json"), ",")
+		changes = append(changes, fmt.Sprintf("%s: %v -> %v", name, before, after))
+	}
+	return strings.Join(changes, ", ")
+}
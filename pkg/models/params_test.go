@@ -0,0 +1,33 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduleParamsValidate(t *testing.T) {
+	tests := []struct {
+		interval   time.Duration
+		iterations int
+		valid      bool
+	}{
+		{interval: time.Minute, iterations: 3, valid: true},
+		{interval: time.Millisecond, iterations: 1, valid: true},
+		{interval: 500 * time.Microsecond, iterations: 1},
+		{interval: 0, iterations: 1},
+		{interval: time.Minute, iterations: 0},
+	}
+	for _, test := range tests {
+		err := ScheduleParams{
+			IntervalExecutionDuration:      Duration(test.interval),
+			NumOfTradeIterationsInInterval: test.iterations,
+		}.Validate()
+		if test.valid {
+			assert.NoError(t, err, test.interval.String())
+		} else {
+			assert.ErrorIs(t, err, ErrInvalidParams, test.interval.String())
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package models
+
+// PriceBranch names the rule that selected the price range in the trader's pricing logic.
+type PriceBranch string
+
+const (
+	BranchOracleRangeInMargin PriceBranch = "oracle_range_in_margin"
+	BranchOracleLowerInMargin PriceBranch = "oracle_lower_in_margin"
+	BranchOracleUpperInMargin PriceBranch = "oracle_upper_in_margin"
+	BranchCandleRangeInMargin PriceBranch = "candle_range_in_margin"
+	BranchCandleLowerInMargin PriceBranch = "candle_lower_in_margin"
+	BranchCandleUpperInMargin PriceBranch = "candle_upper_in_margin"
+	BranchOracleRangeInSpread PriceBranch = "oracle_range_in_spread"
+	BranchOracleLowerInSpread PriceBranch = "oracle_lower_in_spread"
+	BranchOracleUpperInSpread PriceBranch = "oracle_upper_in_spread"
+	BranchCandleRangeInSpread PriceBranch = "candle_range_in_spread"
+	BranchCandleLowerInSpread PriceBranch = "candle_lower_in_spread"
+	BranchCandleUpperInSpread PriceBranch = "candle_upper_in_spread"
+	BranchSpreadAboveOracle   PriceBranch = "spread_above_oracle"
+	BranchSpreadBelowOracle   PriceBranch = "spread_below_oracle"
+	BranchSpread              PriceBranch = "spread"
+)
+
+// PriceDecision describes how the trade price of an iteration was chosen.
+type PriceDecision struct {
+	Branch      PriceBranch `json:"branch"`
+	Price       float64     `json:"price"`
+	Min         float64     `json:"min"`
+	Max         float64     `json:"max"`
+	LastPrice   float64     `json:"lastPrice"`
+	OraclePrice float64     `json:"oraclePrice"`
+	Ask         float64     `json:"ask"`
+	Bid         float64     `json:"bid"`
+	Crossed     bool        `json:"crossed"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// RiskState is the persisted risk state of a pair, the daily volume and fees are reset on the first order of a UTC day.
+type RiskState struct {
+	Halted   bool       `json:"halted"`
+	Reason   string     `json:"reason,omitempty"`
+	HaltedAt *time.Time `json:"haltedAt,omitempty"`
+	// Day is the UTC date of the daily totals, which are in quote currency. Volume is the notional of the orders placed
+	// within the day, and FilledVolume the notional of its fills, which also counts the orders placed by others.
+	Day          string  `json:"day"`
+	Volume       float64 `json:"volume"`
+	FilledVolume float64 `json:"filledVolume"`
+	Fees         float64 `json:"fees"`
+	// FillsSyncedAt is the time of the last fill counted in the daily totals, and SyncedTradeIDs the trade ids of the
+	// fills of that time, so that the next fills are fetched from it without counting them twice.
+	FillsSyncedAt  *time.Time `json:"fillsSyncedAt,omitempty"`
+	SyncedTradeIDs []string   `json:"syncedTradeIds,omitempty"`
+}
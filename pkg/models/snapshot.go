@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// MarketDataSource tells whether recorded market data belongs to the traded venue or to the price oracle.
+type MarketDataSource string
+
+const (
+	VenueSource  MarketDataSource = "venue"
+	OracleSource MarketDataSource = "oracle"
+)
+
+type TickerSnapshot struct {
+	Time   time.Time        `json:"ts"`
+	Source MarketDataSource `json:"source"`
+	Ticker *Ticker          `json:"ticker"`
+}
+
+type OrderBookSnapshot struct {
+	Time      time.Time        `json:"ts"`
+	Source    MarketDataSource `json:"source"`
+	OrderBook *OrderBook       `json:"orderBook"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// PairStatus is the state of a traded pair.
+type PairStatus struct {
+	Pair   string      `json:"pair"`
+	Symbol string      `json:"symbol"`
+	Paused bool        `json:"paused"`
+	Params *PairParams `json:"params"`
+	// LastIteration is the end time of the last iteration, and LastError its error.
+	LastIteration *time.Time     `json:"lastIteration,omitempty"`
+	LastError     string         `json:"lastError,omitempty"`
+	OpenOrders    []*OrderRecord `json:"openOrders"`
+	Risk          *RiskState     `json:"risk,omitempty"`
+	// Circuits are the circuit breakers of the exchanges the pair trades on and takes prices from. The open orders are
+	// omitted while the circuit of the exchange is open.
+	Circuits []CircuitStatus `json:"circuits,omitempty"`
+}
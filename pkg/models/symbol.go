@@ -0,0 +1,53 @@
+package models
+
+import (
+	"fmt"
+
+	"github.com/imbonda/vmm-bot/pkg/utils"
+)
+
+type SymbolStatus string
+
+const (
+	SymbolStatusTrading SymbolStatus = "trading"
+	SymbolStatusHalted  SymbolStatus = "halted"
+)
+
+// SymbolInfo holds the trading rules of a symbol, rules an exchange doesn't report are left empty.
+type SymbolInfo struct {
+	Symbol string       `json:"symbol"`
+	Status SymbolStatus `json:"status"`
+	// TickSize is the price increment.
+	TickSize string `json:"tickSize"`
+	// LotStep is the qty increment.
+	LotStep     string `json:"lotStep"`
+	MinQty      string `json:"minQty"`
+	MinNotional string `json:"minNotional"`
+}
+
+func (s *SymbolInfo) IsTrading() bool {
+	return s.Status == SymbolStatusTrading
+}
+
+// Rules parses the trading rules, a zero value stands for a rule the exchange doesn't report.
+func (s *SymbolInfo) Rules() (tickSize, lotStep, minQty, minNotional float64, err error) {
+	fields := []struct {
+		name  string
+		value string
+		dest  *float64
+	}{
+		{"tick size", s.TickSize, &tickSize},
+		{"lot step", s.LotStep, &lotStep},
+		{"min qty", s.MinQty, &minQty},
+		{"min notional", s.MinNotional, &minNotional},
+	}
+	for _, field := range fields {
+		if field.value == "" {
+			continue
+		}
+		if *field.dest, err = utils.ParseFloat(field.value); err != nil {
+			return 0, 0, 0, 0, fmt.Errorf("failed parse %s %s: %s", s.Symbol, field.name, field.value)
+		}
+	}
+	return tickSize, lotStep, minQty, minNotional, nil
+}
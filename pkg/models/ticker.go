@@ -2,6 +2,7 @@ package models
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/imbonda/vmm-bot/pkg/utils"
 )
@@ -11,6 +12,10 @@ type Ticker struct {
 	LastPrice string `json:"lastPrice"`
 	BestAsk   string `json:"ask"`
 	BestBid   string `json:"bid"`
+	// Volume is the base volume of the last 24 hours, empty when the exchange doesn't report it.
+	Volume string `json:"volume,omitempty"`
+	// Time is when the exchange reported the quote, zero when it doesn't.
+	Time time.Time `json:"-"`
 }
 
 func (t *Ticker) Spread() (*Spread, error) {
@@ -1,4 +1,23 @@
 package models
 
 type TradeOnceOutput struct {
+	Orders    []*OrderRecord `json:"orders"`
+	Pricing   *PriceDecision `json:"pricing"`
+	Inventory *InventorySkew `json:"inventory,omitempty"`
+	Funding   *FundingCheck  `json:"funding,omitempty"`
+	Oracle    *OracleCheck   `json:"oracle,omitempty"`
+}
+
+// FundingCheck describes how the per side qty was checked against the free balances,
+// the sell order is funded in base and the buy order in quote currency.
+type FundingCheck struct {
+	Base             string  `json:"base"`
+	Quote            string  `json:"quote"`
+	FreeBase         float64 `json:"freeBase"`
+	FreeQuote        float64 `json:"freeQuote"`
+	RequestedSellQty float64 `json:"requestedSellQty"`
+	RequestedBuyQty  float64 `json:"requestedBuyQty"`
+	FundedSellQty    float64 `json:"fundedSellQty"`
+	FundedBuyQty     float64 `json:"fundedBuyQty"`
+	Shrunk           bool    `json:"shrunk"`
 }
@@ -21,3 +21,7 @@ func FormatFloatToString(value float64, precision int) string {
 func ParseFloat(str string) (float64, error) {
 	return strconv.ParseFloat(str, 64)
 }
+
+func ParseInt(str string) (int, error) {
+	return strconv.Atoi(str)
+}
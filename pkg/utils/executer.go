@@ -3,6 +3,7 @@ package utils
 import (
 	"context"
 	"runtime/debug"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -19,6 +20,8 @@ type IterationsExecutor[I iterable] struct {
 	callee       I
 	logger       log.Logger
 	lastRunEpoch atomic.Uint64
+	lastErrMu    sync.Mutex
+	lastErr      error
 }
 
 type NewIterationsExecutorInput[I iterable] struct {
@@ -59,6 +62,39 @@ func (ie *IterationsExecutor[I]) Shutdown(ctx context.Context) error {
 	return nil
 }
 
+// LastRun returns the end time of the last iteration, zero before the first one.
+func (ie *IterationsExecutor[I]) LastRun() time.Time {
+	epoch := ie.lastRunEpoch.Load()
+	if epoch == 0 {
+		return time.Time{}
+	}
+	return time.Unix(int64(epoch), 0)
+}
+
+// LastError returns the error of the last iteration, nil when it succeeded.
+func (ie *IterationsExecutor[I]) LastError() error {
+	ie.lastErrMu.Lock()
+	defer ie.lastErrMu.Unlock()
+	return ie.lastErr
+}
+
+// Running reports whether the scheduler still runs the iterations, it stops when shut down.
+func (ie *IterationsExecutor[I]) Running() bool {
+	return ie.scheduler.Running()
+}
+
+func (ie *IterationsExecutor[I]) Schedule() Schedule {
+	return ie.scheduler.Schedule()
+}
+
+func (ie *IterationsExecutor[I]) UpdateSchedule(schedule Schedule) (Schedule, error) {
+	return ie.scheduler.UpdateSchedule(schedule)
+}
+
+func (ie *IterationsExecutor[I]) RollbackSchedule() (Schedule, error) {
+	return ie.scheduler.RollbackSchedule()
+}
+
 func (ie *IterationsExecutor[I]) doIteration(ctx context.Context) {
 	defer func() {
 		if r := recover(); r != nil {
@@ -69,7 +105,11 @@ func (ie *IterationsExecutor[I]) doIteration(ctx context.Context) {
 	}()
 
 	level.Debug(ie.logger).Log("msg", "starting trade iteration")
-	if err := ie.callee.DoIteration(ctx); err != nil {
+	err := ie.callee.DoIteration(ctx)
+	ie.lastErrMu.Lock()
+	ie.lastErr = err
+	ie.lastErrMu.Unlock()
+	if err != nil {
 		ie.logger.Log("msg", "failed to iterate", "err", err)
 	} else {
 		level.Debug(ie.logger).Log("msg", "trade iteration is done")
@@ -5,4 +5,5 @@ type Orchestration string
 const (
 	HTTP     Orchestration = "http"
 	Executor Orchestration = "executor"
+	Recorder Orchestration = "recorder"
 )
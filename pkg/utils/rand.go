@@ -11,6 +11,10 @@ var r = rand.New(rand.NewSource(time.Now().UnixNano()))
 
 // Generate random number in the full open range (min, max)
 func RandInRange(min, max float64) float64 {
+	// The open range is empty.
+	if max <= min {
+		return min
+	}
 again:
 	val := min + r.Float64()*(max-min)
 	if val == min || val == max {
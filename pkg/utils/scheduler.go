@@ -2,20 +2,40 @@ package utils
 
 import (
 	"context"
+	"fmt"
 	"math/rand"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
 )
 
+// MinIntervalDuration is the shortest interval of a schedule, the tasks are spread over it in whole milliseconds.
+const MinIntervalDuration = time.Millisecond
+
+var (
+	ErrInvalidSchedule    = fmt.Errorf("invalid schedule")
+	ErrNoPreviousSchedule = fmt.Errorf("no previous schedule")
+)
+
+// Schedule is the interval of a scheduler and the number of tasks run within every interval.
+type Schedule struct {
+	IntervalDuration   time.Duration
+	NumTasksInInterval int
+}
+
 type Scheduler struct {
-	intervalDuration   time.Duration
-	numTasksInInterval int
-	task               func(context.Context)
-	logger             log.Logger
-	taskChan           chan struct{}
-	stopChan           chan struct{}
+	mu               sync.Mutex
+	schedule         Schedule
+	previousSchedule *Schedule
+	task             func(context.Context)
+	logger           log.Logger
+	taskChan         chan struct{}
+	stopChan         chan struct{}
+	// loops counts the running run loops, a stopped loop may still be returning when the scheduler is run again.
+	loops atomic.Int32
 }
 
 type NewSchedulerInput struct {
@@ -27,12 +47,14 @@ type NewSchedulerInput struct {
 
 func NewScheduler(input *NewSchedulerInput) *Scheduler {
 	return &Scheduler{
-		intervalDuration:   input.IntervalDuration,
-		numTasksInInterval: input.NumTasksInInterval,
-		task:               input.Task,
-		logger:             input.Logger,
-		taskChan:           make(chan struct{}),
-		stopChan:           make(chan struct{}),
+		schedule: Schedule{
+			IntervalDuration:   input.IntervalDuration,
+			NumTasksInInterval: input.NumTasksInInterval,
+		},
+		task:     input.Task,
+		logger:   input.Logger,
+		taskChan: make(chan struct{}),
+		stopChan: make(chan struct{}),
 	}
 }
 
@@ -40,27 +62,94 @@ func (s *Scheduler) SetTask(task func(context.Context)) {
 	s.task = task
 }
 
+// Schedule returns the schedule of the next intervals.
+func (s *Scheduler) Schedule() Schedule {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.schedule
+}
+
+// UpdateSchedule replaces the schedule from the next interval on, returning the replaced one which is kept for RollbackSchedule.
+func (s *Scheduler) UpdateSchedule(schedule Schedule) (Schedule, error) {
+	if schedule.IntervalDuration < MinIntervalDuration || schedule.NumTasksInInterval <= 0 {
+		return Schedule{}, fmt.Errorf(
+			"%w, expected an interval of at least %s and a positive number of tasks. interval: %s, tasks: %d",
+			ErrInvalidSchedule,
+			MinIntervalDuration,
+			schedule.IntervalDuration,
+			schedule.NumTasksInInterval,
+		)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	previous := s.schedule
+	s.schedule, s.previousSchedule = schedule, &previous
+	s.logChange("updated schedule", previous)
+	return previous, nil
+}
+
+// RollbackSchedule restores and returns the schedule replaced by the last update, rolling back twice restores the update.
+func (s *Scheduler) RollbackSchedule() (Schedule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.previousSchedule == nil {
+		return Schedule{}, ErrNoPreviousSchedule
+	}
+	previous := s.schedule
+	s.schedule, s.previousSchedule = *s.previousSchedule, &previous
+	s.logChange("rolled back schedule", previous)
+	return s.schedule, nil
+}
+
+func (s *Scheduler) logChange(msg string, previous Schedule) {
+	level.Info(s.logger).Log(
+		"msg", msg,
+		"previousInterval", previous.IntervalDuration,
+		"previousTasks", previous.NumTasksInInterval,
+		"interval", s.schedule.IntervalDuration,
+		"tasks", s.schedule.NumTasksInInterval,
+	)
+}
+
 func (s *Scheduler) Stop(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	close(s.stopChan)
 }
 
+// Run starts the run loop, a stopped scheduler can be run again.
 func (s *Scheduler) Run(ctx context.Context) {
-	go s.run(ctx)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	select {
+	case <-s.stopChan:
+		s.stopChan = make(chan struct{})
+	default:
+	}
+	s.loops.Add(1)
+	go s.run(ctx, s.stopChan)
+}
+
+// Running reports whether the run loop is running.
+func (s *Scheduler) Running() bool {
+	return s.loops.Load() > 0
 }
 
-func (s *Scheduler) run(ctx context.Context) {
+func (s *Scheduler) run(ctx context.Context, stopChan chan struct{}) {
+	defer s.loops.Add(-1)
 	for {
 		level.Info(s.logger).Log("msg", "starting run interval")
 		start := time.Now()
+		schedule := s.Schedule()
 		// Schedule operations randomly within interval
-		for range s.numTasksInInterval {
-			go s.scheduleTask()
+		for range schedule.NumTasksInInterval {
+			go s.scheduleTask(stopChan, schedule.IntervalDuration)
 		}
 
 		// Consume tasks sequentially
-		for range s.numTasksInInterval {
+		for range schedule.NumTasksInInterval {
 			select {
-			case <-s.stopChan:
+			case <-stopChan:
 				level.Info(s.logger).Log("msg", "stopped run loop")
 				return
 			case <-s.taskChan:
@@ -72,10 +161,10 @@ func (s *Scheduler) run(ctx context.Context) {
 
 		// Measure how long it took to schedule
 		elapsed := time.Since(start)
-		remaining := s.intervalDuration - elapsed
+		remaining := schedule.IntervalDuration - elapsed
 		if remaining > 0 {
 			select {
-			case <-s.stopChan:
+			case <-stopChan:
 				level.Info(s.logger).Log("msg", "stopped run loop")
 				return
 			case <-time.After(remaining):
@@ -85,13 +174,19 @@ func (s *Scheduler) run(ctx context.Context) {
 	}
 }
 
-func (s *Scheduler) scheduleTask() {
-	delay := time.Duration(rand.Int63n(int64(s.intervalDuration.Milliseconds()))) * time.Millisecond
+func (s *Scheduler) scheduleTask(stopChan chan struct{}, intervalDuration time.Duration) {
+	var delay time.Duration
+	if interval := intervalDuration.Milliseconds(); interval > 0 {
+		delay = time.Duration(rand.Int63n(interval)) * time.Millisecond
+	}
 	level.Debug(s.logger).Log("msg", "got random sleep time", "sleepTime", delay.Seconds())
 	select {
-	case <-s.stopChan:
+	case <-stopChan:
 		return
 	case <-time.After(delay):
-		s.taskChan <- struct{}{}
+		select {
+		case <-stopChan:
+		case s.taskChan <- struct{}{}:
+		}
 	}
 }
@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateScheduleRejectsIntervalsBelowMin(t *testing.T) {
+	scheduler := NewScheduler(&NewSchedulerInput{
+		IntervalDuration:   time.Second,
+		NumTasksInInterval: 1,
+		Task:               func(context.Context) {},
+		Logger:             log.NewNopLogger(),
+	})
+	for _, interval := range []time.Duration{0, -time.Second, 500 * time.Microsecond} {
+		_, err := scheduler.UpdateSchedule(Schedule{IntervalDuration: interval, NumTasksInInterval: 1})
+		assert.ErrorIs(t, err, ErrInvalidSchedule, interval.String())
+	}
+	_, err := scheduler.UpdateSchedule(Schedule{IntervalDuration: MinIntervalDuration, NumTasksInInterval: 1})
+	require.NoError(t, err)
+	assert.Equal(t, MinIntervalDuration, scheduler.Schedule().IntervalDuration)
+}
+
+func TestScheduleTaskWithinSubMillisecondInterval(t *testing.T) {
+	scheduler := NewScheduler(&NewSchedulerInput{Logger: log.NewNopLogger()})
+	stopChan := make(chan struct{})
+	defer close(stopChan)
+	go scheduler.scheduleTask(stopChan, 500*time.Microsecond)
+	select {
+	case <-scheduler.taskChan:
+	case <-time.After(time.Second):
+		t.Fatal("task wasn't scheduled")
+	}
+}
@@ -0,0 +1,48 @@
+package utils
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// stepEpsilon absorbs the float error of values which already are a multiple of the step, e.g. 14.98 / 0.01.
+const stepEpsilon = 1e-9
+
+// RoundToStep rounds the value to the nearest multiple of the step, a non positive step leaves it as is.
+func RoundToStep(value, step float64) float64 {
+	if step <= 0 {
+		return value
+	}
+	return math.Round(value/step) * step
+}
+
+// FloorToStep rounds the value down to a multiple of the step, a non positive step leaves it as is.
+func FloorToStep(value, step float64) float64 {
+	if step <= 0 {
+		return value
+	}
+	return math.Floor(value/step+stepEpsilon) * step
+}
+
+// CeilToStep rounds the value up to a multiple of the step, a non positive step leaves it as is.
+func CeilToStep(value, step float64) float64 {
+	if step <= 0 {
+		return value
+	}
+	return math.Ceil(value/step-stepEpsilon) * step
+}
+
+// StepDecimals returns the number of decimals needed to format multiples of the step, e.g. 3 for 0.005.
+func StepDecimals(step float64) int {
+	formatted := strconv.FormatFloat(step, 'f', -1, 64)
+	if _, decimals, found := strings.Cut(formatted, "."); found {
+		return len(decimals)
+	}
+	return 0
+}
+
+// DecimalsStep returns the step of the given number of decimals, e.g. 0.01 for 2.
+func DecimalsStep(decimals int) float64 {
+	return math.Pow(10, -float64(decimals))
+}
@@ -0,0 +1,11 @@
+package utils
+
+import "time"
+
+// UnixMilliTime converts a unix time in milliseconds, zero being an unknown time.
+func UnixMilliTime(millis int64) time.Time {
+	if millis <= 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(millis)
+}